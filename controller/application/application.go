@@ -1,12 +1,14 @@
 package application
 
 import (
+	"errors"
 	"github.com/gin-gonic/gin"
 	"github.com/google/uuid"
 	"net/http"
+	"onepenny-server/internal/pagination"
 	"onepenny-server/internal/repository"
 	"onepenny-server/internal/service"
-	"strconv"
+	"onepenny-server/model/dao"
 	"time"
 )
 
@@ -59,6 +61,7 @@ type ErrorResponse struct {
 // @Success     201 {object} ApplicationResponse      "申请提交成功"
 // @Failure     400 {object} ErrorResponse            "参数格式错误"
 // @Failure     401 {object} ErrorResponse            "未授权"
+// @Failure     404 {object} ErrorResponse            "未找到赏金任务或无权查看"
 // @Failure     500 {object} ErrorResponse            "服务器内部错误"
 // @Router      /api/applications [post]
 func (ctl *ApplicationController) Submit(c *gin.Context) {
@@ -93,7 +96,7 @@ func (ctl *ApplicationController) Submit(c *gin.Context) {
 	}
 	app, err := ctl.svc.SubmitApplication(input)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, ErrorResponse{Error: err.Error()})
+		handleError(c, err)
 		return
 	}
 
@@ -130,7 +133,10 @@ func (ctl *ApplicationController) Get(c *gin.Context) {
 		return
 	}
 
-	app, err := ctl.svc.GetApplication(appID)
+	raw, _ := c.Get("userID")
+	userID := raw.(uuid.UUID)
+
+	app, err := ctl.svc.GetApplication(appID, userID)
 	if err != nil {
 		c.JSON(http.StatusNotFound, ErrorResponse{Error: err.Error()})
 		return
@@ -154,9 +160,10 @@ func (ctl *ApplicationController) Get(c *gin.Context) {
 // @Tags        application
 // @Security    BearerAuth
 // @Produce     json
-// @Param       page  query     int false "页码"   default(1)
-// @Param       size  query     int false "每页大小" default(20)
-// @Success     200   {array}   ApplicationResponse    "申请列表"
+// @Param       cursor     query string false "上一页返回的 next_cursor，为空表示第一页"
+// @Param       size       query int    false "每页大小" default(20)
+// @Param       with_total query bool   false "是否返回总数"
+// @Success     200   {object}  pagination.Result[ApplicationResponse]    "申请列表"
 // @Failure     401   {object}  ErrorResponse           "未授权"
 // @Failure     500   {object}  ErrorResponse           "服务器内部错误"
 // @Router      /api/applications [get]
@@ -172,29 +179,20 @@ func (ctl *ApplicationController) ListByUser(c *gin.Context) {
 		return
 	}
 
-	page := 1
-	size := 20
-	if p := c.Query("page"); p != "" {
-		if v, err := strconv.Atoi(p); err == nil && v > 0 {
-			page = v
-		}
-	}
-	if s := c.Query("size"); s != "" {
-		if v, err := strconv.Atoi(s); err == nil && v > 0 {
-			size = v
-		}
+	page, err := pagination.FromQuery(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: err.Error()})
+		return
 	}
 
-	list, err := ctl.svc.ListByUser(userID, page, size)
+	list, err := ctl.svc.ListByUser(userID, page)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, ErrorResponse{Error: err.Error()})
 		return
 	}
 
-	// 构造响应
-	resp := make([]ApplicationResponse, len(list))
-	for i, app := range list {
-		resp[i] = ApplicationResponse{
+	c.JSON(http.StatusOK, pagination.Map(list, func(app *dao.Application) ApplicationResponse {
+		return ApplicationResponse{
 			ID:             app.ID,
 			BountyID:       app.BountyID,
 			UserID:         app.UserID,
@@ -203,9 +201,7 @@ func (ctl *ApplicationController) ListByUser(c *gin.Context) {
 			CreatedAt:      app.CreatedAt,
 			UpdatedAt:      app.UpdatedAt,
 		}
-	}
-
-	c.JSON(http.StatusOK, resp)
+	}))
 }
 
 // Delete godoc
@@ -241,19 +237,20 @@ func (ctl *ApplicationController) Delete(c *gin.Context) {
 // @Security    BearerAuth
 // @Accept      json
 // @Produce     json
-// @Param       application_id path     string          true  "申请 ID"
-// @Param       req            body     decisionRequest true  "批准理由"
-// @Success     200            {object} service.ApplicationDTO
-// @Failure     400            {object} ErrorResponse
-// @Failure     401            {object} ErrorResponse
-// @Failure     403            {object} ErrorResponse   "无权限"
-// @Failure     500            {object} ErrorResponse
-// @Router      /api/applications/{application_id}/approve [put]
+// @Param       id  path     string          true  "申请 ID"
+// @Param       req body     decisionRequest true  "批准理由"
+// @Success     200 {object} service.ApplicationDTO
+// @Failure     400 {object} ErrorResponse
+// @Failure     401 {object} ErrorResponse
+// @Failure     403 {object} ErrorResponse   "无权限"
+// @Failure     409 {object} ErrorResponse   "悬赏令当前状态不允许接受申请"
+// @Failure     500 {object} ErrorResponse
+// @Router      /api/applications/{id}/approve [put]
 func (ctl *ApplicationController) Approve(c *gin.Context) {
 	raw, _ := c.Get("userID")
 	ownerID := raw.(uuid.UUID)
 
-	appID, err := uuid.Parse(c.Param("application_id"))
+	appID, err := uuid.Parse(c.Param("id"))
 	if err != nil {
 		c.JSON(http.StatusBadRequest, ErrorResponse{Error: "invalid application_id"})
 		return
@@ -284,19 +281,19 @@ func (ctl *ApplicationController) Approve(c *gin.Context) {
 // @Security    BearerAuth
 // @Accept      json
 // @Produce     json
-// @Param       application_id path     string          true  "申请 ID"
-// @Param       req            body     decisionRequest true  "拒绝理由"
-// @Success     200            {object} service.ApplicationDTO
-// @Failure     400            {object} ErrorResponse
-// @Failure     401            {object} ErrorResponse
-// @Failure     403            {object} ErrorResponse
-// @Failure     500            {object} ErrorResponse
-// @Router      /api/applications/{application_id}/reject [put]
+// @Param       id  path     string          true  "申请 ID"
+// @Param       req body     decisionRequest true  "拒绝理由"
+// @Success     200 {object} service.ApplicationDTO
+// @Failure     400 {object} ErrorResponse
+// @Failure     401 {object} ErrorResponse
+// @Failure     403 {object} ErrorResponse
+// @Failure     500 {object} ErrorResponse
+// @Router      /api/applications/{id}/reject [put]
 func (ctl *ApplicationController) Reject(c *gin.Context) {
 	raw, _ := c.Get("userID")
 	ownerID := raw.(uuid.UUID)
 
-	appID, err := uuid.Parse(c.Param("application_id"))
+	appID, err := uuid.Parse(c.Param("id"))
 	if err != nil {
 		c.JSON(http.StatusBadRequest, ErrorResponse{Error: "invalid application_id"})
 		return
@@ -322,9 +319,13 @@ func (ctl *ApplicationController) Reject(c *gin.Context) {
 
 // 错误统一处理（示例）
 func handleError(c *gin.Context, err error) {
-	switch err {
-	case repository.ErrNotApplicationOwner:
+	switch {
+	case errors.Is(err, repository.ErrNotApplicationOwner):
 		c.JSON(http.StatusForbidden, ErrorResponse{Error: err.Error()})
+	case errors.Is(err, service.ErrIllegalBountyTransition):
+		c.JSON(http.StatusConflict, ErrorResponse{Error: err.Error()})
+	case errors.Is(err, service.ErrBountyNotFound):
+		c.JSON(http.StatusNotFound, ErrorResponse{Error: err.Error()})
 	default:
 		c.JSON(http.StatusInternalServerError, ErrorResponse{Error: err.Error()})
 	}
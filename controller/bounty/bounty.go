@@ -1,34 +1,82 @@
 package bounty
 
 import (
+	"errors"
 	"github.com/gin-gonic/gin"
 	"github.com/google/uuid"
 	"net/http"
+	"onepenny-server/internal/pagination"
+	"onepenny-server/internal/repository"
 	"onepenny-server/internal/service"
+	"onepenny-server/model/dao"
+	"onepenny-server/util"
 	"strconv"
+	"strings"
 	"time"
 )
 
 // BountyController 提供赏金任务相关的 HTTP 接口
 type BountyController struct {
-	svc service.BountyService
+	svc      service.BountyService
+	feedSvc  service.FeedService
+	trendSvc service.TrendingService
+	watchSvc service.WatchService
+	viewSvc  service.ViewService
+	dupSvc   service.DuplicateService
 }
 
-// NewBountyController 注入 BountyService
-func NewBountyController(svc service.BountyService) *BountyController {
-	return &BountyController{svc: svc}
+// NewBountyController 注入 BountyService、FeedService、TrendingService、WatchService、ViewService 与 DuplicateService
+func NewBountyController(svc service.BountyService, feedSvc service.FeedService, trendSvc service.TrendingService, watchSvc service.WatchService, viewSvc service.ViewService, dupSvc service.DuplicateService) *BountyController {
+	return &BountyController{svc: svc, feedSvc: feedSvc, trendSvc: trendSvc, watchSvc: watchSvc, viewSvc: viewSvc, dupSvc: dupSvc}
 }
 
 // CreateBountyRequest 创建赏金任务请求体
+// 草稿只要求标题，描述、赏金与币种在发布时校验
 type CreateBountyRequest struct {
 	Title       string   `json:"title" binding:"required"`
-	Description string   `json:"description" binding:"required"`
-	Reward      float64  `json:"reward" binding:"required"`
-	Currency    string   `json:"currency" binding:"required"`
+	Description string   `json:"description,omitempty"`
+	Reward      float64  `json:"reward,omitempty"`
+	Currency    string   `json:"currency,omitempty"`
 	Deadline    *string  `json:"deadline,omitempty"` // RFC3339
-	Category    string   `json:"category,omitempty"`
+	Category    string   `json:"category,omitempty"` // 分类 Slug，须存在于分类树中
 	Tags        []string `json:"tags,omitempty"`
 	Priority    string   `json:"priority,omitempty"`
+	// Milestones 可选，按顺序排列，金额之和必须等于 reward
+	Milestones []MilestoneRequest `json:"milestones,omitempty"`
+	// Draft 为 true 时保存为草稿；PublishAt 晚于当前时间时同样先保存为草稿，到时自动发布
+	Draft     bool    `json:"draft,omitempty"`
+	PublishAt *string `json:"publish_at,omitempty"` // RFC3339
+	// Visibility 可见范围：public（默认）、unlisted（仅凭链接）、team（需 team_id）、invite_only（需 allowed_user_ids）
+	Visibility     string      `json:"visibility,omitempty"`
+	TeamID         *uuid.UUID  `json:"team_id,omitempty"`
+	AllowedUserIDs []uuid.UUID `json:"allowed_user_ids,omitempty"`
+	// 附件链接、位置（"remote" 或线下地址）与沟通方式；线下悬赏令可附坐标以便按距离搜索
+	Attachments   []string `json:"attachments,omitempty"`
+	Location      string   `json:"location,omitempty"`
+	Latitude      *float64 `json:"latitude,omitempty"`
+	Longitude     *float64 `json:"longitude,omitempty"`
+	Communication string   `json:"communication,omitempty"`
+}
+
+// MilestoneRequest 单个里程碑的请求体
+type MilestoneRequest struct {
+	Title       string  `json:"title" binding:"required"`
+	Deliverable string  `json:"deliverable,omitempty"`
+	Amount      float64 `json:"amount" binding:"required"`
+	DueDate     *string `json:"due_date,omitempty"` // RFC3339
+}
+
+// MilestoneResponse 里程碑返回体
+type MilestoneResponse struct {
+	ID                    uuid.UUID  `json:"id"`
+	Seq                   int        `json:"seq"`
+	Title                 string     `json:"title"`
+	Deliverable           string     `json:"deliverable,omitempty"`
+	Amount                float64    `json:"amount"`
+	DueDate               *time.Time `json:"due_date,omitempty"`
+	Status                string     `json:"status"`
+	SettlementRequestedAt *time.Time `json:"settlement_requested_at,omitempty"`
+	SettledAt             *time.Time `json:"settled_at,omitempty"`
 }
 
 // BountyResponse 赏金任务返回体
@@ -43,8 +91,127 @@ type BountyResponse struct {
 	Category    string     `json:"category,omitempty"`
 	Tags        []string   `json:"tags,omitempty"`
 	Priority    string     `json:"priority"`
+	Status      string     `json:"status"`
 	CreatedAt   time.Time  `json:"created_at"`
 	UpdatedAt   time.Time  `json:"updated_at"`
+	OverdueAt   *time.Time `json:"overdue_at,omitempty"` // 进行中超过截止时间时由后台任务标记
+	PublishAt   *time.Time `json:"publish_at,omitempty"` // 草稿的定时发布时间，或已发布的发布时间
+	// 发布者申请取消、等待接收者确认时返回
+	CancelRequestedAt *time.Time `json:"cancel_requested_at,omitempty"`
+	CancelReason      string     `json:"cancel_reason,omitempty"`
+	// 众筹追加：按币种汇总的追加金额，以及基础赏金加追加后的实际赏金
+	Pledged         map[string]float64 `json:"pledged,omitempty"`
+	EffectiveReward map[string]float64 `json:"effective_reward"`
+	// 来源：通过模板发布或由周期悬赏生成时返回
+	TemplateID *uuid.UUID `json:"template_id,omitempty"`
+	SeriesID   *uuid.UUID `json:"series_id,omitempty"`
+	Occurrence int        `json:"occurrence,omitempty"`
+	// 可见范围；白名单仅返回给发布者
+	Visibility     string      `json:"visibility"`
+	TeamID         *uuid.UUID  `json:"team_id,omitempty"`
+	AllowedUserIDs []uuid.UUID `json:"allowed_user_ids,omitempty"`
+	// 附件、位置与沟通方式；按距离搜索时返回与搜索点的距离
+	Attachments   []string `json:"attachments,omitempty"`
+	Location      string   `json:"location,omitempty"`
+	Latitude      *float64 `json:"latitude,omitempty"`
+	Longitude     *float64 `json:"longitude,omitempty"`
+	DistanceKm    *float64 `json:"distance_km,omitempty"`
+	Communication string   `json:"communication,omitempty"`
+	// 关注人数与浏览数（同一用户在去重窗口内只计一次）
+	WatcherCount int64 `json:"watcher_count"`
+	ViewCount    int64 `json:"view_count"`
+
+	Milestones []MilestoneResponse `json:"milestones,omitempty"`
+}
+
+// BountyListResponse 悬赏令列表返回体：分页字段同 pagination.Result，另附各维度的分面统计
+type BountyListResponse struct {
+	Items      []BountyResponse `json:"items"`
+	NextCursor string           `json:"next_cursor"`
+	Total      *int64           `json:"total,omitempty"`
+	Facets     FacetsResponse   `json:"facets"`
+}
+
+// FacetsResponse 分面统计：各取值对应的悬赏令数量。
+// 每个维度统计时忽略该维度自身的筛选条件，便于展示可切换的其他取值；标签只返回数量最多的部分
+type FacetsResponse struct {
+	Status   map[string]int64 `json:"status"`
+	Category map[string]int64 `json:"category"`
+	Tags     map[string]int64 `json:"tags"`
+}
+
+// SearchResultResponse 全文检索的一条结果；高亮字段以 <mark> 标注命中词，已做 HTML 转义
+type SearchResultResponse struct {
+	Bounty         BountyResponse `json:"bounty"`
+	Rank           float64        `json:"rank"`
+	TitleHighlight string         `json:"title_highlight"`
+	Snippet        string         `json:"snippet"`
+}
+
+// RecommendedBountyResponse 推荐结果：得分越高越符合用户兴趣
+type RecommendedBountyResponse struct {
+	Bounty BountyResponse `json:"bounty"`
+	Score  float64        `json:"score"`
+}
+
+// TrendingBountyResponse 热度榜的一项：得分为时间衰减后的加权互动数
+type TrendingBountyResponse struct {
+	Bounty BountyResponse `json:"bounty"`
+	Score  float64        `json:"score"`
+}
+
+// WatchStatusResponse 当前用户是否关注了悬赏令，以及关注人数
+type WatchStatusResponse struct {
+	Watching     bool  `json:"watching"`
+	WatcherCount int64 `json:"watcher_count"`
+}
+
+// WatchingResponse 关注列表中的一项
+type WatchingResponse struct {
+	Bounty    BountyResponse `json:"bounty"`
+	WatchedAt time.Time      `json:"watched_at"`
+}
+
+// SimilarBountyResponse 与新悬赏令相似的近期悬赏令，Score 为 0~1 的三元组相似度
+type SimilarBountyResponse struct {
+	Bounty BountyResponse `json:"bounty"`
+	Score  float64        `json:"score"`
+	Own    bool           `json:"own"` // 是否为发布者自己的悬赏令
+}
+
+// CreateBountyResponse 创建结果：悬赏令字段之外附上相似的近期悬赏令，仅作提示
+type CreateBountyResponse struct {
+	BountyResponse
+	SimilarBounties []SimilarBountyResponse `json:"similar_bounties,omitempty"`
+}
+
+// DuplicateErrorResponse 与自己的近期悬赏令疑似重复而未创建时的返回体
+type DuplicateErrorResponse struct {
+	Error      string                  `json:"error"`
+	Duplicates []SimilarBountyResponse `json:"duplicates"`
+}
+
+// DuplicateClusterResponse 重复报告中的一组疑似重复悬赏令，按创建时间升序
+type DuplicateClusterResponse struct {
+	Score    float64          `json:"score"`
+	Bounties []BountyResponse `json:"bounties"`
+}
+
+// ViewBucketResponse 一个时间桶内的浏览数与独立访客数
+type ViewBucketResponse struct {
+	Start         time.Time `json:"start"`
+	Views         int64     `json:"views"`
+	UniqueViewers int64     `json:"unique_viewers"`
+}
+
+// ViewAnalyticsResponse 悬赏令的浏览统计：区间内总浏览数、独立访客数与按时间分桶的明细
+type ViewAnalyticsResponse struct {
+	Interval      string               `json:"interval"`
+	From          time.Time            `json:"from"`
+	To            time.Time            `json:"to"`
+	Views         int64                `json:"views"`
+	UniqueViewers int64                `json:"unique_viewers"`
+	Buckets       []ViewBucketResponse `json:"buckets"`
 }
 
 // UpdateBountyRequest 更新赏金任务请求体
@@ -54,10 +221,46 @@ type UpdateBountyRequest struct {
 	Reward      *float64  `json:"reward,omitempty"`
 	Currency    *string   `json:"currency,omitempty"`
 	Deadline    *string   `json:"deadline,omitempty"` // RFC3339
-	Status      *string   `json:"status,omitempty"`
 	Category    *string   `json:"category,omitempty"`
 	Tags        *[]string `json:"tags,omitempty"`
 	Priority    *string   `json:"priority,omitempty"`
+	// Milestones 非空时整体替换里程碑，传空数组表示取消分阶段
+	Milestones *[]MilestoneRequest `json:"milestones,omitempty"`
+	// PublishAt 仅草稿可设置，传空字符串表示取消定时发布
+	PublishAt *string `json:"publish_at,omitempty"` // RFC3339
+	// 可见范围，未传入的部分沿用原值；allowed_user_ids 非空时整体替换白名单
+	Visibility     *string      `json:"visibility,omitempty"`
+	TeamID         *uuid.UUID   `json:"team_id,omitempty"`
+	AllowedUserIDs *[]uuid.UUID `json:"allowed_user_ids,omitempty"`
+	// 修改 location 而未同时提供坐标时清除原坐标
+	Attachments   *[]string `json:"attachments,omitempty"`
+	Location      *string   `json:"location,omitempty"`
+	Latitude      *float64  `json:"latitude,omitempty"`
+	Longitude     *float64  `json:"longitude,omitempty"`
+	Communication *string   `json:"communication,omitempty"`
+}
+
+// CancelBountyRequest 取消悬赏令请求体
+type CancelBountyRequest struct {
+	Reason  string  `json:"reason" binding:"required"`
+	KillFee float64 `json:"kill_fee,omitempty"` // 进行中时支付给接收者的违约金，不填则需接收者同意
+}
+
+// RespondCancellationRequest 接收者处理取消申请请求体
+type RespondCancellationRequest struct {
+	Accept *bool `json:"accept" binding:"required"`
+}
+
+// BountyEventResponse 悬赏令时间线中的单条事件
+type BountyEventResponse struct {
+	ID         uuid.UUID              `json:"id"`
+	BountyID   uuid.UUID              `json:"bounty_id"`
+	ActorID    *uuid.UUID             `json:"actor_id,omitempty"`
+	Type       string                 `json:"type"`
+	FromStatus string                 `json:"from_status,omitempty"`
+	ToStatus   string                 `json:"to_status,omitempty"`
+	Payload    map[string]interface{} `json:"payload,omitempty"`
+	CreatedAt  time.Time              `json:"created_at"`
 }
 
 // ErrorResponse 通用错误返回体
@@ -67,15 +270,21 @@ type ErrorResponse struct {
 
 // Create godoc
 // @Summary     创建赏金任务
-// @Description 登录用户创建新的赏金任务
+// @Description 登录用户创建新的赏金任务；draft 为 true 或 publish_at 在未来时保存为草稿，不托管赏金。
+// @Description 标题与描述会和近 30 天内尚未完成的悬赏令按三元组相似度比对：与自己的悬赏令疑似重复时返回 409 及相似列表，force=true 时仍然创建；
+// @Description 与他人的悬赏令相似时照常创建，并在 similar_bounties 中提示
 // @Tags        bounty
 // @Security    BearerAuth
 // @Accept      json
 // @Produce     json
-// @Param       req body     CreateBountyRequest true "赏金任务信息"
-// @Success     201 {object} BountyResponse
-// @Failure     400 {object} ErrorResponse "参数格式错误"
+// @Param       force query    bool                false "与自己的近期悬赏令疑似重复时仍然创建"
+// @Param       req   body     CreateBountyRequest true  "赏金任务信息"
+// @Success     201 {object} CreateBountyResponse
+// @Failure     400 {object} ErrorResponse "参数格式错误、信息不完整、里程碑金额与赏金不符、可见范围或坐标设置错误"
 // @Failure     401 {object} ErrorResponse "未授权"
+// @Failure     402 {object} ErrorResponse "钱包余额不足以托管赏金"
+// @Failure     403 {object} ErrorResponse "不是所设团队的成员"
+// @Failure     409 {object} DuplicateErrorResponse "与自己的近期悬赏令疑似重复"
 // @Failure     500 {object} ErrorResponse "服务器内部错误"
 // @Router      /api/bounties [post]
 func (ctl *BountyController) Create(c *gin.Context) {
@@ -106,6 +315,42 @@ func (ctl *BountyController) Create(c *gin.Context) {
 		dl = &parsed
 	}
 
+	var publishAt *time.Time
+	if req.PublishAt != nil {
+		parsed, err := time.Parse(time.RFC3339, *req.PublishAt)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, ErrorResponse{Error: "invalid publish_at format; use RFC3339"})
+			return
+		}
+		publishAt = &parsed
+	}
+
+	milestones, err := parseMilestones(req.Milestones)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: err.Error()})
+		return
+	}
+
+	force := false
+	if s := c.Query("force"); s != "" {
+		if force, err = strconv.ParseBool(s); err != nil {
+			c.JSON(http.StatusBadRequest, ErrorResponse{Error: "invalid force"})
+			return
+		}
+	}
+	check, err := ctl.dupSvc.Check(userID, req.Title, req.Description)
+	if err != nil {
+		handleError(c, err)
+		return
+	}
+	if len(check.Own) > 0 && !force {
+		c.JSON(http.StatusConflict, DuplicateErrorResponse{
+			Error:      service.ErrDuplicateBounty.Error(),
+			Duplicates: toSimilarResponses(check.Own, userID),
+		})
+		return
+	}
+
 	input := &service.CreateBountyInput{
 		Title:       req.Title,
 		Description: req.Description,
@@ -116,87 +361,321 @@ func (ctl *BountyController) Create(c *gin.Context) {
 		Category:    req.Category,
 		Tags:        req.Tags,
 		Priority:    req.Priority,
+		Milestones:  milestones,
+		Draft:       req.Draft,
+		PublishAt:   publishAt,
+
+		Visibility:     req.Visibility,
+		TeamID:         req.TeamID,
+		AllowedUserIDs: req.AllowedUserIDs,
+
+		Attachments:   req.Attachments,
+		Location:      req.Location,
+		Latitude:      req.Latitude,
+		Longitude:     req.Longitude,
+		Communication: req.Communication,
 	}
 
 	b, err := ctl.svc.CreateBounty(input)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, ErrorResponse{Error: err.Error()})
+		handleError(c, err)
 		return
 	}
 
-	// 构造响应
-	resp := BountyResponse{
-		ID:          b.ID,
-		Title:       b.Title,
-		Description: b.Description,
-		Reward:      b.Reward,
-		Currency:    b.Currency,
-		UserID:      b.UserID,
-		Deadline:    b.Deadline,
-		Category:    b.Category,
-		Tags:        b.Tags,
-		Priority:    b.Priority,
-		CreatedAt:   b.CreatedAt,
-		UpdatedAt:   b.UpdatedAt,
-	}
-	c.JSON(http.StatusCreated, resp)
+	c.JSON(http.StatusCreated, CreateBountyResponse{
+		BountyResponse:  toResponse(b),
+		SimilarBounties: append(toSimilarResponses(check.Own, userID), toSimilarResponses(check.Others, userID)...),
+	})
 }
 
 // List godoc
 // @Summary     列出赏金任务
-// @Description 分页获取赏金任务列表
+// @Description 按条件分页获取当前用户可见的赏金任务列表，不含仅凭链接可见的悬赏令，并返回状态、分类与标签的分面统计；
+// @Description 多值参数可重复传入或以逗号分隔；传入 near 且未指定 sort 时按距离由近到远排序并返回距离
 // @Tags        bounty
 // @Security    BearerAuth
 // @Produce     json
-// @Param       page  query     int false "页码" default(1)
-// @Param       size  query     int false "每页大小" default(20)
-// @Success     200 {array}     BountyResponse
+// @Param       cursor        query string false "上一页返回的 next_cursor，为空表示第一页"
+// @Param       size          query int    false "每页大小" default(20)
+// @Param       with_total    query bool   false "是否返回总数"
+// @Param       status        query string false "状态，可多选"
+// @Param       category      query string false "分类 Slug，可多选；父分类包含其全部子孙分类"
+// @Param       tags          query string false "标签，可多选"
+// @Param       tags_match    query string false "any（包含任一，默认）或 all（包含全部）"
+// @Param       min_reward    query number false "最低赏金"
+// @Param       max_reward    query number false "最高赏金"
+// @Param       currency      query string false "币种"
+// @Param       priority      query string false "优先级，可多选"
+// @Param       deadline_from query string false "截止时间不早于（RFC3339）"
+// @Param       deadline_to   query string false "截止时间不晚于（RFC3339）"
+// @Param       publisher_id  query string false "发布者 ID"
+// @Param       unassigned    query bool   false "只看尚无接收者的悬赏令"
+// @Param       sort          query string false "newest（默认）、reward、deadline、popular"
+// @Param       order         query string false "asc 或 desc，默认截止时间升序、其余降序"
+// @Param       location      query string false "remote 只看远程悬赏令，其他值按线下地址模糊匹配"
+// @Param       near          query string false "搜索中心点，格式 lat,lng"
+// @Param       radius_km     query number false "搜索半径（千米），配合 near 使用" default(10)
+// @Success     200 {object}    BountyListResponse
+// @Failure     400 {object}    ErrorResponse "筛选或排序参数错误"
 // @Failure     500 {object}    ErrorResponse "服务器内部错误"
 // @Router      /api/bounties [get]
 func (ctl *BountyController) List(c *gin.Context) {
-	page := 1
-	size := 20
-	if p := c.Query("page"); p != "" {
-		if v, err := strconv.Atoi(p); err == nil && v > 0 {
-			page = v
-		}
+	raw, _ := c.Get("userID")
+	userID := raw.(uuid.UUID)
+
+	page, err := pagination.FromQuery(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: err.Error()})
+		return
 	}
-	if s := c.Query("size"); s != "" {
-		if v, err := strconv.Atoi(s); err == nil && v > 0 {
-			size = v
-		}
+
+	filter, err := parseBountyFilter(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: err.Error()})
+		return
 	}
 
-	list, err := ctl.svc.ListBounties(page, size)
+	list, err := ctl.svc.ListBounties(userID, filter, page)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, ErrorResponse{Error: err.Error()})
+		handleError(c, err)
+		return
+	}
+	facets, err := ctl.svc.FacetBounties(userID, filter)
+	if err != nil {
+		handleError(c, err)
 		return
 	}
 
 	// 构造响应
-	resp := make([]BountyResponse, len(list))
-	for i, b := range list {
-		resp[i] = BountyResponse{
-			ID:          b.ID,
-			Title:       b.Title,
-			Description: b.Description,
-			Reward:      b.Reward,
-			Currency:    b.Currency,
-			UserID:      b.UserID,
-			Deadline:    b.Deadline,
-			Category:    b.Category,
-			Tags:        b.Tags,
-			Priority:    b.Priority,
-			CreatedAt:   b.CreatedAt,
-			UpdatedAt:   b.UpdatedAt,
+	resp := BountyListResponse{
+		Items:      make([]BountyResponse, len(list.Items)),
+		NextCursor: list.NextCursor,
+		Total:      list.Total,
+		Facets: FacetsResponse{
+			Status:   facets.Status,
+			Category: facets.Category,
+			Tags:     facets.Tags,
+		},
+	}
+	for i, b := range list.Items {
+		resp.Items[i] = toResponse(b)
+		if filter.Near != nil && b.Latitude != nil && b.Longitude != nil {
+			d := util.DistanceKm(filter.Near.Lat, filter.Near.Lng, *b.Latitude, *b.Longitude)
+			resp.Items[i].DistanceKm = &d
 		}
 	}
 	c.JSON(http.StatusOK, resp)
 }
 
+// parseBountyFilter 解析列表的筛选与排序参数；多值参数可重复传入或以逗号分隔
+func parseBountyFilter(c *gin.Context) (*service.BountyFilter, error) {
+	filter := &service.BountyFilter{
+		Location:   c.Query("location"),
+		Statuses:   queryList(c, "status"),
+		Categories: queryList(c, "category"),
+		Tags:       queryList(c, "tags"),
+		Currency:   c.Query("currency"),
+		Priorities: queryList(c, "priority"),
+		Sort:       c.Query("sort"),
+		Order:      c.Query("order"),
+	}
+
+	switch c.Query("tags_match") {
+	case "", "any":
+	case "all":
+		filter.AllTags = true
+	default:
+		return nil, errors.New("invalid tags_match; use any or all")
+	}
+
+	var err error
+	if filter.MinReward, err = queryFloat(c, "min_reward"); err != nil {
+		return nil, err
+	}
+	if filter.MaxReward, err = queryFloat(c, "max_reward"); err != nil {
+		return nil, err
+	}
+	if filter.DeadlineFrom, err = queryTime(c, "deadline_from"); err != nil {
+		return nil, err
+	}
+	if filter.DeadlineTo, err = queryTime(c, "deadline_to"); err != nil {
+		return nil, err
+	}
+	if s := c.Query("publisher_id"); s != "" {
+		id, err := uuid.Parse(s)
+		if err != nil {
+			return nil, errors.New("invalid publisher_id")
+		}
+		filter.PublisherID = &id
+	}
+	if s := c.Query("unassigned"); s != "" {
+		if filter.Unassigned, err = strconv.ParseBool(s); err != nil {
+			return nil, errors.New("invalid unassigned")
+		}
+	}
+
+	if near := c.Query("near"); near != "" {
+		if filter.Near, err = parseGeoPoint(near); err != nil {
+			return nil, err
+		}
+		radius, err := queryFloat(c, "radius_km")
+		if err != nil {
+			return nil, err
+		}
+		if radius != nil {
+			filter.RadiusKm = *radius
+		}
+	}
+	return filter, nil
+}
+
+// queryList 读取可重复或以逗号分隔的多值参数，忽略空值
+func queryList(c *gin.Context, key string) []string {
+	var list []string
+	for _, v := range c.QueryArray(key) {
+		for _, item := range strings.Split(v, ",") {
+			if item = strings.TrimSpace(item); item != "" {
+				list = append(list, item)
+			}
+		}
+	}
+	return list
+}
+
+func queryFloat(c *gin.Context, key string) (*float64, error) {
+	s := c.Query(key)
+	if s == "" {
+		return nil, nil
+	}
+	v, err := strconv.ParseFloat(s, 64)
+	if err != nil {
+		return nil, errors.New("invalid " + key)
+	}
+	return &v, nil
+}
+
+func queryTime(c *gin.Context, key string) (*time.Time, error) {
+	s := c.Query(key)
+	if s == "" {
+		return nil, nil
+	}
+	t, err := time.Parse(time.RFC3339, s)
+	if err != nil {
+		return nil, errors.New("invalid " + key + " format; use RFC3339")
+	}
+	return &t, nil
+}
+
+// Search godoc
+// @Summary     搜索赏金任务
+// @Description 全文检索当前用户可见的已发布悬赏令的标题、描述、标签与分类，支持中文与英文前缀匹配，按相关度排序并返回高亮片段
+// @Tags        bounty
+// @Security    BearerAuth
+// @Produce     json
+// @Param       q          query string true  "搜索词"
+// @Param       cursor     query string false "上一页返回的 next_cursor，为空表示第一页"
+// @Param       size       query int    false "每页大小" default(20)
+// @Param       with_total query bool   false "是否返回总数"
+// @Success     200   {object}  pagination.Result[SearchResultResponse]
+// @Failure     400   {object}  ErrorResponse "搜索词为空"
+// @Failure     500   {object}  ErrorResponse "服务器内部错误"
+// @Router      /api/bounties/search [get]
+func (ctl *BountyController) Search(c *gin.Context) {
+	raw, _ := c.Get("userID")
+	userID := raw.(uuid.UUID)
+
+	page, err := pagination.FromQuery(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: err.Error()})
+		return
+	}
+
+	hits, err := ctl.svc.SearchBounties(userID, c.Query("q"), page)
+	if err != nil {
+		handleError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, pagination.Map(hits, func(h *service.BountySearchHit) SearchResultResponse {
+		return SearchResultResponse{
+			Bounty:         toResponse(h.Bounty),
+			Rank:           h.Rank,
+			TitleHighlight: h.TitleHighlight,
+			Snippet:        h.Snippet,
+		}
+	}))
+}
+
+// Recommended godoc
+// @Summary     个性化推荐
+// @Description 根据当前用户浏览、点赞、申请与承接过的分类和标签，推荐尚无人承接的悬赏令；不含自己发布或已申请过的，没有历史记录时按发布时间推荐
+// @Tags        bounty
+// @Security    BearerAuth
+// @Produce     json
+// @Param       cursor     query string false "上一页返回的 next_cursor，为空表示第一页"
+// @Param       size       query int    false "每页大小" default(20)
+// @Success     200   {object}  pagination.Result[RecommendedBountyResponse]
+// @Failure     400   {object}  ErrorResponse "参数格式错误"
+// @Failure     401   {object}  ErrorResponse "未授权"
+// @Failure     500   {object}  ErrorResponse "服务器内部错误"
+// @Router      /api/feed/recommended [get]
+func (ctl *BountyController) Recommended(c *gin.Context) {
+	raw, _ := c.Get("userID")
+	userID := raw.(uuid.UUID)
+
+	page, err := pagination.FromQuery(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: err.Error()})
+		return
+	}
+
+	list, err := ctl.feedSvc.Recommended(userID, page)
+	if err != nil {
+		handleError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, pagination.Map(list, func(r *service.RecommendedBounty) RecommendedBountyResponse {
+		return RecommendedBountyResponse{Bounty: toResponse(r.Bounty), Score: r.Score}
+	}))
+}
+
+// Trending godoc
+// @Summary     热门悬赏令
+// @Description 按时间衰减后的浏览、点赞、评论与申请数排序，只含仍在招募或进行中的悬赏令；排行每分钟更新
+// @Tags        bounty
+// @Security    BearerAuth
+// @Produce     json
+// @Param       window     query string false "时间窗口：24h（默认）或 7d"
+// @Param       category   query string false "分类 Slug，包含其子孙分类"
+// @Param       cursor     query string false "上一页返回的 next_cursor，为空表示第一页"
+// @Param       size       query int    false "每页大小" default(20)
+// @Success     200   {object}  pagination.Result[TrendingBountyResponse]
+// @Failure     400   {object}  ErrorResponse "参数格式错误"
+// @Failure     401   {object}  ErrorResponse "未授权"
+// @Failure     500   {object}  ErrorResponse "服务器内部错误"
+// @Router      /api/bounties/trending [get]
+func (ctl *BountyController) Trending(c *gin.Context) {
+	raw, _ := c.Get("userID")
+	userID := raw.(uuid.UUID)
+
+	page, err := pagination.FromQuery(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: err.Error()})
+		return
+	}
+
+	list, err := ctl.trendSvc.Trending(userID, c.Query("window"), c.Query("category"), page)
+	if err != nil {
+		handleError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, pagination.Map(list, func(t *service.TrendingBounty) TrendingBountyResponse {
+		return TrendingBountyResponse{Bounty: toResponse(t.Bounty), Score: t.Score}
+	}))
+}
+
 // Get godoc
 // @Summary     获取赏金任务详情
-// @Description 根据 ID 获取单个赏金任务的详细信息
+// @Description 根据 ID 获取单个赏金任务的详细信息；草稿仅发布者可见，无权查看的悬赏令返回 404
 // @Tags        bounty
 // @Security    BearerAuth
 // @Produce     json
@@ -213,31 +692,22 @@ func (ctl *BountyController) Get(c *gin.Context) {
 		return
 	}
 
-	b, err := ctl.svc.GetBounty(id)
+	raw, _ := c.Get("userID")
+	userID := raw.(uuid.UUID)
+
+	b, err := ctl.svc.GetBounty(id, userID)
 	if err != nil {
 		c.JSON(http.StatusNotFound, ErrorResponse{Error: err.Error()})
 		return
 	}
+	ctl.viewSvc.RecordView(b, userID)
 
-	c.JSON(http.StatusOK, BountyResponse{
-		ID:          b.ID,
-		Title:       b.Title,
-		Description: b.Description,
-		Reward:      b.Reward,
-		Currency:    b.Currency,
-		UserID:      b.UserID,
-		Deadline:    b.Deadline,
-		Category:    b.Category,
-		Tags:        b.Tags,
-		Priority:    b.Priority,
-		CreatedAt:   b.CreatedAt,
-		UpdatedAt:   b.UpdatedAt,
-	})
+	c.JSON(http.StatusOK, toViewerResponse(b, userID))
 }
 
 // Update godoc
 // @Summary     更新赏金任务
-// @Description 根据 ID 更新赏金任务的字段
+// @Description 发布者根据 ID 更新赏金任务的字段；状态只能通过发布、取消、结算等专用接口变更
 // @Tags        bounty
 // @Security    BearerAuth
 // @Accept      json
@@ -245,8 +715,12 @@ func (ctl *BountyController) Get(c *gin.Context) {
 // @Param       id   path      string               true  "赏金任务 ID"
 // @Param       req  body      UpdateBountyRequest  true  "要更新的字段"
 // @Success     200  {object}  BountyResponse
-// @Failure     400  {object}  ErrorResponse  "参数格式错误或无效的 ID"
+// @Failure     400  {object}  ErrorResponse  "参数格式错误、无效的 ID、可见范围或坐标设置错误"
 // @Failure     401  {object}  ErrorResponse  "未授权"
+// @Failure     402  {object}  ErrorResponse  "钱包余额不足以托管赏金"
+// @Failure     403  {object}  ErrorResponse  "不是发布者，或不是所设团队的成员"
+// @Failure     404  {object}  ErrorResponse  "未找到赏金任务"
+// @Failure     409  {object}  ErrorResponse  "赏金、里程碑已锁定，或对已发布的悬赏令设置定时发布"
 // @Failure     500  {object}  ErrorResponse  "服务器内部错误"
 // @Router      /api/bounties/{id} [put]
 func (ctl *BountyController) Update(c *gin.Context) {
@@ -263,6 +737,17 @@ func (ctl *BountyController) Update(c *gin.Context) {
 		return
 	}
 
+	uidVal, exists := c.Get("userID")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, ErrorResponse{Error: "unauthorized"})
+		return
+	}
+	userID, ok := uidVal.(uuid.UUID)
+	if !ok {
+		c.JSON(http.StatusInternalServerError, ErrorResponse{Error: "invalid userID"})
+		return
+	}
+
 	var dl *time.Time
 	if req.Deadline != nil {
 		parsed, err := time.Parse(time.RFC3339, *req.Deadline)
@@ -273,49 +758,76 @@ func (ctl *BountyController) Update(c *gin.Context) {
 		dl = &parsed
 	}
 
+	var milestones *[]service.MilestoneInput
+	if req.Milestones != nil {
+		parsed, err := parseMilestones(*req.Milestones)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, ErrorResponse{Error: err.Error()})
+			return
+		}
+		if parsed == nil {
+			parsed = []service.MilestoneInput{}
+		}
+		milestones = &parsed
+	}
+
+	var publishAt *time.Time
+	if req.PublishAt != nil {
+		parsed := time.Time{}
+		if *req.PublishAt != "" {
+			if parsed, err = time.Parse(time.RFC3339, *req.PublishAt); err != nil {
+				c.JSON(http.StatusBadRequest, ErrorResponse{Error: "invalid publish_at format; use RFC3339"})
+				return
+			}
+		}
+		publishAt = &parsed
+	}
+
 	input := &service.UpdateBountyInput{
+		ActorID:     userID,
 		Title:       req.Title,
 		Description: req.Description,
 		Reward:      req.Reward,
 		Currency:    req.Currency,
 		Deadline:    dl,
-		Status:      req.Status,
 		Category:    req.Category,
 		Tags:        req.Tags,
 		Priority:    req.Priority,
+		Milestones:  milestones,
+		PublishAt:   publishAt,
+
+		Visibility:     req.Visibility,
+		TeamID:         req.TeamID,
+		AllowedUserIDs: req.AllowedUserIDs,
+
+		Attachments:   req.Attachments,
+		Location:      req.Location,
+		Latitude:      req.Latitude,
+		Longitude:     req.Longitude,
+		Communication: req.Communication,
 	}
 
 	updated, err := ctl.svc.UpdateBounty(id, input)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, ErrorResponse{Error: err.Error()})
+		handleError(c, err)
 		return
 	}
 
-	c.JSON(http.StatusOK, BountyResponse{
-		ID:          updated.ID,
-		Title:       updated.Title,
-		Description: updated.Description,
-		Reward:      updated.Reward,
-		Currency:    updated.Currency,
-		UserID:      updated.UserID,
-		Deadline:    updated.Deadline,
-		Category:    updated.Category,
-		Tags:        updated.Tags,
-		Priority:    updated.Priority,
-		CreatedAt:   updated.CreatedAt,
-		UpdatedAt:   updated.UpdatedAt,
-	})
+	c.JSON(http.StatusOK, toViewerResponse(updated, userID))
 }
 
 // Delete godoc
 // @Summary     删除赏金任务
-// @Description 根据 ID 删除赏金任务
+// @Description 发布方根据 ID 删除赏金任务，仅限草稿以及已结算、已取消或已过期的悬赏令
 // @Tags        bounty
 // @Security    BearerAuth
 // @Param       id   path      string  true  "赏金任务 ID"
 // @Success     204  {string}  string  "No Content"
 // @Failure     400  {object}  ErrorResponse  "无效的 ID"
 // @Failure     401  {object}  ErrorResponse  "未授权"
+// @Failure     403  {object}  ErrorResponse  "非发布方"
+// @Failure     404  {object}  ErrorResponse  "未找到赏金任务"
+// @Failure     409  {object}  ErrorResponse  "悬赏令尚未结束"
 // @Failure     500  {object}  ErrorResponse  "服务器内部错误"
 // @Router      /api/bounties/{id} [delete]
 func (ctl *BountyController) Delete(c *gin.Context) {
@@ -325,72 +837,787 @@ func (ctl *BountyController) Delete(c *gin.Context) {
 		c.JSON(http.StatusBadRequest, ErrorResponse{Error: "invalid bounty ID"})
 		return
 	}
+	raw, _ := c.Get("userID")
+	userID := raw.(uuid.UUID)
 
-	if err := ctl.svc.DeleteBounty(id); err != nil {
-		c.JSON(http.StatusInternalServerError, ErrorResponse{Error: err.Error()})
+	if err := ctl.svc.DeleteBounty(id, userID); err != nil {
+		handleError(c, err)
 		return
 	}
 
 	c.Status(http.StatusNoContent)
 }
 
-// RequestSettlement godoc
-// @Summary     发起结算申请
-// @Description 接收者完成任务后，可向发布者发起结算请求
+// ListDrafts godoc
+// @Summary     我的草稿
+// @Description 分页列出当前用户的草稿，包括等待定时发布的悬赏令
 // @Tags        bounty
 // @Security    BearerAuth
 // @Produce     json
-// @Param       bounty_id path string true "悬赏令 ID"
-// @Success     200 {object} dao.Bounty
-// @Failure     400 {object} ErrorResponse
-// @Failure     401 {object} ErrorResponse
-// @Failure     403 {object} ErrorResponse
-// @Failure     500 {object} ErrorResponse
-// @Router      /api/bounties/{bounty_id}/request-settlement [post]
-func (ctl *BountyController) RequestSettlement(c *gin.Context) {
+// @Param       cursor     query string false "上一页返回的 next_cursor，为空表示第一页"
+// @Param       size       query int    false "每页大小" default(20)
+// @Param       with_total query bool   false "是否返回总数"
+// @Success     200   {object}  pagination.Result[BountyResponse]
+// @Failure     401   {object}  ErrorResponse  "未授权"
+// @Failure     500   {object}  ErrorResponse  "服务器内部错误"
+// @Router      /api/bounties/drafts [get]
+func (ctl *BountyController) ListDrafts(c *gin.Context) {
 	raw, _ := c.Get("userID")
 	userID := raw.(uuid.UUID)
 
-	bID, err := uuid.Parse(c.Param("bounty_id"))
+	page, err := pagination.FromQuery(c)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid bounty_id"})
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: err.Error()})
 		return
 	}
-	b, err := ctl.svc.RequestSettlement(bID, userID)
+
+	list, err := ctl.svc.ListDrafts(userID, page)
 	if err != nil {
-		// 这里可根据 err 类型返回 403、400 或 500
-		c.JSON(http.StatusForbidden, gin.H{"error": err.Error()})
+		handleError(c, err)
 		return
 	}
-	c.JSON(http.StatusOK, b)
+	c.JSON(http.StatusOK, pagination.Map(list, toResponse))
 }
 
-// ConfirmSettlement godoc
-// @Summary     确认结算
-// @Description 发布者对某个待结算悬赏令进行确认结算
+// Publish godoc
+// @Summary     发布草稿
+// @Description 校验草稿信息完整后立即发布，并从发布者钱包托管赏金
 // @Tags        bounty
 // @Security    BearerAuth
 // @Produce     json
-// @Param       bounty_id path string true "悬赏令 ID"
-// @Success     200 {object} dao.Bounty
-// @Failure     400 {object} ErrorResponse
-// @Failure     401 {object} ErrorResponse
-// @Failure     403 {object} ErrorResponse
-// @Failure     500 {object} ErrorResponse
-// @Router      /api/bounties/{bounty_id}/confirm-settlement [post]
-func (ctl *BountyController) ConfirmSettlement(c *gin.Context) {
+// @Param       id   path      string  true  "赏金任务 ID"
+// @Success     200  {object}  BountyResponse
+// @Failure     400  {object}  ErrorResponse  "无效的 ID 或信息不完整"
+// @Failure     402  {object}  ErrorResponse  "钱包余额不足以托管赏金"
+// @Failure     403  {object}  ErrorResponse  "不是发布者"
+// @Failure     404  {object}  ErrorResponse  "未找到赏金任务"
+// @Failure     409  {object}  ErrorResponse  "悬赏令已发布"
+// @Failure     500  {object}  ErrorResponse  "服务器内部错误"
+// @Router      /api/bounties/{id}/publish [post]
+func (ctl *BountyController) Publish(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: "invalid bounty ID"})
+		return
+	}
+
 	raw, _ := c.Get("userID")
 	userID := raw.(uuid.UUID)
 
-	bID, err := uuid.Parse(c.Param("bounty_id"))
+	b, err := ctl.svc.PublishBounty(id, userID)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid bounty_id"})
+		handleError(c, err)
 		return
 	}
-	b, err := ctl.svc.ConfirmSettlement(bID, userID)
+	c.JSON(http.StatusOK, toResponse(b))
+}
+
+// Timeline godoc
+// @Summary     悬赏令时间线
+// @Description 按时间顺序分页获取悬赏令的事件历史（发布、编辑、接单、结算等）
+// @Tags        bounty
+// @Security    BearerAuth
+// @Produce     json
+// @Param       id         path  string true  "赏金任务 ID"
+// @Param       cursor     query string false "上一页返回的 next_cursor，为空表示第一页"
+// @Param       size       query int    false "每页大小" default(20)
+// @Param       with_total query bool   false "是否返回总数"
+// @Success     200   {object}  pagination.Result[BountyEventResponse]
+// @Failure     400   {object}  ErrorResponse  "无效的 ID"
+// @Failure     404   {object}  ErrorResponse  "未找到赏金任务"
+// @Failure     500   {object}  ErrorResponse  "服务器内部错误"
+// @Router      /api/bounties/{id}/timeline [get]
+func (ctl *BountyController) Timeline(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: "invalid bounty ID"})
+		return
+	}
+
+	page, err := pagination.FromQuery(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: err.Error()})
+		return
+	}
+
+	raw, _ := c.Get("userID")
+	userID := raw.(uuid.UUID)
+
+	list, err := ctl.svc.ListTimeline(id, userID, page)
+	if err != nil {
+		handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, pagination.Map(list, func(ev *dao.BountyEvent) BountyEventResponse {
+		return BountyEventResponse{
+			ID:         ev.ID,
+			BountyID:   ev.BountyID,
+			ActorID:    ev.ActorID,
+			Type:       ev.Type,
+			FromStatus: string(ev.FromStatus),
+			ToStatus:   string(ev.ToStatus),
+			Payload:    ev.Payload,
+			CreatedAt:  ev.CreatedAt,
+		}
+	}))
+}
+
+// Watch godoc
+// @Summary     关注悬赏令
+// @Description 关注后，悬赏令状态变化、有新评论、截止时间修改或结算时收到通知；发布者与评论者自动关注
+// @Tags        bounty
+// @Security    BearerAuth
+// @Produce     json
+// @Param       id   path      string  true  "赏金任务 ID"
+// @Success     200  {object}  WatchStatusResponse
+// @Failure     400  {object}  ErrorResponse  "无效的 ID"
+// @Failure     404  {object}  ErrorResponse  "未找到赏金任务"
+// @Failure     500  {object}  ErrorResponse  "服务器内部错误"
+// @Router      /api/bounties/{id}/watch [post]
+func (ctl *BountyController) Watch(c *gin.Context) {
+	ctl.changeWatch(c, ctl.watchSvc.Watch)
+}
+
+// Unwatch godoc
+// @Summary     取消关注悬赏令
+// @Tags        bounty
+// @Security    BearerAuth
+// @Produce     json
+// @Param       id   path      string  true  "赏金任务 ID"
+// @Success     200  {object}  WatchStatusResponse
+// @Failure     400  {object}  ErrorResponse  "无效的 ID"
+// @Failure     404  {object}  ErrorResponse  "未找到赏金任务"
+// @Failure     500  {object}  ErrorResponse  "服务器内部错误"
+// @Router      /api/bounties/{id}/watch [delete]
+func (ctl *BountyController) Unwatch(c *gin.Context) {
+	ctl.changeWatch(c, ctl.watchSvc.Unwatch)
+}
+
+// WatchStatus godoc
+// @Summary     查询是否关注悬赏令
+// @Tags        bounty
+// @Security    BearerAuth
+// @Produce     json
+// @Param       id   path      string  true  "赏金任务 ID"
+// @Success     200  {object}  WatchStatusResponse
+// @Failure     400  {object}  ErrorResponse  "无效的 ID"
+// @Failure     404  {object}  ErrorResponse  "未找到赏金任务"
+// @Failure     500  {object}  ErrorResponse  "服务器内部错误"
+// @Router      /api/bounties/{id}/watch [get]
+func (ctl *BountyController) WatchStatus(c *gin.Context) {
+	ctl.changeWatch(c, nil)
+}
+
+// changeWatch 关注／取消关注／查询共用的处理流程，fn 为空时只查询
+func (ctl *BountyController) changeWatch(c *gin.Context, fn func(bountyID, userID uuid.UUID) error) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: "invalid bounty ID"})
+		return
+	}
+	raw, _ := c.Get("userID")
+	userID := raw.(uuid.UUID)
+
+	if fn != nil {
+		if err := fn(id, userID); err != nil {
+			handleError(c, err)
+			return
+		}
+	}
+	watching, err := ctl.watchSvc.IsWatching(id, userID)
+	if err != nil {
+		handleError(c, err)
+		return
+	}
+	b, err := ctl.svc.GetBounty(id, userID)
+	if err != nil {
+		handleError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, WatchStatusResponse{Watching: watching, WatcherCount: b.WatcherCount})
+}
+
+// ListWatching godoc
+// @Summary     我关注的悬赏令
+// @Description 按关注时间由新到旧列出，不含已删除或不再可见的悬赏令
+// @Tags        bounty
+// @Security    BearerAuth
+// @Produce     json
+// @Param       cursor     query string false "上一页返回的 next_cursor，为空表示第一页"
+// @Param       size       query int    false "每页大小" default(20)
+// @Param       with_total query bool   false "是否返回总数"
+// @Success     200   {object}  pagination.Result[WatchingResponse]
+// @Failure     400   {object}  ErrorResponse "参数格式错误"
+// @Failure     500   {object}  ErrorResponse "服务器内部错误"
+// @Router      /api/bounties/watching [get]
+func (ctl *BountyController) ListWatching(c *gin.Context) {
+	raw, _ := c.Get("userID")
+	userID := raw.(uuid.UUID)
+
+	page, err := pagination.FromQuery(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: err.Error()})
+		return
+	}
+	list, err := ctl.watchSvc.ListWatching(userID, page)
+	if err != nil {
+		handleError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, pagination.Map(list, func(w *dao.BountyWatch) WatchingResponse {
+		return WatchingResponse{Bounty: toViewerResponse(&w.Bounty, userID), WatchedAt: w.CreatedAt}
+	}))
+}
+
+// Views godoc
+// @Summary     悬赏令浏览统计
+// @Description 发布者按小时或天查看自己悬赏令的浏览数与独立访客数，时间桶按 UTC 划分，没有浏览的桶计为零。
+// @Description 未指定区间时，按小时统计最近 48 小时，按天统计最近 30 天；同一用户在去重窗口内重复查看只计一次，最新的浏览可能稍后才计入
+// @Tags        bounty
+// @Security    BearerAuth
+// @Produce     json
+// @Param       id       path  string true  "赏金任务 ID"
+// @Param       interval query string false "分桶粒度：hour 或 day" default(day)
+// @Param       from     query string false "起始时间（RFC3339）"
+// @Param       to       query string false "结束时间（RFC3339），默认当前时间"
+// @Success     200  {object}  ViewAnalyticsResponse
+// @Failure     400  {object}  ErrorResponse  "无效的 ID、分桶粒度或时间区间"
+// @Failure     403  {object}  ErrorResponse  "不是发布者"
+// @Failure     404  {object}  ErrorResponse  "未找到赏金任务"
+// @Failure     500  {object}  ErrorResponse  "服务器内部错误"
+// @Router      /api/bounties/{id}/views [get]
+func (ctl *BountyController) Views(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: "invalid bounty ID"})
+		return
+	}
+	raw, _ := c.Get("userID")
+	userID := raw.(uuid.UUID)
+
+	from, err := queryTime(c, "from")
+	if err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: err.Error()})
+		return
+	}
+	to, err := queryTime(c, "to")
 	if err != nil {
-		c.JSON(http.StatusForbidden, gin.H{"error": err.Error()})
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: err.Error()})
+		return
+	}
+	stats, err := ctl.viewSvc.ViewAnalytics(id, userID, c.Query("interval"), from, to)
+	if err != nil {
+		handleError(c, err)
+		return
+	}
+	resp := ViewAnalyticsResponse{
+		Interval:      stats.Interval,
+		From:          stats.From,
+		To:            stats.To,
+		Views:         stats.Views,
+		UniqueViewers: stats.UniqueViewers,
+		Buckets:       make([]ViewBucketResponse, len(stats.Buckets)),
+	}
+	for i, bk := range stats.Buckets {
+		resp.Buckets[i] = ViewBucketResponse{Start: bk.Start, Views: bk.Views, UniqueViewers: bk.UniqueViewers}
+	}
+	c.JSON(http.StatusOK, resp)
+}
+
+// DuplicateReport godoc
+// @Summary     疑似重复悬赏令报告
+// @Description 列出最近 days 天内创建、尚未完成的疑似重复悬赏令：标题相近且标题与描述的三元组相似度不低于阈值的悬赏令连通成组，按组内最高相似度降序（仅管理员）
+// @Tags        admin
+// @Security    BearerAuth
+// @Produce     json
+// @Param       days      query int    false "时间范围（天），1~90" default(7)
+// @Param       threshold query number false "相似度阈值，[0.3, 1]，为 0 时取默认值" default(0.5)
+// @Success     200 {array}  DuplicateClusterResponse
+// @Failure     400 {object} ErrorResponse "参数格式错误"
+// @Failure     403 {object} ErrorResponse "无权限"
+// @Failure     500 {object} ErrorResponse "服务器内部错误"
+// @Router      /api/admin/bounties/duplicates [get]
+func (ctl *BountyController) DuplicateReport(c *gin.Context) {
+	days := 7
+	if s := c.Query("days"); s != "" {
+		var err error
+		if days, err = strconv.Atoi(s); err != nil {
+			c.JSON(http.StatusBadRequest, ErrorResponse{Error: "invalid days"})
+			return
+		}
+	}
+	threshold, err := queryFloat(c, "threshold")
+	if err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: err.Error()})
+		return
+	}
+	var minScore float64
+	if threshold != nil {
+		minScore = *threshold
+	}
+
+	clusters, err := ctl.dupSvc.Report(days, minScore)
+	if err != nil {
+		handleError(c, err)
+		return
+	}
+	resp := make([]DuplicateClusterResponse, len(clusters))
+	for i, cl := range clusters {
+		resp[i] = DuplicateClusterResponse{Score: cl.Score, Bounties: make([]BountyResponse, len(cl.Bounties))}
+		for j, b := range cl.Bounties {
+			resp[i].Bounties[j] = toResponse(b)
+		}
+	}
+	c.JSON(http.StatusOK, resp)
+}
+
+// RequestSettlement godoc
+// @Summary     发起结算申请
+// @Description 接收者完成任务后，可向发布者发起结算请求
+// @Tags        bounty
+// @Security    BearerAuth
+// @Produce     json
+// @Param       id  path string true "悬赏令 ID"
+// @Success     200 {object} dao.Bounty
+// @Failure     400 {object} ErrorResponse
+// @Failure     401 {object} ErrorResponse
+// @Failure     403 {object} ErrorResponse
+// @Failure     404 {object} ErrorResponse
+// @Failure     409 {object} ErrorResponse "非法的状态流转"
+// @Failure     500 {object} ErrorResponse
+// @Router      /api/bounties/{id}/request-settlement [post]
+func (ctl *BountyController) RequestSettlement(c *gin.Context) {
+	raw, _ := c.Get("userID")
+	userID := raw.(uuid.UUID)
+
+	bID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid bounty_id"})
+		return
+	}
+	b, err := ctl.svc.RequestSettlement(bID, userID)
+	if err != nil {
+		handleError(c, err)
 		return
 	}
 	c.JSON(http.StatusOK, b)
 }
+
+// ConfirmSettlement godoc
+// @Summary     确认结算
+// @Description 发布者对某个待结算悬赏令进行确认结算
+// @Tags        bounty
+// @Security    BearerAuth
+// @Produce     json
+// @Param       id  path string true "悬赏令 ID"
+// @Success     200 {object} dao.Bounty
+// @Failure     400 {object} ErrorResponse
+// @Failure     401 {object} ErrorResponse
+// @Failure     403 {object} ErrorResponse
+// @Failure     404 {object} ErrorResponse
+// @Failure     409 {object} ErrorResponse "非法的状态流转"
+// @Failure     500 {object} ErrorResponse
+// @Router      /api/bounties/{id}/confirm-settlement [post]
+func (ctl *BountyController) ConfirmSettlement(c *gin.Context) {
+	raw, _ := c.Get("userID")
+	userID := raw.(uuid.UUID)
+
+	bID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid bounty_id"})
+		return
+	}
+	b, err := ctl.svc.ConfirmSettlement(bID, userID)
+	if err != nil {
+		handleError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, b)
+}
+
+// Cancel godoc
+// @Summary     取消悬赏令
+// @Description 发布者取消悬赏令：未承接时直接取消并全额退款；进行中时可支付违约金立即取消，否则需接收者同意；进入结算后不可取消。取消后待处理的申请自动拒绝并通知申请人
+// @Tags        bounty
+// @Security    BearerAuth
+// @Accept      json
+// @Produce     json
+// @Param       id  path     string              true "悬赏令 ID"
+// @Param       req body     CancelBountyRequest true "取消原因与违约金"
+// @Success     200 {object} BountyResponse
+// @Failure     400 {object} ErrorResponse "参数格式错误或违约金无效"
+// @Failure     401 {object} ErrorResponse "未授权"
+// @Failure     403 {object} ErrorResponse "不是发布者"
+// @Failure     404 {object} ErrorResponse "未找到赏金任务"
+// @Failure     409 {object} ErrorResponse "当前状态不允许取消或已有待确认的取消申请"
+// @Failure     500 {object} ErrorResponse "服务器内部错误"
+// @Router      /api/bounties/{id}/cancel [post]
+func (ctl *BountyController) Cancel(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: "invalid bounty ID"})
+		return
+	}
+
+	var req CancelBountyRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: err.Error()})
+		return
+	}
+	if req.KillFee < 0 {
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: "kill_fee must not be negative"})
+		return
+	}
+
+	raw, _ := c.Get("userID")
+	userID := raw.(uuid.UUID)
+
+	b, err := ctl.svc.CancelBounty(&service.CancelBountyInput{
+		BountyID: id,
+		OwnerID:  userID,
+		Reason:   req.Reason,
+		KillFee:  req.KillFee,
+	})
+	if err != nil {
+		handleError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, toResponse(b))
+}
+
+// RespondCancellation godoc
+// @Summary     处理取消申请
+// @Description 接收者同意（赏金全额退回发布者）或拒绝（悬赏令继续进行）发布者的取消申请
+// @Tags        bounty
+// @Security    BearerAuth
+// @Accept      json
+// @Produce     json
+// @Param       id  path     string                     true "悬赏令 ID"
+// @Param       req body     RespondCancellationRequest true "是否同意"
+// @Success     200 {object} BountyResponse
+// @Failure     400 {object} ErrorResponse "参数格式错误"
+// @Failure     401 {object} ErrorResponse "未授权"
+// @Failure     403 {object} ErrorResponse "不是接收者"
+// @Failure     404 {object} ErrorResponse "未找到赏金任务"
+// @Failure     409 {object} ErrorResponse "没有待处理的取消申请"
+// @Failure     500 {object} ErrorResponse "服务器内部错误"
+// @Router      /api/bounties/{id}/cancel/respond [post]
+func (ctl *BountyController) RespondCancellation(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: "invalid bounty ID"})
+		return
+	}
+
+	var req RespondCancellationRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: err.Error()})
+		return
+	}
+
+	raw, _ := c.Get("userID")
+	userID := raw.(uuid.UUID)
+
+	b, err := ctl.svc.RespondCancellation(id, userID, *req.Accept)
+	if err != nil {
+		handleError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, toResponse(b))
+}
+
+// ListMilestones godoc
+// @Summary     列出里程碑
+// @Description 按顺序获取分阶段悬赏令的全部里程碑及其结算进度
+// @Tags        bounty
+// @Security    BearerAuth
+// @Produce     json
+// @Param       id  path     string true "悬赏令 ID"
+// @Success     200 {object} pagination.Result[MilestoneResponse]
+// @Failure     400 {object} ErrorResponse "无效的 ID"
+// @Failure     404 {object} ErrorResponse "未找到赏金任务"
+// @Failure     500 {object} ErrorResponse "服务器内部错误"
+// @Router      /api/bounties/{id}/milestones [get]
+func (ctl *BountyController) ListMilestones(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: "invalid bounty ID"})
+		return
+	}
+
+	raw, _ := c.Get("userID")
+	userID := raw.(uuid.UUID)
+
+	list, err := ctl.svc.ListMilestones(id, userID)
+	if err != nil {
+		handleError(c, err)
+		return
+	}
+	// 里程碑数量有限，不分页
+	c.JSON(http.StatusOK, pagination.All(toMilestoneResponses(list)))
+}
+
+// RequestMilestoneSettlement godoc
+// @Summary     发起里程碑结算
+// @Description 接收者完成某个里程碑后发起结算，须按顺序进行；最后一个里程碑会使悬赏令进入待结算
+// @Tags        bounty
+// @Security    BearerAuth
+// @Produce     json
+// @Param       id           path     string true "悬赏令 ID"
+// @Param       milestone_id path     string true "里程碑 ID"
+// @Success     200 {object} BountyResponse
+// @Failure     400 {object} ErrorResponse "无效的 ID"
+// @Failure     403 {object} ErrorResponse "不是接收者"
+// @Failure     404 {object} ErrorResponse "未找到悬赏令或里程碑"
+// @Failure     409 {object} ErrorResponse "里程碑状态或顺序不允许结算"
+// @Failure     500 {object} ErrorResponse "服务器内部错误"
+// @Router      /api/bounties/{id}/milestones/{milestone_id}/request-settlement [post]
+func (ctl *BountyController) RequestMilestoneSettlement(c *gin.Context) {
+	raw, _ := c.Get("userID")
+	userID := raw.(uuid.UUID)
+
+	bID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: "invalid bounty ID"})
+		return
+	}
+	mID, err := uuid.Parse(c.Param("milestone_id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: "invalid milestone ID"})
+		return
+	}
+
+	b, err := ctl.svc.RequestMilestoneSettlement(bID, mID, userID)
+	if err != nil {
+		handleError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, toResponse(b))
+}
+
+// ConfirmMilestoneSettlement godoc
+// @Summary     确认里程碑结算
+// @Description 发布者确认某个里程碑，从托管中发放该阶段赏金；确认最后一个里程碑即完成整体结算
+// @Tags        bounty
+// @Security    BearerAuth
+// @Produce     json
+// @Param       id           path     string true "悬赏令 ID"
+// @Param       milestone_id path     string true "里程碑 ID"
+// @Success     200 {object} BountyResponse
+// @Failure     400 {object} ErrorResponse "无效的 ID"
+// @Failure     403 {object} ErrorResponse "不是发布者"
+// @Failure     404 {object} ErrorResponse "未找到悬赏令或里程碑"
+// @Failure     409 {object} ErrorResponse "里程碑尚未发起结算"
+// @Failure     500 {object} ErrorResponse "服务器内部错误"
+// @Router      /api/bounties/{id}/milestones/{milestone_id}/confirm-settlement [post]
+func (ctl *BountyController) ConfirmMilestoneSettlement(c *gin.Context) {
+	raw, _ := c.Get("userID")
+	userID := raw.(uuid.UUID)
+
+	bID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: "invalid bounty ID"})
+		return
+	}
+	mID, err := uuid.Parse(c.Param("milestone_id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: "invalid milestone ID"})
+		return
+	}
+
+	b, err := ctl.svc.ConfirmMilestoneSettlement(bID, mID, userID)
+	if err != nil {
+		handleError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, toResponse(b))
+}
+
+// parseMilestones 将请求中的里程碑转换为 Service 层输入
+func parseMilestones(reqs []MilestoneRequest) ([]service.MilestoneInput, error) {
+	if reqs == nil {
+		return nil, nil
+	}
+	list := make([]service.MilestoneInput, len(reqs))
+	for i, m := range reqs {
+		list[i] = service.MilestoneInput{
+			Title:       m.Title,
+			Deliverable: m.Deliverable,
+			Amount:      m.Amount,
+		}
+		if m.DueDate != nil {
+			parsed, err := time.Parse(time.RFC3339, *m.DueDate)
+			if err != nil {
+				return nil, errors.New("invalid milestone due_date format; use RFC3339")
+			}
+			list[i].DueDate = &parsed
+		}
+	}
+	return list, nil
+}
+
+// parseGeoPoint 解析 "lat,lng" 格式的坐标
+func parseGeoPoint(s string) (*service.GeoPoint, error) {
+	parts := strings.Split(s, ",")
+	if len(parts) != 2 {
+		return nil, errors.New("invalid near; use lat,lng")
+	}
+	lat, err1 := strconv.ParseFloat(strings.TrimSpace(parts[0]), 64)
+	lng, err2 := strconv.ParseFloat(strings.TrimSpace(parts[1]), 64)
+	if err1 != nil || err2 != nil {
+		return nil, errors.New("invalid near; use lat,lng")
+	}
+	return &service.GeoPoint{Lat: lat, Lng: lng}, nil
+}
+
+// toResponse 将 dao.Bounty 转为返回体
+func toResponse(b *dao.Bounty) BountyResponse {
+	resp := BountyResponse{
+		ID:          b.ID,
+		Title:       b.Title,
+		Description: b.Description,
+		Reward:      b.Reward,
+		Currency:    b.Currency,
+		UserID:      b.UserID,
+		Deadline:    b.Deadline,
+		Category:    b.Category,
+		Tags:        b.Tags,
+		Priority:    b.Priority,
+		Status:      string(b.Status),
+		CreatedAt:   b.CreatedAt,
+		UpdatedAt:   b.UpdatedAt,
+		OverdueAt:   b.OverdueAt,
+		PublishAt:   b.PublishAt,
+
+		CancelRequestedAt: b.CancelRequestedAt,
+		CancelReason:      b.CancelReason,
+		TemplateID:        b.TemplateID,
+		SeriesID:          b.SeriesID,
+		Occurrence:        b.Occurrence,
+		Visibility:        string(b.Visibility),
+		TeamID:            b.TeamID,
+		Attachments:       b.Attachments,
+		Location:          b.Location,
+		Latitude:          b.Latitude,
+		Longitude:         b.Longitude,
+		Communication:     b.Communication,
+		WatcherCount:      b.WatcherCount,
+		ViewCount:         b.ViewCount,
+		Milestones:        toMilestoneResponses(b.Milestones),
+	}
+	resp.Pledged, resp.EffectiveReward = sumRewards(b)
+	for _, au := range b.AllowedUsers {
+		resp.AllowedUserIDs = append(resp.AllowedUserIDs, au.UserID)
+	}
+	return resp
+}
+
+// toSimilarResponses 转换查重结果，按查看者裁剪悬赏令字段
+func toSimilarResponses(list []*repository.SimilarBounty, viewerID uuid.UUID) []SimilarBountyResponse {
+	var resp []SimilarBountyResponse
+	for _, sb := range list {
+		resp = append(resp, SimilarBountyResponse{
+			Bounty: toViewerResponse(sb.Bounty, viewerID),
+			Score:  sb.Score,
+			Own:    sb.Bounty.UserID == viewerID,
+		})
+	}
+	return resp
+}
+
+// toViewerResponse 按查看者裁剪返回体：白名单仅发布者可见
+func toViewerResponse(b *dao.Bounty, viewerID uuid.UUID) BountyResponse {
+	resp := toResponse(b)
+	if b.UserID != viewerID {
+		resp.AllowedUserIDs = nil
+	}
+	return resp
+}
+
+// sumRewards 按币种汇总众筹追加，并与基础赏金合计出实际赏金
+func sumRewards(b *dao.Bounty) (pledged, effective map[string]float64) {
+	effective = map[string]float64{b.Currency: b.Reward}
+	if len(b.Contributions) == 0 {
+		return nil, effective
+	}
+	pledged = make(map[string]float64)
+	for _, ct := range b.Contributions {
+		pledged[ct.Currency] += ct.Amount
+		effective[ct.Currency] += ct.Amount
+	}
+	return pledged, effective
+}
+
+func toMilestoneResponses(list []dao.Milestone) []MilestoneResponse {
+	if len(list) == 0 {
+		return nil
+	}
+	resp := make([]MilestoneResponse, len(list))
+	for i, m := range list {
+		resp[i] = MilestoneResponse{
+			ID:                    m.ID,
+			Seq:                   m.Seq,
+			Title:                 m.Title,
+			Deliverable:           m.Deliverable,
+			Amount:                m.Amount,
+			DueDate:               m.DueDate,
+			Status:                string(m.Status),
+			SettlementRequestedAt: m.SettlementRequestedAt,
+			SettledAt:             m.SettledAt,
+		}
+	}
+	return resp
+}
+
+// handleError 将业务错误映射为 HTTP 状态码
+func handleError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, service.ErrBountyNotFound):
+		c.JSON(http.StatusNotFound, ErrorResponse{Error: err.Error()})
+	case errors.Is(err, repository.ErrMilestoneNotFound):
+		c.JSON(http.StatusNotFound, ErrorResponse{Error: err.Error()})
+	case errors.Is(err, service.ErrInvalidBountyStatus),
+		errors.Is(err, service.ErrMilestoneSumMismatch),
+		errors.Is(err, service.ErrInvalidMilestone),
+		errors.Is(err, service.ErrBountyIncomplete),
+		errors.Is(err, service.ErrInvalidVisibility),
+		errors.Is(err, service.ErrVisibilityTeamRequired),
+		errors.Is(err, service.ErrInvalidCoordinates),
+		errors.Is(err, service.ErrInvalidRadius),
+		errors.Is(err, service.ErrEmptySearchQuery),
+		errors.Is(err, service.ErrInvalidSort),
+		errors.Is(err, service.ErrInvalidRange),
+		errors.Is(err, service.ErrUnknownCategory),
+		errors.Is(err, service.ErrInvalidTrendingWindow),
+		errors.Is(err, service.ErrInvalidViewInterval),
+		errors.Is(err, service.ErrTooManyViewBuckets),
+		errors.Is(err, service.ErrInvalidDuplicateWindow),
+		errors.Is(err, service.ErrInvalidDuplicateThreshold),
+		errors.Is(err, pagination.ErrInvalidCursor),
+		errors.Is(err, repository.ErrInvalidKillFee):
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: err.Error()})
+	case errors.Is(err, service.ErrIllegalBountyTransition),
+		errors.Is(err, repository.ErrRewardLocked),
+		errors.Is(err, repository.ErrMilestonesLocked),
+		errors.Is(err, repository.ErrMilestoneOutOfOrder),
+		errors.Is(err, repository.ErrMilestoneNotPending),
+		errors.Is(err, repository.ErrMilestoneNotRequested),
+		errors.Is(err, repository.ErrBountyHasMilestones),
+		errors.Is(err, repository.ErrBountyNotInProgress),
+		errors.Is(err, repository.ErrCancelNotAllowed),
+		errors.Is(err, repository.ErrNoCancelRequest),
+		errors.Is(err, repository.ErrCancelAlreadyPending),
+		errors.Is(err, repository.ErrBountyNotClosed),
+		errors.Is(err, service.ErrNotDraft):
+		c.JSON(http.StatusConflict, ErrorResponse{Error: err.Error()})
+	case errors.Is(err, service.ErrInsufficientBalance):
+		c.JSON(http.StatusPaymentRequired, ErrorResponse{Error: err.Error()})
+	case errors.Is(err, repository.ErrNotBountyOwner),
+		errors.Is(err, repository.ErrNotBountyReceiver),
+		errors.Is(err, service.ErrNotTeamMember):
+		c.JSON(http.StatusForbidden, ErrorResponse{Error: err.Error()})
+	default:
+		c.JSON(http.StatusInternalServerError, ErrorResponse{Error: err.Error()})
+	}
+}
@@ -0,0 +1,437 @@
+package bountyseries
+
+import (
+	"errors"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"net/http"
+	"onepenny-server/internal/pagination"
+	"onepenny-server/internal/service"
+	"onepenny-server/model/dao"
+	"time"
+)
+
+// BountySeriesController 提供周期悬赏相关的 HTTP 接口
+type BountySeriesController struct {
+	svc service.BountySeriesService
+}
+
+// NewBountySeriesController 注入 BountySeriesService
+func NewBountySeriesController(svc service.BountySeriesService) *BountySeriesController {
+	return &BountySeriesController{svc: svc}
+}
+
+// CreateSeriesRequest 新建周期悬赏请求体；title/description 中可使用 {{n}}（第几期）与 {{date}}（生成日期）
+type CreateSeriesRequest struct {
+	Title       string   `json:"title" binding:"required"`
+	Description string   `json:"description" binding:"required"`
+	Reward      float64  `json:"reward" binding:"required,gt=0"`
+	Currency    string   `json:"currency" binding:"required"`
+	Category    string   `json:"category,omitempty"`
+	Tags        []string `json:"tags,omitempty"`
+	Priority    string   `json:"priority,omitempty"`
+
+	Cron               string  `json:"cron" binding:"required"`        // 如 "0 9 * * 1" 或 "@weekly"
+	Timezone           string  `json:"timezone,omitempty"`             // IANA 时区，默认 UTC
+	StartAt            *string `json:"start_at,omitempty"`             // RFC3339，第一期不早于该时间
+	DeadlineAfterHours int     `json:"deadline_after_hours,omitempty"` // 0 表示以下一期的生成时间为截止
+	EndAt              *string `json:"end_at,omitempty"`               // RFC3339
+	MaxOccurrences     int     `json:"max_occurrences,omitempty"`      // 0 表示不限
+}
+
+// UpdateSeriesRequest 更新周期悬赏请求体，修改只影响之后生成的期数
+type UpdateSeriesRequest struct {
+	Title       *string   `json:"title,omitempty"`
+	Description *string   `json:"description,omitempty"`
+	Reward      *float64  `json:"reward,omitempty"`
+	Currency    *string   `json:"currency,omitempty"`
+	Category    *string   `json:"category,omitempty"`
+	Tags        *[]string `json:"tags,omitempty"`
+	Priority    *string   `json:"priority,omitempty"`
+
+	Cron               *string `json:"cron,omitempty"`
+	Timezone           *string `json:"timezone,omitempty"`
+	DeadlineAfterHours *int    `json:"deadline_after_hours,omitempty"`
+	EndAt              *string `json:"end_at,omitempty"` // RFC3339
+	MaxOccurrences     *int    `json:"max_occurrences,omitempty"`
+}
+
+// SeriesResponse 周期悬赏返回体
+type SeriesResponse struct {
+	ID                 uuid.UUID  `json:"id"`
+	UserID             uuid.UUID  `json:"user_id"`
+	Title              string     `json:"title"`
+	Description        string     `json:"description"`
+	Reward             float64    `json:"reward"`
+	Currency           string     `json:"currency"`
+	Category           string     `json:"category,omitempty"`
+	Tags               []string   `json:"tags,omitempty"`
+	Priority           string     `json:"priority"`
+	Cron               string     `json:"cron"`
+	Timezone           string     `json:"timezone"`
+	DeadlineAfterHours int        `json:"deadline_after_hours"`
+	EndAt              *time.Time `json:"end_at,omitempty"`
+	MaxOccurrences     int        `json:"max_occurrences"`
+	Status             string     `json:"status"`
+	OccurrenceCount    int        `json:"occurrence_count"`
+	NextRunAt          *time.Time `json:"next_run_at,omitempty"`
+	LastError          string     `json:"last_error,omitempty"`
+	CreatedAt          time.Time  `json:"created_at"`
+	UpdatedAt          time.Time  `json:"updated_at"`
+}
+
+// OccurrenceResponse 系列中的一期悬赏令
+type OccurrenceResponse struct {
+	ID         uuid.UUID  `json:"id"`
+	Occurrence int        `json:"occurrence"`
+	Title      string     `json:"title"`
+	Reward     float64    `json:"reward"`
+	Currency   string     `json:"currency"`
+	Status     string     `json:"status"`
+	Deadline   *time.Time `json:"deadline,omitempty"`
+	ReceiverID *uuid.UUID `json:"receiver_id,omitempty"`
+	CreatedAt  time.Time  `json:"created_at"`
+}
+
+// ErrorResponse 通用错误返回体
+type ErrorResponse struct {
+	Error string `json:"error"`
+}
+
+// Create godoc
+// @Summary     创建周期悬赏
+// @Description 按 cron 计划定期自动发布悬赏令，每期赏金在发布时从当前用户钱包托管
+// @Tags        bounty-series
+// @Security    BearerAuth
+// @Accept      json
+// @Produce     json
+// @Param       req body     CreateSeriesRequest true "每期内容与计划"
+// @Success     201 {object} SeriesResponse
+// @Failure     400 {object} ErrorResponse "参数格式错误、cron 或时区无效"
+// @Failure     401 {object} ErrorResponse "未授权"
+// @Failure     500 {object} ErrorResponse "服务器内部错误"
+// @Router      /api/bounty-series [post]
+func (ctl *BountySeriesController) Create(c *gin.Context) {
+	var req CreateSeriesRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: err.Error()})
+		return
+	}
+
+	startAt, err := parseTime(req.StartAt)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: "invalid start_at format; use RFC3339"})
+		return
+	}
+	endAt, err := parseTime(req.EndAt)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: "invalid end_at format; use RFC3339"})
+		return
+	}
+
+	raw, _ := c.Get("userID")
+	userID := raw.(uuid.UUID)
+
+	series, err := ctl.svc.CreateSeries(&service.CreateSeriesInput{
+		UserID:             userID,
+		Title:              req.Title,
+		Description:        req.Description,
+		Reward:             req.Reward,
+		Currency:           req.Currency,
+		Category:           req.Category,
+		Tags:               req.Tags,
+		Priority:           req.Priority,
+		Cron:               req.Cron,
+		Timezone:           req.Timezone,
+		StartAt:            startAt,
+		DeadlineAfterHours: req.DeadlineAfterHours,
+		EndAt:              endAt,
+		MaxOccurrences:     req.MaxOccurrences,
+	})
+	if err != nil {
+		handleError(c, err)
+		return
+	}
+	c.JSON(http.StatusCreated, toResponse(series))
+}
+
+// List godoc
+// @Summary     列出我的周期悬赏
+// @Tags        bounty-series
+// @Security    BearerAuth
+// @Produce     json
+// @Param       cursor     query string false "上一页返回的 next_cursor，为空表示第一页"
+// @Param       size       query int    false "每页大小" default(20)
+// @Param       with_total query bool   false "是否返回总数"
+// @Success     200  {object} pagination.Result[SeriesResponse]
+// @Failure     401  {object} ErrorResponse "未授权"
+// @Failure     500  {object} ErrorResponse "服务器内部错误"
+// @Router      /api/bounty-series [get]
+func (ctl *BountySeriesController) List(c *gin.Context) {
+	raw, _ := c.Get("userID")
+	userID := raw.(uuid.UUID)
+
+	page, err := pagination.FromQuery(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: err.Error()})
+		return
+	}
+	list, err := ctl.svc.ListSeries(userID, page)
+	if err != nil {
+		handleError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, pagination.Map(list, toResponse))
+}
+
+// Get godoc
+// @Summary     获取周期悬赏
+// @Tags        bounty-series
+// @Security    BearerAuth
+// @Produce     json
+// @Param       id  path     string true "系列 ID"
+// @Success     200 {object} SeriesResponse
+// @Failure     400 {object} ErrorResponse "无效的 ID"
+// @Failure     403 {object} ErrorResponse "不是发布者"
+// @Failure     404 {object} ErrorResponse "未找到系列"
+// @Failure     500 {object} ErrorResponse "服务器内部错误"
+// @Router      /api/bounty-series/{id} [get]
+func (ctl *BountySeriesController) Get(c *gin.Context) {
+	id, userID, ok := parseIDs(c)
+	if !ok {
+		return
+	}
+	series, err := ctl.svc.GetSeries(id, userID)
+	if err != nil {
+		handleError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, toResponse(series))
+}
+
+// Update godoc
+// @Summary     修改周期悬赏
+// @Description 整体修改系列的内容、计划或结束条件，已生成的悬赏令不受影响
+// @Tags        bounty-series
+// @Security    BearerAuth
+// @Accept      json
+// @Produce     json
+// @Param       id  path     string              true "系列 ID"
+// @Param       req body     UpdateSeriesRequest true "需要修改的字段"
+// @Success     200 {object} SeriesResponse
+// @Failure     400 {object} ErrorResponse "参数格式错误、cron 或时区无效"
+// @Failure     403 {object} ErrorResponse "不是发布者"
+// @Failure     404 {object} ErrorResponse "未找到系列"
+// @Failure     409 {object} ErrorResponse "系列已结束"
+// @Failure     500 {object} ErrorResponse "服务器内部错误"
+// @Router      /api/bounty-series/{id} [put]
+func (ctl *BountySeriesController) Update(c *gin.Context) {
+	id, userID, ok := parseIDs(c)
+	if !ok {
+		return
+	}
+
+	var req UpdateSeriesRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: err.Error()})
+		return
+	}
+	endAt, err := parseTime(req.EndAt)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: "invalid end_at format; use RFC3339"})
+		return
+	}
+
+	series, err := ctl.svc.UpdateSeries(id, userID, &service.UpdateSeriesInput{
+		Title:              req.Title,
+		Description:        req.Description,
+		Reward:             req.Reward,
+		Currency:           req.Currency,
+		Category:           req.Category,
+		Tags:               req.Tags,
+		Priority:           req.Priority,
+		Cron:               req.Cron,
+		Timezone:           req.Timezone,
+		DeadlineAfterHours: req.DeadlineAfterHours,
+		EndAt:              endAt,
+		MaxOccurrences:     req.MaxOccurrences,
+	})
+	if err != nil {
+		handleError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, toResponse(series))
+}
+
+// Pause godoc
+// @Summary     暂停周期悬赏
+// @Tags        bounty-series
+// @Security    BearerAuth
+// @Produce     json
+// @Param       id  path     string true "系列 ID"
+// @Success     200 {object} SeriesResponse
+// @Failure     403 {object} ErrorResponse "不是发布者"
+// @Failure     404 {object} ErrorResponse "未找到系列"
+// @Failure     409 {object} ErrorResponse "系列已结束"
+// @Router      /api/bounty-series/{id}/pause [post]
+func (ctl *BountySeriesController) Pause(c *gin.Context) {
+	ctl.changeState(c, ctl.svc.PauseSeries)
+}
+
+// Resume godoc
+// @Summary     恢复周期悬赏
+// @Description 从当前时间起重新计算下一期，暂停期间错过的期数不补发
+// @Tags        bounty-series
+// @Security    BearerAuth
+// @Produce     json
+// @Param       id  path     string true "系列 ID"
+// @Success     200 {object} SeriesResponse
+// @Failure     403 {object} ErrorResponse "不是发布者"
+// @Failure     404 {object} ErrorResponse "未找到系列"
+// @Failure     409 {object} ErrorResponse "系列已结束或不会再有下一期"
+// @Router      /api/bounty-series/{id}/resume [post]
+func (ctl *BountySeriesController) Resume(c *gin.Context) {
+	ctl.changeState(c, ctl.svc.ResumeSeries)
+}
+
+// End godoc
+// @Summary     结束周期悬赏
+// @Description 不再生成新的期数，已生成的悬赏令不受影响
+// @Tags        bounty-series
+// @Security    BearerAuth
+// @Produce     json
+// @Param       id  path     string true "系列 ID"
+// @Success     200 {object} SeriesResponse
+// @Failure     403 {object} ErrorResponse "不是发布者"
+// @Failure     404 {object} ErrorResponse "未找到系列"
+// @Router      /api/bounty-series/{id}/end [post]
+func (ctl *BountySeriesController) End(c *gin.Context) {
+	ctl.changeState(c, ctl.svc.EndSeries)
+}
+
+// ListOccurrences godoc
+// @Summary     列出系列的各期悬赏令
+// @Tags        bounty-series
+// @Security    BearerAuth
+// @Produce     json
+// @Param       id         path  string true  "系列 ID"
+// @Param       cursor     query string false "上一页返回的 next_cursor，为空表示第一页"
+// @Param       size       query int    false "每页大小" default(20)
+// @Param       with_total query bool   false "是否返回总数"
+// @Success     200  {object} pagination.Result[OccurrenceResponse]
+// @Failure     403  {object} ErrorResponse "不是发布者"
+// @Failure     404  {object} ErrorResponse "未找到系列"
+// @Failure     500  {object} ErrorResponse "服务器内部错误"
+// @Router      /api/bounty-series/{id}/occurrences [get]
+func (ctl *BountySeriesController) ListOccurrences(c *gin.Context) {
+	id, userID, ok := parseIDs(c)
+	if !ok {
+		return
+	}
+	page, err := pagination.FromQuery(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: err.Error()})
+		return
+	}
+	list, err := ctl.svc.ListOccurrences(id, userID, page)
+	if err != nil {
+		handleError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, pagination.Map(list, func(b *dao.Bounty) OccurrenceResponse {
+		return OccurrenceResponse{
+			ID:         b.ID,
+			Occurrence: b.Occurrence,
+			Title:      b.Title,
+			Reward:     b.Reward,
+			Currency:   b.Currency,
+			Status:     string(b.Status),
+			Deadline:   b.Deadline,
+			ReceiverID: b.ReceiverID,
+			CreatedAt:  b.CreatedAt,
+		}
+	}))
+}
+
+// changeState 暂停／恢复／结束共用的处理流程
+func (ctl *BountySeriesController) changeState(c *gin.Context, fn func(id, userID uuid.UUID) (*dao.BountySeries, error)) {
+	id, userID, ok := parseIDs(c)
+	if !ok {
+		return
+	}
+	series, err := fn(id, userID)
+	if err != nil {
+		handleError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, toResponse(series))
+}
+
+// parseIDs 解析路径中的系列 ID 与当前用户 ID
+func parseIDs(c *gin.Context) (uuid.UUID, uuid.UUID, bool) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: "invalid series ID"})
+		return uuid.Nil, uuid.Nil, false
+	}
+	raw, _ := c.Get("userID")
+	return id, raw.(uuid.UUID), true
+}
+
+func parseTime(s *string) (*time.Time, error) {
+	if s == nil {
+		return nil, nil
+	}
+	t, err := time.Parse(time.RFC3339, *s)
+	if err != nil {
+		return nil, err
+	}
+	return &t, nil
+}
+
+// toResponse 将 dao.BountySeries 转为返回体
+func toResponse(s *dao.BountySeries) SeriesResponse {
+	return SeriesResponse{
+		ID:                 s.ID,
+		UserID:             s.UserID,
+		Title:              s.Title,
+		Description:        s.Description,
+		Reward:             s.Reward,
+		Currency:           s.Currency,
+		Category:           s.Category,
+		Tags:               s.Tags,
+		Priority:           s.Priority,
+		Cron:               s.Cron,
+		Timezone:           s.Timezone,
+		DeadlineAfterHours: s.DeadlineAfterHours,
+		EndAt:              s.EndAt,
+		MaxOccurrences:     s.MaxOccurrences,
+		Status:             string(s.Status),
+		OccurrenceCount:    s.OccurrenceCount,
+		NextRunAt:          s.NextRunAt,
+		LastError:          s.LastError,
+		CreatedAt:          s.CreatedAt,
+		UpdatedAt:          s.UpdatedAt,
+	}
+}
+
+// handleError 将业务错误映射为 HTTP 状态码
+func handleError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, service.ErrSeriesNotFound):
+		c.JSON(http.StatusNotFound, ErrorResponse{Error: err.Error()})
+	case errors.Is(err, service.ErrNotSeriesOwner):
+		c.JSON(http.StatusForbidden, ErrorResponse{Error: err.Error()})
+	case errors.Is(err, service.ErrInvalidCron),
+		errors.Is(err, service.ErrInvalidTimezone),
+		errors.Is(err, service.ErrInvalidReward),
+		errors.Is(err, service.ErrInvalidSeriesLimit),
+		errors.Is(err, service.ErrUnknownCategory):
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: err.Error()})
+	case errors.Is(err, service.ErrSeriesEnded),
+		errors.Is(err, service.ErrSeriesNoRuns):
+		c.JSON(http.StatusConflict, ErrorResponse{Error: err.Error()})
+	default:
+		c.JSON(http.StatusInternalServerError, ErrorResponse{Error: err.Error()})
+	}
+}
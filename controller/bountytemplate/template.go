@@ -0,0 +1,416 @@
+package bountytemplate
+
+import (
+	"errors"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"net/http"
+	"onepenny-server/internal/pagination"
+	"onepenny-server/internal/service"
+	"onepenny-server/model/dao"
+	"time"
+)
+
+// BountyTemplateController 提供悬赏令模板相关的 HTTP 接口
+type BountyTemplateController struct {
+	svc service.BountyTemplateService
+}
+
+// NewBountyTemplateController 注入 BountyTemplateService
+func NewBountyTemplateController(svc service.BountyTemplateService) *BountyTemplateController {
+	return &BountyTemplateController{svc: svc}
+}
+
+// CreateTemplateRequest 新建模板请求体；title/description 中可使用 {{name}} 占位符
+type CreateTemplateRequest struct {
+	Name        string   `json:"name" binding:"required"`
+	TeamID      *string  `json:"team_id,omitempty" binding:"omitempty,uuid"` // 不填为个人模板
+	Title       string   `json:"title" binding:"required"`
+	Description string   `json:"description,omitempty"`
+	Reward      float64  `json:"reward,omitempty"`
+	Currency    string   `json:"currency,omitempty"`
+	Category    string   `json:"category,omitempty"`
+	Tags        []string `json:"tags,omitempty"`
+	Priority    string   `json:"priority,omitempty"`
+}
+
+// UpdateTemplateRequest 更新模板请求体
+type UpdateTemplateRequest struct {
+	Name        *string   `json:"name,omitempty"`
+	Title       *string   `json:"title,omitempty"`
+	Description *string   `json:"description,omitempty"`
+	Reward      *float64  `json:"reward,omitempty"`
+	Currency    *string   `json:"currency,omitempty"`
+	Category    *string   `json:"category,omitempty"`
+	Tags        *[]string `json:"tags,omitempty"`
+	Priority    *string   `json:"priority,omitempty"`
+}
+
+// InstantiateRequest 实例化模板请求体：variables 用于替换占位符，其余字段覆盖模板默认值
+type InstantiateRequest struct {
+	Variables   map[string]string `json:"variables,omitempty"`
+	Title       *string           `json:"title,omitempty"`
+	Description *string           `json:"description,omitempty"`
+	Reward      *float64          `json:"reward,omitempty"`
+	Currency    *string           `json:"currency,omitempty"`
+	Deadline    *string           `json:"deadline,omitempty"` // RFC3339
+	Category    *string           `json:"category,omitempty"`
+	Tags        *[]string         `json:"tags,omitempty"`
+	Priority    *string           `json:"priority,omitempty"`
+}
+
+// TemplateResponse 模板返回体
+type TemplateResponse struct {
+	ID          uuid.UUID  `json:"id"`
+	UserID      uuid.UUID  `json:"user_id"`
+	TeamID      *uuid.UUID `json:"team_id,omitempty"`
+	Name        string     `json:"name"`
+	Title       string     `json:"title"`
+	Description string     `json:"description,omitempty"`
+	Reward      float64    `json:"reward"`
+	Currency    string     `json:"currency"`
+	Category    string     `json:"category,omitempty"`
+	Tags        []string   `json:"tags,omitempty"`
+	Priority    string     `json:"priority"`
+	CreatedAt   time.Time  `json:"created_at"`
+	UpdatedAt   time.Time  `json:"updated_at"`
+}
+
+// BountyResponse 实例化后发布的悬赏令
+type BountyResponse struct {
+	ID          uuid.UUID  `json:"id"`
+	TemplateID  uuid.UUID  `json:"template_id"`
+	Title       string     `json:"title"`
+	Description string     `json:"description"`
+	Reward      float64    `json:"reward"`
+	Currency    string     `json:"currency"`
+	UserID      uuid.UUID  `json:"user_id"`
+	Deadline    *time.Time `json:"deadline,omitempty"`
+	Category    string     `json:"category,omitempty"`
+	Tags        []string   `json:"tags,omitempty"`
+	Priority    string     `json:"priority"`
+	Status      string     `json:"status"`
+	CreatedAt   time.Time  `json:"created_at"`
+}
+
+// ErrorResponse 通用错误返回体
+type ErrorResponse struct {
+	Error string `json:"error"`
+}
+
+// Create godoc
+// @Summary     创建悬赏令模板
+// @Description 创建个人模板，或为所在团队创建团队模板
+// @Tags        bounty-template
+// @Security    BearerAuth
+// @Accept      json
+// @Produce     json
+// @Param       req body     CreateTemplateRequest true "模板内容"
+// @Success     201 {object} TemplateResponse
+// @Failure     400 {object} ErrorResponse "参数格式错误"
+// @Failure     401 {object} ErrorResponse "未授权"
+// @Failure     403 {object} ErrorResponse "不是该团队成员"
+// @Failure     500 {object} ErrorResponse "服务器内部错误"
+// @Router      /api/bounty-templates [post]
+func (ctl *BountyTemplateController) Create(c *gin.Context) {
+	var req CreateTemplateRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: err.Error()})
+		return
+	}
+
+	raw, _ := c.Get("userID")
+	userID := raw.(uuid.UUID)
+
+	var teamID *uuid.UUID
+	if req.TeamID != nil {
+		id := uuid.MustParse(*req.TeamID)
+		teamID = &id
+	}
+
+	t, err := ctl.svc.CreateTemplate(&service.BountyTemplateInput{
+		UserID:      userID,
+		TeamID:      teamID,
+		Name:        req.Name,
+		Title:       req.Title,
+		Description: req.Description,
+		Reward:      req.Reward,
+		Currency:    req.Currency,
+		Category:    req.Category,
+		Tags:        req.Tags,
+		Priority:    req.Priority,
+	})
+	if err != nil {
+		handleError(c, err)
+		return
+	}
+	c.JSON(http.StatusCreated, toResponse(t))
+}
+
+// List godoc
+// @Summary     列出悬赏令模板
+// @Description 分页列出当前用户的个人模板及所在团队的模板，可按团队过滤
+// @Tags        bounty-template
+// @Security    BearerAuth
+// @Produce     json
+// @Param       team_id    query string false "只看该团队的模板"
+// @Param       cursor     query string false "上一页返回的 next_cursor，为空表示第一页"
+// @Param       size       query int    false "每页大小" default(20)
+// @Param       with_total query bool   false "是否返回总数"
+// @Success     200     {object} pagination.Result[TemplateResponse]
+// @Failure     400     {object} ErrorResponse "无效的团队 ID"
+// @Failure     401     {object} ErrorResponse "未授权"
+// @Failure     500     {object} ErrorResponse "服务器内部错误"
+// @Router      /api/bounty-templates [get]
+func (ctl *BountyTemplateController) List(c *gin.Context) {
+	raw, _ := c.Get("userID")
+	userID := raw.(uuid.UUID)
+
+	var teamID *uuid.UUID
+	if s := c.Query("team_id"); s != "" {
+		id, err := uuid.Parse(s)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, ErrorResponse{Error: "invalid team ID"})
+			return
+		}
+		teamID = &id
+	}
+
+	page, err := pagination.FromQuery(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: err.Error()})
+		return
+	}
+
+	list, err := ctl.svc.ListTemplates(userID, teamID, page)
+	if err != nil {
+		handleError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, pagination.Map(list, toResponse))
+}
+
+// Get godoc
+// @Summary     获取悬赏令模板
+// @Tags        bounty-template
+// @Security    BearerAuth
+// @Produce     json
+// @Param       id  path     string true "模板 ID"
+// @Success     200 {object} TemplateResponse
+// @Failure     400 {object} ErrorResponse "无效的 ID"
+// @Failure     403 {object} ErrorResponse "无权查看"
+// @Failure     404 {object} ErrorResponse "未找到模板"
+// @Failure     500 {object} ErrorResponse "服务器内部错误"
+// @Router      /api/bounty-templates/{id} [get]
+func (ctl *BountyTemplateController) Get(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: "invalid template ID"})
+		return
+	}
+
+	raw, _ := c.Get("userID")
+	userID := raw.(uuid.UUID)
+
+	t, err := ctl.svc.GetTemplate(id, userID)
+	if err != nil {
+		handleError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, toResponse(t))
+}
+
+// Update godoc
+// @Summary     更新悬赏令模板
+// @Description 仅模板创建者或所属团队的创建者可修改
+// @Tags        bounty-template
+// @Security    BearerAuth
+// @Accept      json
+// @Produce     json
+// @Param       id  path     string                true "模板 ID"
+// @Param       req body     UpdateTemplateRequest true "需要修改的字段"
+// @Success     200 {object} TemplateResponse
+// @Failure     400 {object} ErrorResponse "参数格式错误"
+// @Failure     403 {object} ErrorResponse "无权修改"
+// @Failure     404 {object} ErrorResponse "未找到模板"
+// @Failure     500 {object} ErrorResponse "服务器内部错误"
+// @Router      /api/bounty-templates/{id} [put]
+func (ctl *BountyTemplateController) Update(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: "invalid template ID"})
+		return
+	}
+
+	var req UpdateTemplateRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: err.Error()})
+		return
+	}
+
+	raw, _ := c.Get("userID")
+	userID := raw.(uuid.UUID)
+
+	t, err := ctl.svc.UpdateTemplate(id, userID, &service.UpdateBountyTemplateInput{
+		Name:        req.Name,
+		Title:       req.Title,
+		Description: req.Description,
+		Reward:      req.Reward,
+		Currency:    req.Currency,
+		Category:    req.Category,
+		Tags:        req.Tags,
+		Priority:    req.Priority,
+	})
+	if err != nil {
+		handleError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, toResponse(t))
+}
+
+// Delete godoc
+// @Summary     删除悬赏令模板
+// @Description 仅模板创建者或所属团队的创建者可删除，已发布的悬赏令不受影响
+// @Tags        bounty-template
+// @Security    BearerAuth
+// @Param       id  path     string true "模板 ID"
+// @Success     204 "No Content"
+// @Failure     400 {object} ErrorResponse "无效的 ID"
+// @Failure     403 {object} ErrorResponse "无权删除"
+// @Failure     404 {object} ErrorResponse "未找到模板"
+// @Failure     500 {object} ErrorResponse "服务器内部错误"
+// @Router      /api/bounty-templates/{id} [delete]
+func (ctl *BountyTemplateController) Delete(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: "invalid template ID"})
+		return
+	}
+
+	raw, _ := c.Get("userID")
+	userID := raw.(uuid.UUID)
+
+	if err := ctl.svc.DeleteTemplate(id, userID); err != nil {
+		handleError(c, err)
+		return
+	}
+	c.Status(http.StatusNoContent)
+}
+
+// Instantiate godoc
+// @Summary     用模板发布悬赏令
+// @Description 替换占位符并应用覆盖字段后，以当前用户身份发布悬赏令，赏金从当前用户钱包托管
+// @Tags        bounty-template
+// @Security    BearerAuth
+// @Accept      json
+// @Produce     json
+// @Param       id  path     string             true "模板 ID"
+// @Param       req body     InstantiateRequest false "占位符变量与覆盖字段"
+// @Success     201 {object} BountyResponse
+// @Failure     400 {object} ErrorResponse "参数格式错误、缺少占位符变量或赏金无效"
+// @Failure     402 {object} ErrorResponse "钱包余额不足以托管赏金"
+// @Failure     403 {object} ErrorResponse "无权使用该模板"
+// @Failure     404 {object} ErrorResponse "未找到模板"
+// @Failure     500 {object} ErrorResponse "服务器内部错误"
+// @Router      /api/bounty-templates/{id}/instantiate [post]
+func (ctl *BountyTemplateController) Instantiate(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: "invalid template ID"})
+		return
+	}
+
+	var req InstantiateRequest
+	if c.Request.ContentLength != 0 {
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusBadRequest, ErrorResponse{Error: err.Error()})
+			return
+		}
+	}
+
+	var dl *time.Time
+	if req.Deadline != nil {
+		parsed, err := time.Parse(time.RFC3339, *req.Deadline)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, ErrorResponse{Error: "invalid deadline format; use RFC3339"})
+			return
+		}
+		dl = &parsed
+	}
+
+	raw, _ := c.Get("userID")
+	userID := raw.(uuid.UUID)
+
+	b, err := ctl.svc.Instantiate(id, &service.InstantiateTemplateInput{
+		UserID:      userID,
+		Variables:   req.Variables,
+		Title:       req.Title,
+		Description: req.Description,
+		Reward:      req.Reward,
+		Currency:    req.Currency,
+		Deadline:    dl,
+		Category:    req.Category,
+		Tags:        req.Tags,
+		Priority:    req.Priority,
+	})
+	if err != nil {
+		handleError(c, err)
+		return
+	}
+	c.JSON(http.StatusCreated, BountyResponse{
+		ID:          b.ID,
+		TemplateID:  id,
+		Title:       b.Title,
+		Description: b.Description,
+		Reward:      b.Reward,
+		Currency:    b.Currency,
+		UserID:      b.UserID,
+		Deadline:    b.Deadline,
+		Category:    b.Category,
+		Tags:        b.Tags,
+		Priority:    b.Priority,
+		Status:      string(b.Status),
+		CreatedAt:   b.CreatedAt,
+	})
+}
+
+// toResponse 将 dao.BountyTemplate 转为返回体
+func toResponse(t *dao.BountyTemplate) TemplateResponse {
+	return TemplateResponse{
+		ID:          t.ID,
+		UserID:      t.UserID,
+		TeamID:      t.TeamID,
+		Name:        t.Name,
+		Title:       t.Title,
+		Description: t.Description,
+		Reward:      t.Reward,
+		Currency:    t.Currency,
+		Category:    t.Category,
+		Tags:        t.Tags,
+		Priority:    t.Priority,
+		CreatedAt:   t.CreatedAt,
+		UpdatedAt:   t.UpdatedAt,
+	}
+}
+
+// handleError 将业务错误映射为 HTTP 状态码
+func handleError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, service.ErrTemplateNotFound):
+		c.JSON(http.StatusNotFound, ErrorResponse{Error: err.Error()})
+	case errors.Is(err, service.ErrTemplateForbidden):
+		c.JSON(http.StatusForbidden, ErrorResponse{Error: err.Error()})
+	case errors.Is(err, service.ErrTemplateVariableMissing),
+		errors.Is(err, service.ErrInvalidReward),
+		errors.Is(err, service.ErrInvalidAmount),
+		errors.Is(err, service.ErrBountyIncomplete),
+		errors.Is(err, service.ErrMilestoneSumMismatch),
+		errors.Is(err, service.ErrUnknownCategory),
+		errors.Is(err, pagination.ErrInvalidCursor):
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: err.Error()})
+	case errors.Is(err, service.ErrInsufficientBalance):
+		c.JSON(http.StatusPaymentRequired, ErrorResponse{Error: err.Error()})
+	default:
+		c.JSON(http.StatusInternalServerError, ErrorResponse{Error: err.Error()})
+	}
+}
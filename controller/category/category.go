@@ -0,0 +1,253 @@
+package category
+
+import (
+	"errors"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"net/http"
+	"onepenny-server/internal/service"
+	"onepenny-server/model/dao"
+	"strings"
+)
+
+// CategoryController 提供分类树的查询与管理接口
+type CategoryController struct {
+	svc service.CategoryService
+}
+
+// NewCategoryController 注入 CategoryService
+func NewCategoryController(svc service.CategoryService) *CategoryController {
+	return &CategoryController{svc: svc}
+}
+
+// CategoryRequest 新建或修改分类请求体；修改时整体替换，Slug 不可变更
+type CategoryRequest struct {
+	Slug      string            `json:"slug,omitempty"`      // 新建时必填，小写字母、数字与连字符
+	ParentID  *uuid.UUID        `json:"parent_id,omitempty"` // 为空表示顶级分类
+	Names     map[string]string `json:"names" binding:"required"`
+	Icon      string            `json:"icon,omitempty"`
+	SortOrder int               `json:"sort_order,omitempty"`
+}
+
+// CategoryResponse 分类返回体，Name 为按请求语言选取的显示名称
+type CategoryResponse struct {
+	ID        uuid.UUID           `json:"id"`
+	Slug      string              `json:"slug"`
+	ParentID  *uuid.UUID          `json:"parent_id,omitempty"`
+	Path      string              `json:"path"`
+	Name      string              `json:"name"`
+	Names     map[string]string   `json:"names"`
+	Icon      string              `json:"icon,omitempty"`
+	SortOrder int                 `json:"sort_order"`
+	Children  []*CategoryResponse `json:"children,omitempty"`
+}
+
+// ErrorResponse 通用错误返回体
+type ErrorResponse struct {
+	Error string `json:"error"`
+}
+
+// Tree godoc
+// @Summary     分类树
+// @Description 返回完整的分类树，同级按排序值升序；显示名称按 lang 参数或 Accept-Language 选取，缺少时回退到英文
+// @Tags        category
+// @Security    BearerAuth
+// @Produce     json
+// @Param       lang query    string false "显示语言，如 zh、en"
+// @Success     200  {array}  CategoryResponse
+// @Failure     401  {object} ErrorResponse "未授权"
+// @Failure     500  {object} ErrorResponse "服务器内部错误"
+// @Router      /api/categories [get]
+func (ctl *CategoryController) Tree(c *gin.Context) {
+	roots, err := ctl.svc.Tree()
+	if err != nil {
+		handleError(c, err)
+		return
+	}
+	locale := requestLocale(c)
+	resp := make([]*CategoryResponse, 0, len(roots))
+	for _, n := range roots {
+		resp = append(resp, toTreeResponse(n, locale))
+	}
+	c.JSON(http.StatusOK, resp)
+}
+
+// Get godoc
+// @Summary     分类详情
+// @Tags        category
+// @Security    BearerAuth
+// @Produce     json
+// @Param       slug path     string true  "分类 Slug"
+// @Param       lang query    string false "显示语言，如 zh、en"
+// @Success     200  {object} CategoryResponse
+// @Failure     401  {object} ErrorResponse "未授权"
+// @Failure     404  {object} ErrorResponse "未找到分类"
+// @Failure     500  {object} ErrorResponse "服务器内部错误"
+// @Router      /api/categories/{slug} [get]
+func (ctl *CategoryController) Get(c *gin.Context) {
+	cat, err := ctl.svc.GetCategory(c.Param("slug"))
+	if err != nil {
+		handleError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, toResponse(cat, requestLocale(c)))
+}
+
+// Create godoc
+// @Summary     新建分类
+// @Description 新建顶级分类或子分类（仅管理员）
+// @Tags        admin
+// @Security    BearerAuth
+// @Accept      json
+// @Produce     json
+// @Param       req body     CategoryRequest true "分类信息"
+// @Success     201 {object} CategoryResponse
+// @Failure     400 {object} ErrorResponse "参数格式错误"
+// @Failure     401 {object} ErrorResponse "未授权"
+// @Failure     403 {object} ErrorResponse "权限不足"
+// @Failure     404 {object} ErrorResponse "未找到父分类"
+// @Failure     409 {object} ErrorResponse "Slug 已存在"
+// @Failure     500 {object} ErrorResponse "服务器内部错误"
+// @Router      /api/admin/categories [post]
+func (ctl *CategoryController) Create(c *gin.Context) {
+	var req CategoryRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: err.Error()})
+		return
+	}
+
+	cat, err := ctl.svc.CreateCategory(toInput(&req))
+	if err != nil {
+		handleError(c, err)
+		return
+	}
+	c.JSON(http.StatusCreated, toResponse(cat, requestLocale(c)))
+}
+
+// Update godoc
+// @Summary     修改分类
+// @Description 修改名称、图标、排序或父分类（仅管理员）；移动分类时其子孙分类随之移动
+// @Tags        admin
+// @Security    BearerAuth
+// @Accept      json
+// @Produce     json
+// @Param       id  path     string          true "分类 ID"
+// @Param       req body     CategoryRequest true "分类信息"
+// @Success     200 {object} CategoryResponse
+// @Failure     400 {object} ErrorResponse "参数格式错误或移动到自身之下"
+// @Failure     401 {object} ErrorResponse "未授权"
+// @Failure     403 {object} ErrorResponse "权限不足"
+// @Failure     404 {object} ErrorResponse "未找到分类"
+// @Failure     500 {object} ErrorResponse "服务器内部错误"
+// @Router      /api/admin/categories/{id} [put]
+func (ctl *CategoryController) Update(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: "invalid category ID"})
+		return
+	}
+
+	var req CategoryRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: err.Error()})
+		return
+	}
+
+	cat, err := ctl.svc.UpdateCategory(id, toInput(&req))
+	if err != nil {
+		handleError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, toResponse(cat, requestLocale(c)))
+}
+
+// Delete godoc
+// @Summary     删除分类
+// @Description 删除没有子分类且未被悬赏令、模板或周期悬赏使用的分类（仅管理员）
+// @Tags        admin
+// @Security    BearerAuth
+// @Param       id path string true "分类 ID"
+// @Success     204 "No Content"
+// @Failure     400 {object} ErrorResponse "无效的 ID"
+// @Failure     401 {object} ErrorResponse "未授权"
+// @Failure     403 {object} ErrorResponse "权限不足"
+// @Failure     404 {object} ErrorResponse "未找到分类"
+// @Failure     409 {object} ErrorResponse "仍有子分类或仍被使用"
+// @Failure     500 {object} ErrorResponse "服务器内部错误"
+// @Router      /api/admin/categories/{id} [delete]
+func (ctl *CategoryController) Delete(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: "invalid category ID"})
+		return
+	}
+	if err := ctl.svc.DeleteCategory(id); err != nil {
+		handleError(c, err)
+		return
+	}
+	c.Status(http.StatusNoContent)
+}
+
+// requestLocale 取 lang 参数，未指定时取 Accept-Language 的首选语言，如 "zh-CN" 取 "zh"
+func requestLocale(c *gin.Context) string {
+	lang := c.Query("lang")
+	if lang == "" {
+		lang = strings.SplitN(c.GetHeader("Accept-Language"), ",", 2)[0]
+		lang = strings.SplitN(lang, ";", 2)[0]
+	}
+	lang = strings.SplitN(strings.TrimSpace(lang), "-", 2)[0]
+	if lang == "" {
+		return dao.DefaultCategoryLocale
+	}
+	return strings.ToLower(lang)
+}
+
+func toInput(req *CategoryRequest) *service.CategoryInput {
+	return &service.CategoryInput{
+		Slug:      req.Slug,
+		ParentID:  req.ParentID,
+		Names:     req.Names,
+		Icon:      req.Icon,
+		SortOrder: req.SortOrder,
+	}
+}
+
+// toResponse 将 dao.Category 转为返回体
+func toResponse(cat *dao.Category, locale string) *CategoryResponse {
+	return &CategoryResponse{
+		ID:        cat.ID,
+		Slug:      cat.Slug,
+		ParentID:  cat.ParentID,
+		Path:      cat.Path,
+		Name:      cat.DisplayName(locale),
+		Names:     cat.Names,
+		Icon:      cat.Icon,
+		SortOrder: cat.SortOrder,
+	}
+}
+
+func toTreeResponse(n *service.CategoryNode, locale string) *CategoryResponse {
+	resp := toResponse(n.Category, locale)
+	for _, child := range n.Children {
+		resp.Children = append(resp.Children, toTreeResponse(child, locale))
+	}
+	return resp
+}
+
+// handleError 将业务错误映射为 HTTP 状态码
+func handleError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, service.ErrCategoryNotFound):
+		c.JSON(http.StatusNotFound, ErrorResponse{Error: err.Error()})
+	case errors.Is(err, service.ErrInvalidCategorySlug),
+		errors.Is(err, service.ErrCategoryNameRequired),
+		errors.Is(err, service.ErrCategoryCycle):
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: err.Error()})
+	case errors.Is(err, service.ErrCategoryExists),
+		errors.Is(err, service.ErrCategoryHasChildren),
+		errors.Is(err, service.ErrCategoryInUse):
+		c.JSON(http.StatusConflict, ErrorResponse{Error: err.Error()})
+	default:
+		c.JSON(http.StatusInternalServerError, ErrorResponse{Error: err.Error()})
+	}
+}
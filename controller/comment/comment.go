@@ -1,11 +1,13 @@
 package comment
 
 import (
+	"errors"
 	"github.com/gin-gonic/gin"
 	"github.com/google/uuid"
 	"net/http"
+	"onepenny-server/internal/pagination"
 	"onepenny-server/internal/service"
-	"strconv"
+	"onepenny-server/model/dao"
 	"time"
 )
 
@@ -61,6 +63,7 @@ type UpdateCommentRequest struct {
 // @Success     201 {object} CommentResponse     "创建成功，返回新评论"
 // @Failure     400 {object} ErrorResponse       "参数格式错误"
 // @Failure     401 {object} ErrorResponse       "未授权"
+// @Failure     404 {object} ErrorResponse       "未找到赏金任务或无权查看"
 // @Failure     500 {object} ErrorResponse       "服务器内部错误"
 // @Router      /api/comments [post]
 func (ctl *CommentController) Add(c *gin.Context) {
@@ -110,7 +113,7 @@ func (ctl *CommentController) Add(c *gin.Context) {
 	}
 	cmt, err := ctl.svc.AddComment(input)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, ErrorResponse{Error: err.Error()})
+		handleError(c, err)
 		return
 	}
 
@@ -134,14 +137,19 @@ func (ctl *CommentController) Add(c *gin.Context) {
 // @Tags        comment
 // @Security    BearerAuth
 // @Produce     json
-// @Param       bountyId path      string  true  "赏金任务 ID"
-// @Param       page     query     int     false "页码"    default(1)
-// @Param       size     query     int     false "每页大小" default(20)
-// @Success     200      {array}   CommentResponse   "评论列表"
+// @Param       bountyId   path  string true  "赏金任务 ID"
+// @Param       cursor     query string false "上一页返回的 next_cursor，为空表示第一页"
+// @Param       size       query int    false "每页大小" default(20)
+// @Param       with_total query bool   false "是否返回总数"
+// @Success     200      {object}  pagination.Result[CommentResponse]   "评论列表"
 // @Failure     400      {object}  ErrorResponse     "无效的参数"
+// @Failure     404      {object}  ErrorResponse     "未找到赏金任务或无权查看"
 // @Failure     500      {object}  ErrorResponse     "服务器内部错误"
 // @Router      /api/comments/bounty/{bountyId} [get]
 func (ctl *CommentController) ListByBounty(c *gin.Context) {
+	raw, _ := c.Get("userID")
+	userID := raw.(uuid.UUID)
+
 	// 解析 bounty ID
 	bID, err := uuid.Parse(c.Param("bountyId"))
 	if err != nil {
@@ -150,29 +158,21 @@ func (ctl *CommentController) ListByBounty(c *gin.Context) {
 	}
 
 	// 分页参数
-	page, size := 1, 20
-	if p := c.Query("page"); p != "" {
-		if v, err := strconv.Atoi(p); err == nil && v > 0 {
-			page = v
-		}
-	}
-	if s := c.Query("size"); s != "" {
-		if v, err := strconv.Atoi(s); err == nil && v > 0 {
-			size = v
-		}
+	page, err := pagination.FromQuery(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: err.Error()})
+		return
 	}
 
 	// 调用业务层
-	list, err := ctl.svc.ListCommentsByBounty(bID, page, size)
+	list, err := ctl.svc.ListCommentsByBounty(bID, userID, page)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, ErrorResponse{Error: err.Error()})
+		handleError(c, err)
 		return
 	}
 
-	// 构造响应
-	resp := make([]CommentResponse, len(list))
-	for i, cmt := range list {
-		resp[i] = CommentResponse{
+	c.JSON(http.StatusOK, pagination.Map(list, func(cmt *dao.Comment) CommentResponse {
+		return CommentResponse{
 			ID:          cmt.ID,
 			UserID:      cmt.UserID,
 			BountyID:    cmt.BountyID,
@@ -182,8 +182,7 @@ func (ctl *CommentController) ListByBounty(c *gin.Context) {
 			CreatedAt:   cmt.CreatedAt,
 			UpdatedAt:   cmt.UpdatedAt,
 		}
-	}
-	c.JSON(http.StatusOK, resp)
+	}))
 }
 
 // ListReplies godoc
@@ -192,14 +191,19 @@ func (ctl *CommentController) ListByBounty(c *gin.Context) {
 // @Tags        comment
 // @Security    BearerAuth
 // @Produce     json
-// @Param       id    path      string  true  "父评论 ID"
-// @Param       page  query     int     false "页码"    default(1)
-// @Param       size  query     int     false "每页大小" default(20)
-// @Success     200   {array}   CommentResponse   "回复列表"
+// @Param       id         path  string true  "父评论 ID"
+// @Param       cursor     query string false "上一页返回的 next_cursor，为空表示第一页"
+// @Param       size       query int    false "每页大小" default(20)
+// @Param       with_total query bool   false "是否返回总数"
+// @Success     200   {object}  pagination.Result[CommentResponse]   "回复列表"
 // @Failure     400   {object}  ErrorResponse     "无效的参数"
+// @Failure     404   {object}  ErrorResponse     "未找到评论或无权查看所属赏金任务"
 // @Failure     500   {object}  ErrorResponse     "服务器内部错误"
 // @Router      /api/comments/{id}/replies [get]
 func (ctl *CommentController) ListReplies(c *gin.Context) {
+	raw, _ := c.Get("userID")
+	userID := raw.(uuid.UUID)
+
 	parentStr := c.Param("id")
 	parentID, err := uuid.Parse(parentStr)
 	if err != nil {
@@ -207,27 +211,20 @@ func (ctl *CommentController) ListReplies(c *gin.Context) {
 		return
 	}
 
-	page, size := 1, 20
-	if p := c.Query("page"); p != "" {
-		if v, err := strconv.Atoi(p); err == nil && v > 0 {
-			page = v
-		}
-	}
-	if s := c.Query("size"); s != "" {
-		if v, err := strconv.Atoi(s); err == nil && v > 0 {
-			size = v
-		}
+	page, err := pagination.FromQuery(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: err.Error()})
+		return
 	}
 
-	list, err := ctl.svc.ListReplies(parentID, page, size)
+	list, err := ctl.svc.ListReplies(parentID, userID, page)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, ErrorResponse{Error: err.Error()})
+		handleError(c, err)
 		return
 	}
 
-	resp := make([]CommentResponse, len(list))
-	for i, cmt := range list {
-		resp[i] = CommentResponse{
+	c.JSON(http.StatusOK, pagination.Map(list, func(cmt *dao.Comment) CommentResponse {
+		return CommentResponse{
 			ID:          cmt.ID,
 			UserID:      cmt.UserID,
 			BountyID:    cmt.BountyID,
@@ -237,8 +234,7 @@ func (ctl *CommentController) ListReplies(c *gin.Context) {
 			CreatedAt:   cmt.CreatedAt,
 			UpdatedAt:   cmt.UpdatedAt,
 		}
-	}
-	c.JSON(http.StatusOK, resp)
+	}))
 }
 
 // Update godoc
@@ -316,3 +312,16 @@ func (ctl *CommentController) Delete(c *gin.Context) {
 	}
 	c.Status(http.StatusNoContent)
 }
+
+// handleError 将业务错误映射为 HTTP 状态码；无权查看的悬赏令与不存在同样返回 404
+func handleError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, service.ErrBountyNotFound),
+		errors.Is(err, service.ErrCommentNotFound):
+		c.JSON(http.StatusNotFound, ErrorResponse{Error: err.Error()})
+	case errors.Is(err, service.ErrInvalidParentComment):
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: err.Error()})
+	default:
+		c.JSON(http.StatusInternalServerError, ErrorResponse{Error: err.Error()})
+	}
+}
@@ -0,0 +1,168 @@
+package contribution
+
+import (
+	"errors"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"net/http"
+	"onepenny-server/internal/pagination"
+	"onepenny-server/internal/repository"
+	"onepenny-server/internal/service"
+	"onepenny-server/model/dao"
+	"time"
+)
+
+// ContributionController 提供众筹追加赏金相关的 HTTP 接口
+type ContributionController struct {
+	svc service.ContributionService
+}
+
+// NewContributionController 注入 ContributionService
+func NewContributionController(svc service.ContributionService) *ContributionController {
+	return &ContributionController{svc: svc}
+}
+
+// CreateContributionRequest 追加赏金请求体
+type CreateContributionRequest struct {
+	Amount   float64 `json:"amount" binding:"required,gt=0"`
+	Currency string  `json:"currency,omitempty"` // 不填则使用悬赏令的币种
+	Message  string  `json:"message,omitempty"`
+}
+
+// ContributionResponse 出资记录返回体
+type ContributionResponse struct {
+	ID        uuid.UUID `json:"id"`
+	BountyID  uuid.UUID `json:"bounty_id"`
+	UserID    uuid.UUID `json:"user_id"`
+	Username  string    `json:"username,omitempty"`
+	Currency  string    `json:"currency"`
+	Amount    float64   `json:"amount"`
+	Message   string    `json:"message,omitempty"`
+	Status    string    `json:"status"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// ErrorResponse 通用错误返回体
+type ErrorResponse struct {
+	Error string `json:"error"`
+}
+
+// Create godoc
+// @Summary     追加赏金
+// @Description 为他人发布的悬赏令追加赏金，金额从当前用户钱包转入托管；悬赏令取消或过期时原路退回
+// @Tags        contribution
+// @Security    BearerAuth
+// @Accept      json
+// @Produce     json
+// @Param       id  path     string                    true "悬赏令 ID"
+// @Param       req body     CreateContributionRequest true "追加金额与币种"
+// @Success     201 {object} ContributionResponse
+// @Failure     400 {object} ErrorResponse "参数格式错误"
+// @Failure     401 {object} ErrorResponse "未授权"
+// @Failure     402 {object} ErrorResponse "钱包余额不足"
+// @Failure     403 {object} ErrorResponse "发布者不能为自己的悬赏令追加"
+// @Failure     404 {object} ErrorResponse "未找到悬赏令"
+// @Failure     409 {object} ErrorResponse "悬赏令已不再接受追加"
+// @Failure     500 {object} ErrorResponse "服务器内部错误"
+// @Router      /api/bounties/{id}/contributions [post]
+func (ctl *ContributionController) Create(c *gin.Context) {
+	bountyID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: "invalid bounty ID"})
+		return
+	}
+
+	var req CreateContributionRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: err.Error()})
+		return
+	}
+
+	raw, _ := c.Get("userID")
+	userID := raw.(uuid.UUID)
+
+	ct, err := ctl.svc.Contribute(&service.ContributeInput{
+		BountyID: bountyID,
+		UserID:   userID,
+		Currency: req.Currency,
+		Amount:   req.Amount,
+		Message:  req.Message,
+	})
+	if err != nil {
+		handleError(c, err)
+		return
+	}
+	c.JSON(http.StatusCreated, toResponse(ct))
+}
+
+// List godoc
+// @Summary     出资人列表
+// @Description 按时间先后分页列出悬赏令的出资记录（含已退回的）
+// @Tags        contribution
+// @Security    BearerAuth
+// @Produce     json
+// @Param       id         path  string true  "悬赏令 ID"
+// @Param       cursor     query string false "上一页返回的 next_cursor，为空表示第一页"
+// @Param       size       query int    false "每页大小" default(20)
+// @Param       with_total query bool   false "是否返回总数"
+// @Success     200   {object}  pagination.Result[ContributionResponse]
+// @Failure     400   {object}  ErrorResponse "无效的 ID"
+// @Failure     404   {object}  ErrorResponse "未找到悬赏令"
+// @Failure     500   {object}  ErrorResponse "服务器内部错误"
+// @Router      /api/bounties/{id}/contributions [get]
+func (ctl *ContributionController) List(c *gin.Context) {
+	bountyID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: "invalid bounty ID"})
+		return
+	}
+
+	page, err := pagination.FromQuery(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: err.Error()})
+		return
+	}
+
+	raw, _ := c.Get("userID")
+	userID := raw.(uuid.UUID)
+
+	list, err := ctl.svc.ListByBounty(bountyID, userID, page)
+	if err != nil {
+		handleError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, pagination.Map(list, toResponse))
+}
+
+// toResponse 将 dao.Contribution 转为返回体
+func toResponse(ct *dao.Contribution) ContributionResponse {
+	return ContributionResponse{
+		ID:        ct.ID,
+		BountyID:  ct.BountyID,
+		UserID:    ct.UserID,
+		Username:  ct.User.Username,
+		Currency:  ct.Currency,
+		Amount:    ct.Amount,
+		Message:   ct.Message,
+		Status:    string(ct.Status),
+		CreatedAt: ct.CreatedAt,
+	}
+}
+
+// handleError 将业务错误映射为 HTTP 状态码
+func handleError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, service.ErrBountyNotFound):
+		c.JSON(http.StatusNotFound, ErrorResponse{Error: err.Error()})
+	case errors.Is(err, service.ErrInvalidAmount):
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: err.Error()})
+	case errors.Is(err, service.ErrInsufficientBalance):
+		c.JSON(http.StatusPaymentRequired, ErrorResponse{Error: err.Error()})
+	case errors.Is(err, repository.ErrOwnerCannotContribute):
+		c.JSON(http.StatusForbidden, ErrorResponse{Error: err.Error()})
+	case errors.Is(err, repository.ErrContributionClosed):
+		c.JSON(http.StatusConflict, ErrorResponse{Error: err.Error()})
+	default:
+		c.JSON(http.StatusInternalServerError, ErrorResponse{Error: err.Error()})
+	}
+}
@@ -0,0 +1,393 @@
+package dispute
+
+import (
+	"errors"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"net/http"
+	"onepenny-server/internal/pagination"
+	"onepenny-server/internal/repository"
+	"onepenny-server/internal/service"
+	"onepenny-server/model/dao"
+	"time"
+)
+
+// DisputeController 提供结算争议与仲裁相关的 HTTP 接口
+type DisputeController struct {
+	svc service.DisputeService
+}
+
+// NewDisputeController 注入 DisputeService
+func NewDisputeController(svc service.DisputeService) *DisputeController {
+	return &DisputeController{svc: svc}
+}
+
+// OpenDisputeRequest 发起争议请求体
+type OpenDisputeRequest struct {
+	Reason       string   `json:"reason" binding:"required"`
+	EvidenceURLs []string `json:"evidence_urls,omitempty"`
+}
+
+// AddEvidenceRequest 补充证据请求体
+type AddEvidenceRequest struct {
+	EvidenceURLs []string `json:"evidence_urls" binding:"required,min=1"`
+}
+
+// ResolveDisputeRequest 仲裁裁决请求体
+type ResolveDisputeRequest struct {
+	Ruling       string  `json:"ruling" binding:"required,oneof=full_payout partial_payout refund"`
+	PayoutAmount float64 `json:"payout_amount,omitempty"` // 部分发放时判给接收者的金额
+	Note         string  `json:"note" binding:"required"`
+}
+
+// DisputeResponse 争议返回体
+type DisputeResponse struct {
+	ID           uuid.UUID  `json:"id"`
+	BountyID     uuid.UUID  `json:"bounty_id"`
+	InitiatorID  uuid.UUID  `json:"initiator_id"`
+	RespondentID uuid.UUID  `json:"respondent_id"`
+	Reason       string     `json:"reason"`
+	EvidenceURLs []string   `json:"evidence_urls,omitempty"`
+	Status       string     `json:"status"`
+	ArbitratorID *uuid.UUID `json:"arbitrator_id,omitempty"`
+	Ruling       string     `json:"ruling,omitempty"`
+	PayoutAmount float64    `json:"payout_amount"`
+	RulingNote   string     `json:"ruling_note,omitempty"`
+	ResolvedAt   *time.Time `json:"resolved_at,omitempty"`
+	CreatedAt    time.Time  `json:"created_at"`
+	UpdatedAt    time.Time  `json:"updated_at"`
+}
+
+// ErrorResponse 通用错误返回体
+type ErrorResponse struct {
+	Error string `json:"error"`
+}
+
+// Open godoc
+// @Summary     发起结算争议
+// @Description 悬赏令的发布者或接收者对结算发起争议，悬赏令进入 disputed 状态等待仲裁
+// @Tags        dispute
+// @Security    BearerAuth
+// @Accept      json
+// @Produce     json
+// @Param       id  path     string             true "悬赏令 ID"
+// @Param       req body     OpenDisputeRequest true "争议理由与证据"
+// @Success     201 {object} DisputeResponse
+// @Failure     400 {object} ErrorResponse "参数格式错误"
+// @Failure     401 {object} ErrorResponse "未授权"
+// @Failure     403 {object} ErrorResponse "非悬赏令当事人"
+// @Failure     404 {object} ErrorResponse "未找到悬赏令"
+// @Failure     409 {object} ErrorResponse "悬赏令当前状态不允许发起争议"
+// @Failure     500 {object} ErrorResponse "服务器内部错误"
+// @Router      /api/bounties/{id}/disputes [post]
+func (ctl *DisputeController) Open(c *gin.Context) {
+	bountyID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: "invalid bounty ID"})
+		return
+	}
+
+	var req OpenDisputeRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: err.Error()})
+		return
+	}
+
+	raw, _ := c.Get("userID")
+	userID := raw.(uuid.UUID)
+
+	d, err := ctl.svc.OpenDispute(&service.OpenDisputeInput{
+		BountyID:     bountyID,
+		InitiatorID:  userID,
+		Reason:       req.Reason,
+		EvidenceURLs: req.EvidenceURLs,
+	})
+	if err != nil {
+		handleError(c, err)
+		return
+	}
+	c.JSON(http.StatusCreated, toResponse(d))
+}
+
+// ListByBounty godoc
+// @Summary     列出悬赏令的争议
+// @Description 分页获取当前用户在指定悬赏令下参与的争议
+// @Tags        dispute
+// @Security    BearerAuth
+// @Produce     json
+// @Param       id         path  string true  "悬赏令 ID"
+// @Param       cursor     query string false "上一页返回的 next_cursor，为空表示第一页"
+// @Param       size       query int    false "每页大小" default(20)
+// @Param       with_total query bool   false "是否返回总数"
+// @Success     200   {object}  pagination.Result[DisputeResponse]
+// @Failure     400   {object}  ErrorResponse "无效的 ID"
+// @Failure     401   {object}  ErrorResponse "未授权"
+// @Failure     500   {object}  ErrorResponse "服务器内部错误"
+// @Router      /api/bounties/{id}/disputes [get]
+func (ctl *DisputeController) ListByBounty(c *gin.Context) {
+	bountyID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: "invalid bounty ID"})
+		return
+	}
+
+	raw, _ := c.Get("userID")
+	userID := raw.(uuid.UUID)
+
+	page, err := pagination.FromQuery(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: err.Error()})
+		return
+	}
+	list, err := ctl.svc.ListByBounty(bountyID, userID, page)
+	if err != nil {
+		handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, pagination.Map(list, toResponse))
+}
+
+// Get godoc
+// @Summary     获取争议详情
+// @Description 争议当事人查看争议详情
+// @Tags        dispute
+// @Security    BearerAuth
+// @Produce     json
+// @Param       id  path     string true "争议 ID"
+// @Success     200 {object} DisputeResponse
+// @Failure     400 {object} ErrorResponse "无效的 ID"
+// @Failure     403 {object} ErrorResponse "非争议当事人"
+// @Failure     404 {object} ErrorResponse "未找到争议"
+// @Router      /api/disputes/{id} [get]
+func (ctl *DisputeController) Get(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: "invalid dispute ID"})
+		return
+	}
+
+	raw, _ := c.Get("userID")
+	userID := raw.(uuid.UUID)
+
+	d, err := ctl.svc.GetDispute(id, userID)
+	if err != nil {
+		handleError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, toResponse(d))
+}
+
+// AddEvidence godoc
+// @Summary     补充争议证据
+// @Description 争议任一方在裁决前补充证据附件
+// @Tags        dispute
+// @Security    BearerAuth
+// @Accept      json
+// @Produce     json
+// @Param       id  path     string             true "争议 ID"
+// @Param       req body     AddEvidenceRequest true "证据附件 URL"
+// @Success     200 {object} DisputeResponse
+// @Failure     400 {object} ErrorResponse "参数格式错误"
+// @Failure     403 {object} ErrorResponse "非争议当事人"
+// @Failure     404 {object} ErrorResponse "未找到争议"
+// @Failure     409 {object} ErrorResponse "争议已结束"
+// @Router      /api/disputes/{id}/evidence [post]
+func (ctl *DisputeController) AddEvidence(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: "invalid dispute ID"})
+		return
+	}
+
+	var req AddEvidenceRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: err.Error()})
+		return
+	}
+
+	raw, _ := c.Get("userID")
+	userID := raw.(uuid.UUID)
+
+	d, err := ctl.svc.AddEvidence(id, userID, req.EvidenceURLs)
+	if err != nil {
+		handleError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, toResponse(d))
+}
+
+// Withdraw godoc
+// @Summary     撤回争议
+// @Description 发起方撤回争议，悬赏令恢复到争议前的状态
+// @Tags        dispute
+// @Security    BearerAuth
+// @Produce     json
+// @Param       id  path     string true "争议 ID"
+// @Success     200 {object} DisputeResponse
+// @Failure     400 {object} ErrorResponse "无效的 ID"
+// @Failure     403 {object} ErrorResponse "非争议发起方"
+// @Failure     404 {object} ErrorResponse "未找到争议"
+// @Failure     409 {object} ErrorResponse "争议已结束"
+// @Router      /api/disputes/{id}/withdraw [post]
+func (ctl *DisputeController) Withdraw(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: "invalid dispute ID"})
+		return
+	}
+
+	raw, _ := c.Get("userID")
+	userID := raw.(uuid.UUID)
+
+	d, err := ctl.svc.WithdrawDispute(id, userID)
+	if err != nil {
+		handleError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, toResponse(d))
+}
+
+// ListForArbitration godoc
+// @Summary     仲裁队列
+// @Description 仲裁员按状态分页查看争议，默认列出待裁决的争议
+// @Tags        arbitration
+// @Security    BearerAuth
+// @Produce     json
+// @Param       status     query string false "争议状态" Enums(open,resolved,withdrawn) default(open)
+// @Param       cursor     query string false "上一页返回的 next_cursor，为空表示第一页"
+// @Param       size       query int    false "每页大小" default(20)
+// @Param       with_total query bool   false "是否返回总数"
+// @Success     200    {object}  pagination.Result[DisputeResponse]
+// @Failure     401    {object}  ErrorResponse "未授权"
+// @Failure     403    {object}  ErrorResponse "非仲裁员"
+// @Failure     500    {object}  ErrorResponse "服务器内部错误"
+// @Router      /api/arbitration/disputes [get]
+func (ctl *DisputeController) ListForArbitration(c *gin.Context) {
+	page, err := pagination.FromQuery(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: err.Error()})
+		return
+	}
+	list, err := ctl.svc.ListByStatus(c.Query("status"), page)
+	if err != nil {
+		handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, pagination.Map(list, toResponse))
+}
+
+// GetForArbitration godoc
+// @Summary     仲裁员查看争议
+// @Description 仲裁员查看任意争议的详情
+// @Tags        arbitration
+// @Security    BearerAuth
+// @Produce     json
+// @Param       id  path     string true "争议 ID"
+// @Success     200 {object} DisputeResponse
+// @Failure     400 {object} ErrorResponse "无效的 ID"
+// @Failure     403 {object} ErrorResponse "非仲裁员"
+// @Failure     404 {object} ErrorResponse "未找到争议"
+// @Router      /api/arbitration/disputes/{id} [get]
+func (ctl *DisputeController) GetForArbitration(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: "invalid dispute ID"})
+		return
+	}
+
+	d, err := ctl.svc.GetDisputeForArbitration(id)
+	if err != nil {
+		handleError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, toResponse(d))
+}
+
+// Resolve godoc
+// @Summary     裁决争议
+// @Description 仲裁员裁决争议：全额发放、部分发放（其余退回发布者）或全额退款
+// @Tags        arbitration
+// @Security    BearerAuth
+// @Accept      json
+// @Produce     json
+// @Param       id  path     string                true "争议 ID"
+// @Param       req body     ResolveDisputeRequest true "裁决结果"
+// @Success     200 {object} DisputeResponse
+// @Failure     400 {object} ErrorResponse "参数格式错误或金额无效"
+// @Failure     403 {object} ErrorResponse "非仲裁员"
+// @Failure     404 {object} ErrorResponse "未找到争议"
+// @Failure     409 {object} ErrorResponse "争议已结束"
+// @Failure     500 {object} ErrorResponse "服务器内部错误"
+// @Router      /api/arbitration/disputes/{id}/resolve [post]
+func (ctl *DisputeController) Resolve(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: "invalid dispute ID"})
+		return
+	}
+
+	var req ResolveDisputeRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: err.Error()})
+		return
+	}
+
+	raw, _ := c.Get("userID")
+	arbitratorID := raw.(uuid.UUID)
+
+	d, err := ctl.svc.ResolveDispute(&service.ResolveDisputeInput{
+		DisputeID:    id,
+		ArbitratorID: arbitratorID,
+		Ruling:       req.Ruling,
+		PayoutAmount: req.PayoutAmount,
+		Note:         req.Note,
+	})
+	if err != nil {
+		handleError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, toResponse(d))
+}
+
+// toResponse 将 dao.Dispute 转为返回体
+func toResponse(d *dao.Dispute) DisputeResponse {
+	return DisputeResponse{
+		ID:           d.ID,
+		BountyID:     d.BountyID,
+		InitiatorID:  d.InitiatorID,
+		RespondentID: d.RespondentID,
+		Reason:       d.Reason,
+		EvidenceURLs: d.EvidenceURLs,
+		Status:       string(d.Status),
+		ArbitratorID: d.ArbitratorID,
+		Ruling:       string(d.Ruling),
+		PayoutAmount: d.PayoutAmount,
+		RulingNote:   d.RulingNote,
+		ResolvedAt:   d.ResolvedAt,
+		CreatedAt:    d.CreatedAt,
+		UpdatedAt:    d.UpdatedAt,
+	}
+}
+
+// handleError 将业务错误映射为 HTTP 状态码
+func handleError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, service.ErrDisputeNotFound),
+		errors.Is(err, service.ErrBountyNotFound):
+		c.JSON(http.StatusNotFound, ErrorResponse{Error: err.Error()})
+	case errors.Is(err, repository.ErrNotBountyParty),
+		errors.Is(err, service.ErrNotDisputeParty),
+		errors.Is(err, repository.ErrNotDisputeInitiator):
+		c.JSON(http.StatusForbidden, ErrorResponse{Error: err.Error()})
+	case errors.Is(err, repository.ErrInvalidRuling),
+		errors.Is(err, repository.ErrInvalidPayout):
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: err.Error()})
+	case errors.Is(err, repository.ErrDisputeNotOpen),
+		errors.Is(err, service.ErrIllegalBountyTransition):
+		c.JSON(http.StatusConflict, ErrorResponse{Error: err.Error()})
+	default:
+		c.JSON(http.StatusInternalServerError, ErrorResponse{Error: err.Error()})
+	}
+}
@@ -4,8 +4,9 @@ import (
 	"github.com/gin-gonic/gin"
 	"github.com/google/uuid"
 	"net/http"
+	"onepenny-server/internal/pagination"
 	"onepenny-server/internal/service"
-	"strconv"
+	"onepenny-server/model/dao"
 	"time"
 )
 
@@ -139,9 +140,10 @@ func (ctl *InvitationController) Send(c *gin.Context) {
 // @Tags        invitation
 // @Security    BearerAuth
 // @Produce     json
-// @Param       page  query     int   false "页码"    default(1)
-// @Param       size  query     int   false "每页大小" default(20)
-// @Success     200   {array}   InvitationResponse  "邀请列表"
+// @Param       cursor     query string false "上一页返回的 next_cursor，为空表示第一页"
+// @Param       size       query int    false "每页大小" default(20)
+// @Param       with_total query bool   false "是否返回总数"
+// @Success     200   {object}  pagination.Result[InvitationResponse]  "邀请列表"
 // @Failure     401   {object}  ErrorResponse        "未授权"
 // @Failure     500   {object}  ErrorResponse        "服务器内部错误"
 // @Router      /api/invitations [get]
@@ -157,27 +159,20 @@ func (ctl *InvitationController) ListByInvitee(c *gin.Context) {
 		return
 	}
 
-	page, size := 1, 20
-	if p := c.Query("page"); p != "" {
-		if v, err := strconv.Atoi(p); err == nil && v > 0 {
-			page = v
-		}
-	}
-	if s := c.Query("size"); s != "" {
-		if v, err := strconv.Atoi(s); err == nil && v > 0 {
-			size = v
-		}
+	page, err := pagination.FromQuery(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: err.Error()})
+		return
 	}
 
-	list, err := ctl.svc.ListByInvitee(inviteeID, page, size)
+	list, err := ctl.svc.ListByInvitee(inviteeID, page)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, ErrorResponse{Error: err.Error()})
 		return
 	}
 
-	resp := make([]InvitationResponse, len(list))
-	for i, inv := range list {
-		resp[i] = InvitationResponse{
+	c.JSON(http.StatusOK, pagination.Map(list, func(inv *dao.Invitation) InvitationResponse {
+		return InvitationResponse{
 			ID:        inv.ID,
 			InviterID: inv.InviterID,
 			InviteeID: inv.InviteeID,
@@ -188,8 +183,7 @@ func (ctl *InvitationController) ListByInvitee(c *gin.Context) {
 			CreatedAt: inv.CreatedAt,
 			UpdatedAt: inv.UpdatedAt,
 		}
-	}
-	c.JSON(http.StatusOK, resp)
+	}))
 }
 
 // Respond godoc
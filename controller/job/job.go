@@ -0,0 +1,126 @@
+package job
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"net/http"
+	"onepenny-server/internal/pagination"
+	"onepenny-server/internal/service"
+	"onepenny-server/model/dao"
+	"time"
+)
+
+// JobController 提供后台定时任务的管理接口，仅管理员可访问
+type JobController struct {
+	svc service.JobService
+}
+
+// NewJobController 注入 JobService
+func NewJobController(svc service.JobService) *JobController {
+	return &JobController{svc: svc}
+}
+
+// JobRunResponse 单次执行记录
+type JobRunResponse struct {
+	ID         uuid.UUID `json:"id"`
+	Job        string    `json:"job"`
+	Instance   string    `json:"instance"`
+	Status     string    `json:"status"`
+	Affected   int       `json:"affected"`
+	Error      string    `json:"error,omitempty"`
+	StartedAt  time.Time `json:"started_at"`
+	FinishedAt time.Time `json:"finished_at"`
+	DurationMS int64     `json:"duration_ms"`
+}
+
+// JobStatusResponse 单个任务的状态
+type JobStatusResponse struct {
+	Name     string          `json:"name"`
+	Interval string          `json:"interval"`
+	LastRun  *JobRunResponse `json:"last_run,omitempty"`
+}
+
+// SchedulerStatusResponse 调度器状态
+type SchedulerStatusResponse struct {
+	Instance string              `json:"instance"`
+	IsLeader bool                `json:"is_leader"`
+	Leader   string              `json:"leader,omitempty"`
+	Jobs     []JobStatusResponse `json:"jobs"`
+}
+
+// ErrorResponse 通用错误返回体
+type ErrorResponse struct {
+	Error string `json:"error"`
+}
+
+// Status godoc
+// @Summary     后台任务状态
+// @Description 查看当前实例是否为 leader、已注册的任务及其最近一次执行（仅管理员）
+// @Tags        admin
+// @Security    BearerAuth
+// @Produce     json
+// @Success     200 {object} SchedulerStatusResponse
+// @Failure     401 {object} ErrorResponse "未授权"
+// @Failure     403 {object} ErrorResponse "权限不足"
+// @Router      /api/admin/jobs [get]
+func (ctl *JobController) Status(c *gin.Context) {
+	st := ctl.svc.Status(c.Request.Context())
+	resp := SchedulerStatusResponse{
+		Instance: st.Instance,
+		IsLeader: st.IsLeader,
+		Leader:   st.Leader,
+		Jobs:     make([]JobStatusResponse, len(st.Jobs)),
+	}
+	for i, j := range st.Jobs {
+		resp.Jobs[i] = JobStatusResponse{Name: j.Name, Interval: j.Interval.String()}
+		if j.LastRun != nil {
+			run := toRunResponse(j.LastRun)
+			resp.Jobs[i].LastRun = &run
+		}
+	}
+	c.JSON(http.StatusOK, resp)
+}
+
+// ListRuns godoc
+// @Summary     后台任务执行记录
+// @Description 按开始时间倒序分页查看后台任务的执行记录，可按任务名过滤（仅管理员）
+// @Tags        admin
+// @Security    BearerAuth
+// @Produce     json
+// @Param       job        query string false "任务名"
+// @Param       cursor     query string false "上一页返回的 next_cursor，为空表示第一页"
+// @Param       size       query int    false "每页大小" default(20)
+// @Param       with_total query bool   false "是否返回总数"
+// @Success     200   {object}  pagination.Result[JobRunResponse]
+// @Failure     401   {object}  ErrorResponse "未授权"
+// @Failure     403   {object}  ErrorResponse "权限不足"
+// @Failure     500   {object}  ErrorResponse "服务器内部错误"
+// @Router      /api/admin/jobs/runs [get]
+func (ctl *JobController) ListRuns(c *gin.Context) {
+	page, err := pagination.FromQuery(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: err.Error()})
+		return
+	}
+
+	list, err := ctl.svc.ListRuns(c.Query("job"), page)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, ErrorResponse{Error: err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, pagination.Map(list, toRunResponse))
+}
+
+func toRunResponse(run *dao.JobRun) JobRunResponse {
+	return JobRunResponse{
+		ID:         run.ID,
+		Job:        run.Job,
+		Instance:   run.Instance,
+		Status:     string(run.Status),
+		Affected:   run.Affected,
+		Error:      run.Error,
+		StartedAt:  run.StartedAt,
+		FinishedAt: run.FinishedAt,
+		DurationMS: run.FinishedAt.Sub(run.StartedAt).Milliseconds(),
+	}
+}
@@ -1,6 +1,7 @@
 package like
 
 import (
+	"errors"
 	"github.com/gin-gonic/gin"
 	"github.com/google/uuid"
 	"net/http"
@@ -44,6 +45,7 @@ type ErrorResponse struct {
 // @Success     201 {string}  string        "Created"
 // @Failure     400 {object}  ErrorResponse "参数格式错误或已点赞"
 // @Failure     401 {object}  ErrorResponse "未授权"
+// @Failure     404 {object}  ErrorResponse "目标悬赏令或评论不存在，或无权查看"
 // @Failure     500 {object}  ErrorResponse "服务器内部错误"
 // @Router      /api/likes [post]
 func (ctl *LikeController) Like(c *gin.Context) {
@@ -73,6 +75,8 @@ func (ctl *LikeController) Like(c *gin.Context) {
 	if err := ctl.svc.Like(userID, targetID, req.TargetType); err != nil {
 		if err == service.ErrAlreadyLiked {
 			c.JSON(http.StatusBadRequest, ErrorResponse{Error: err.Error()})
+		} else if isTargetHidden(err) {
+			c.JSON(http.StatusNotFound, ErrorResponse{Error: err.Error()})
 		} else {
 			c.JSON(http.StatusInternalServerError, ErrorResponse{Error: err.Error()})
 		}
@@ -93,6 +97,7 @@ func (ctl *LikeController) Like(c *gin.Context) {
 // @Success     204 {string}  string        "No Content"
 // @Failure     400 {object}  ErrorResponse "参数格式错误或未点赞"
 // @Failure     401 {object}  ErrorResponse "未授权"
+// @Failure     404 {object}  ErrorResponse "目标悬赏令或评论不存在，或无权查看"
 // @Failure     500 {object}  ErrorResponse "服务器内部错误"
 // @Router      /api/likes [delete]
 func (ctl *LikeController) Unlike(c *gin.Context) {
@@ -122,6 +127,8 @@ func (ctl *LikeController) Unlike(c *gin.Context) {
 	if err := ctl.svc.Unlike(userID, targetID, req.TargetType); err != nil {
 		if err == service.ErrNotLikedYet {
 			c.JSON(http.StatusBadRequest, ErrorResponse{Error: err.Error()})
+		} else if isTargetHidden(err) {
+			c.JSON(http.StatusNotFound, ErrorResponse{Error: err.Error()})
 		} else {
 			c.JSON(http.StatusInternalServerError, ErrorResponse{Error: err.Error()})
 		}
@@ -141,9 +148,13 @@ func (ctl *LikeController) Unlike(c *gin.Context) {
 // @Param       target_type query     string  true  "目标实体类型，如 bounty, comment, user"
 // @Success     200         {object}  CountResponse     "返回点赞总数"
 // @Failure     400         {object}  ErrorResponse     "参数错误"
+// @Failure     404         {object}  ErrorResponse     "目标悬赏令或评论不存在，或无权查看"
 // @Failure     500         {object}  ErrorResponse     "服务器内部错误"
 // @Router      /api/likes/count [get]
 func (ctl *LikeController) Count(c *gin.Context) {
+	raw, _ := c.Get("userID")
+	userID := raw.(uuid.UUID)
+
 	targetIDStr := c.Query("target_id")
 	if targetIDStr == "" {
 		c.JSON(http.StatusBadRequest, ErrorResponse{Error: "target_id is required"})
@@ -161,11 +172,20 @@ func (ctl *LikeController) Count(c *gin.Context) {
 		return
 	}
 
-	count, err := ctl.svc.Count(targetID, targetType)
+	count, err := ctl.svc.Count(userID, targetID, targetType)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, ErrorResponse{Error: err.Error()})
+		if isTargetHidden(err) {
+			c.JSON(http.StatusNotFound, ErrorResponse{Error: err.Error()})
+		} else {
+			c.JSON(http.StatusInternalServerError, ErrorResponse{Error: err.Error()})
+		}
 		return
 	}
 
 	c.JSON(http.StatusOK, CountResponse{Count: int(count)})
 }
+
+// isTargetHidden 目标悬赏令或评论不存在，或当前用户无权查看
+func isTargetHidden(err error) bool {
+	return errors.Is(err, service.ErrBountyNotFound) || errors.Is(err, service.ErrCommentNotFound)
+}
@@ -4,8 +4,9 @@ import (
 	"github.com/gin-gonic/gin"
 	"github.com/google/uuid"
 	"net/http"
+	"onepenny-server/internal/pagination"
 	"onepenny-server/internal/service"
-	"strconv"
+	"onepenny-server/model/dao"
 	"time"
 )
 
@@ -51,9 +52,10 @@ type ErrorResponse struct {
 // @Tags        notification
 // @Security    BearerAuth
 // @Produce     json
-// @Param       page  query     int false "页码"   default(1)
-// @Param       size  query     int false "每页大小" default(20)
-// @Success     200   {array}   NotificationResponse "通知列表"
+// @Param       cursor     query string false "上一页返回的 next_cursor，为空表示第一页"
+// @Param       size       query int    false "每页大小" default(20)
+// @Param       with_total query bool   false "是否返回总数"
+// @Success     200   {object}  pagination.Result[NotificationResponse] "通知列表"
 // @Failure     401   {object}  ErrorResponse          "未授权"
 // @Failure     500   {object}  ErrorResponse          "服务器内部错误"
 // @Router      /api/notifications [get]
@@ -69,27 +71,20 @@ func (ctl *NotificationController) List(c *gin.Context) {
 		return
 	}
 
-	page, size := 1, 20
-	if p := c.Query("page"); p != "" {
-		if v, err := strconv.Atoi(p); err == nil && v > 0 {
-			page = v
-		}
-	}
-	if s := c.Query("size"); s != "" {
-		if v, err := strconv.Atoi(s); err == nil && v > 0 {
-			size = v
-		}
+	page, err := pagination.FromQuery(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: err.Error()})
+		return
 	}
 
-	list, err := ctl.svc.ListNotifications(userID, page, size)
+	list, err := ctl.svc.ListNotifications(userID, page)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, ErrorResponse{Error: err.Error()})
 		return
 	}
 
-	resp := make([]NotificationResponse, len(list))
-	for i, n := range list {
-		resp[i] = NotificationResponse{
+	c.JSON(http.StatusOK, pagination.Map(list, func(n *dao.Notification) NotificationResponse {
+		return NotificationResponse{
 			ID:          n.ID,
 			UserID:      n.UserID,
 			ActorID:     n.ActorID,
@@ -103,9 +98,7 @@ func (ctl *NotificationController) List(c *gin.Context) {
 			CreatedAt:   n.CreatedAt,
 			UpdatedAt:   n.UpdatedAt,
 		}
-	}
-
-	c.JSON(http.StatusOK, resp)
+	}))
 }
 
 // CountUnread godoc
@@ -6,12 +6,23 @@ import (
 	applicationCtrl "onepenny-server/controller/application"
 	attachmentCtrl "onepenny-server/controller/attachment"
 	bountyCtrl "onepenny-server/controller/bounty"
+	bountySeriesCtrl "onepenny-server/controller/bountyseries"
+	bountyTemplateCtrl "onepenny-server/controller/bountytemplate"
+	categoryCtrl "onepenny-server/controller/category"
 	commentCtrl "onepenny-server/controller/comment"
+	contributionCtrl "onepenny-server/controller/contribution"
+	disputeCtrl "onepenny-server/controller/dispute"
 	invitationCtrl "onepenny-server/controller/invitation"
+	jobCtrl "onepenny-server/controller/job"
 	likeCtrl "onepenny-server/controller/like"
 	notificationCtrl "onepenny-server/controller/notification"
+	savedSearchCtrl "onepenny-server/controller/savedsearch"
+	submissionCtrl "onepenny-server/controller/submission"
+	tagCtrl "onepenny-server/controller/tag"
 	teamCtrl "onepenny-server/controller/team"
 	userCtrl "onepenny-server/controller/user"
+	walletCtrl "onepenny-server/controller/wallet"
+	"onepenny-server/model/dao"
 )
 
 // SetupRouter 接收所有 Controller，先挂载公开路由，再挂载受保护路由
@@ -27,6 +38,16 @@ func SetupRouter(
 	teamController *teamCtrl.TeamController,
 	attachmentController *attachmentCtrl.AttachmentController,
 	statsController *userCtrl.UserStatsController,
+	walletController *walletCtrl.WalletController,
+	disputeController *disputeCtrl.DisputeController,
+	submissionController *submissionCtrl.SubmissionController,
+	jobController *jobCtrl.JobController,
+	contributionController *contributionCtrl.ContributionController,
+	templateController *bountyTemplateCtrl.BountyTemplateController,
+	seriesController *bountySeriesCtrl.BountySeriesController,
+	tagController *tagCtrl.TagController,
+	categoryController *categoryCtrl.CategoryController,
+	savedSearchController *savedSearchCtrl.SavedSearchController,
 ) *gin.Engine {
 	r := gin.Default()
 
@@ -63,11 +84,87 @@ func SetupRouter(
 		{
 			bs.POST("", bountyController.Create)
 			bs.GET("", bountyController.List)
+			bs.GET("/drafts", bountyController.ListDrafts)
+			bs.GET("/search", bountyController.Search)
+			bs.GET("/trending", bountyController.Trending)
+			bs.GET("/watching", bountyController.ListWatching)
 			bs.GET("/:id", bountyController.Get)
 			bs.PUT("/:id", bountyController.Update)
 			bs.DELETE("/:id", bountyController.Delete)
+			bs.POST("/:id/publish", bountyController.Publish)
+			bs.GET("/:id/timeline", bountyController.Timeline)
+			bs.GET("/:id/watch", bountyController.WatchStatus)
+			bs.POST("/:id/watch", bountyController.Watch)
+			bs.DELETE("/:id/watch", bountyController.Unwatch)
+			bs.GET("/:id/views", bountyController.Views)
+			bs.POST("/:id/cancel", bountyController.Cancel)
+			bs.POST("/:id/cancel/respond", bountyController.RespondCancellation)
+			bs.POST("/:id/contributions", contributionController.Create)
+			bs.GET("/:id/contributions", contributionController.List)
+			bs.POST("/:id/disputes", disputeController.Open)
+			bs.GET("/:id/disputes", disputeController.ListByBounty)
+			bs.POST("/:id/submissions", submissionController.Create)
+			bs.GET("/:id/submissions", submissionController.List)
+			bs.POST("/:id/submissions/:submission_id/accept", submissionController.Accept)
+			bs.POST("/:id/submissions/:submission_id/request-changes", submissionController.RequestChanges)
 			bs.POST("/:id/request-settlement", bountyController.RequestSettlement)
 			bs.POST("/:id/confirm-settlement", bountyController.ConfirmSettlement)
+			bs.GET("/:id/milestones", bountyController.ListMilestones)
+			bs.POST("/:id/milestones/:milestone_id/request-settlement", bountyController.RequestMilestoneSettlement)
+			bs.POST("/:id/milestones/:milestone_id/confirm-settlement", bountyController.ConfirmMilestoneSettlement)
+		}
+
+		// 个性化推荐
+		protected.GET("/feed/recommended", bountyController.Recommended)
+
+		// 悬赏令模板
+		tpls := protected.Group("/bounty-templates")
+		{
+			tpls.POST("", templateController.Create)
+			tpls.GET("", templateController.List)
+			tpls.GET("/:id", templateController.Get)
+			tpls.PUT("/:id", templateController.Update)
+			tpls.DELETE("/:id", templateController.Delete)
+			tpls.POST("/:id/instantiate", templateController.Instantiate)
+		}
+
+		// 周期悬赏
+		series := protected.Group("/bounty-series")
+		{
+			series.POST("", seriesController.Create)
+			series.GET("", seriesController.List)
+			series.GET("/:id", seriesController.Get)
+			series.PUT("/:id", seriesController.Update)
+			series.POST("/:id/pause", seriesController.Pause)
+			series.POST("/:id/resume", seriesController.Resume)
+			series.POST("/:id/end", seriesController.End)
+			series.GET("/:id/occurrences", seriesController.ListOccurrences)
+		}
+
+		// 标签
+		tags := protected.Group("/tags")
+		{
+			tags.GET("", tagController.Autocomplete)
+			tags.GET("/following", tagController.ListFollowing)
+			tags.POST("/:tag/follow", tagController.Follow)
+			tags.DELETE("/:tag/follow", tagController.Unfollow)
+		}
+
+		// 分类
+		protected.GET("/categories", categoryController.Tree)
+		protected.GET("/categories/:slug", categoryController.Get)
+
+		// 保存的搜索
+		saved := protected.Group("/saved-searches")
+		{
+			saved.POST("", savedSearchController.Create)
+			saved.GET("", savedSearchController.List)
+			saved.GET("/:id", savedSearchController.Get)
+			saved.PUT("/:id", savedSearchController.Update)
+			saved.DELETE("/:id", savedSearchController.Delete)
+			saved.POST("/:id/pause", savedSearchController.Pause)
+			saved.POST("/:id/resume", savedSearchController.Resume)
+			saved.GET("/:id/matches", savedSearchController.ListMatches)
 		}
 
 		// 应用
@@ -77,6 +174,8 @@ func SetupRouter(
 			apps.GET("/:id", applicationController.Get)
 			apps.GET("", applicationController.ListByUser)
 			apps.DELETE("/:id", applicationController.Delete)
+			apps.PUT("/:id/approve", applicationController.Approve)
+			apps.PUT("/:id/reject", applicationController.Reject)
 		}
 
 		// 邀请
@@ -128,6 +227,44 @@ func SetupRouter(
 			teams.GET("/:id/members", teamController.ListMembers)
 		}
 
+		// 结算争议（当事人）
+		disputes := protected.Group("/disputes")
+		{
+			disputes.GET("/:id", disputeController.Get)
+			disputes.POST("/:id/evidence", disputeController.AddEvidence)
+			disputes.POST("/:id/withdraw", disputeController.Withdraw)
+		}
+
+		// 仲裁（仅仲裁员）
+		arb := protected.Group("/arbitration")
+		arb.Use(authController.RequireRole(dao.RoleArbitrator))
+		{
+			arb.GET("/disputes", disputeController.ListForArbitration)
+			arb.GET("/disputes/:id", disputeController.GetForArbitration)
+			arb.POST("/disputes/:id/resolve", disputeController.Resolve)
+		}
+
+		// 后台任务观测（仅管理员）
+		admin := protected.Group("/admin")
+		admin.Use(authController.RequireRole(dao.RoleAdmin))
+		{
+			admin.GET("/jobs", jobController.Status)
+			admin.GET("/jobs/runs", jobController.ListRuns)
+			admin.GET("/bounties/duplicates", bountyController.DuplicateReport)
+			admin.POST("/wallets/deposit", walletController.Deposit)
+			admin.POST("/tags/:tag/aliases", tagController.AddAlias)
+			admin.POST("/categories", categoryController.Create)
+			admin.PUT("/categories/:id", categoryController.Update)
+			admin.DELETE("/categories/:id", categoryController.Delete)
+		}
+
+		// 钱包与流水
+		wallet := protected.Group("/wallet")
+		{
+			wallet.GET("", walletController.List)
+			wallet.GET("/entries", walletController.ListEntries)
+		}
+
 		// 用户数据统计
 		// 按状态查看自己发布的悬赏
 		protected.GET("/user/bounties/status", statsController.ListMyBountiesByStatus)
@@ -136,6 +273,8 @@ func SetupRouter(
 
 		// 统计总赏金
 		protected.GET("/user/stats/total-earned", statsController.GetTotalEarned)
+		// 按币种统计追加赏金
+		protected.GET("/user/stats/total-contributed", statsController.GetTotalContributed)
 		// 列出已点赞的悬赏
 		protected.GET("/user/stats/liked-bounties", statsController.ListLikedBounties)
 		// 列出已浏览的悬赏
@@ -0,0 +1,431 @@
+package savedsearch
+
+import (
+	"errors"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"net/http"
+	"onepenny-server/internal/pagination"
+	"onepenny-server/internal/service"
+	"onepenny-server/model/dao"
+	"time"
+)
+
+// SavedSearchController 提供保存的搜索相关的 HTTP 接口
+type SavedSearchController struct {
+	svc service.SavedSearchService
+}
+
+// NewSavedSearchController 注入 SavedSearchService
+func NewSavedSearchController(svc service.SavedSearchService) *SavedSearchController {
+	return &SavedSearchController{svc: svc}
+}
+
+// FilterBody 保存的筛选条件，含义与悬赏令列表的同名查询参数一致，省略表示不限
+type FilterBody struct {
+	Location   string   `json:"location,omitempty"` // "remote" 或线下地址关键词
+	NearLat    *float64 `json:"near_lat,omitempty"`
+	NearLng    *float64 `json:"near_lng,omitempty"`
+	RadiusKm   float64  `json:"radius_km,omitempty"` // 配合 near_lat/near_lng 使用，默认 10
+	Categories []string `json:"categories,omitempty"`
+	Tags       []string `json:"tags,omitempty"`
+	TagsMatch  string   `json:"tags_match,omitempty"` // any（默认）或 all
+	Currency   string   `json:"currency,omitempty"`
+	Priorities []string `json:"priorities,omitempty"`
+	MinReward  *float64 `json:"min_reward,omitempty"`
+	MaxReward  *float64 `json:"max_reward,omitempty"`
+}
+
+// CreateSavedSearchRequest 保存搜索请求体；检索词与筛选条件至少提供一项
+type CreateSavedSearchRequest struct {
+	Name     string     `json:"name" binding:"required"`
+	Query    string     `json:"q,omitempty"`
+	Filter   FilterBody `json:"filter"`
+	Delivery string     `json:"delivery,omitempty"` // instant（默认）或 daily
+}
+
+// UpdateSavedSearchRequest 修改保存的搜索请求体，省略的字段不修改
+type UpdateSavedSearchRequest struct {
+	Name     *string     `json:"name,omitempty"`
+	Query    *string     `json:"q,omitempty"`
+	Filter   *FilterBody `json:"filter,omitempty"`
+	Delivery *string     `json:"delivery,omitempty"`
+}
+
+// SavedSearchResponse 保存的搜索返回体
+type SavedSearchResponse struct {
+	ID             uuid.UUID  `json:"id"`
+	Name           string     `json:"name"`
+	Query          string     `json:"q,omitempty"`
+	Filter         FilterBody `json:"filter"`
+	Delivery       string     `json:"delivery"`
+	Paused         bool       `json:"paused"`
+	LastNotifiedAt *time.Time `json:"last_notified_at,omitempty"`
+	CreatedAt      time.Time  `json:"created_at"`
+	UpdatedAt      time.Time  `json:"updated_at"`
+}
+
+// MatchResponse 一条命中记录
+type MatchResponse struct {
+	BountyID   uuid.UUID  `json:"bounty_id"`
+	Title      string     `json:"title"`
+	Reward     float64    `json:"reward"`
+	Currency   string     `json:"currency"`
+	Category   string     `json:"category,omitempty"`
+	Tags       []string   `json:"tags,omitempty"`
+	Status     string     `json:"status"`
+	Deadline   *time.Time `json:"deadline,omitempty"`
+	MatchedAt  time.Time  `json:"matched_at"`
+	NotifiedAt *time.Time `json:"notified_at,omitempty"` // 为空表示等待按天汇总通知
+}
+
+// ErrorResponse 通用错误返回体
+type ErrorResponse struct {
+	Error string `json:"error"`
+}
+
+// Create godoc
+// @Summary     保存搜索
+// @Description 保存检索词与筛选条件，之后发布的悬赏令命中时立即通知，或按天汇总通知
+// @Tags        saved-search
+// @Security    BearerAuth
+// @Accept      json
+// @Produce     json
+// @Param       req body     CreateSavedSearchRequest true "名称、检索词、筛选条件与通知方式"
+// @Success     201 {object} SavedSearchResponse
+// @Failure     400 {object} ErrorResponse "参数格式错误、检索词与筛选条件都为空或分类不存在"
+// @Failure     401 {object} ErrorResponse "未授权"
+// @Failure     409 {object} ErrorResponse "保存的搜索数已达上限"
+// @Failure     500 {object} ErrorResponse "服务器内部错误"
+// @Router      /api/saved-searches [post]
+func (ctl *SavedSearchController) Create(c *gin.Context) {
+	var req CreateSavedSearchRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: err.Error()})
+		return
+	}
+	filter, err := toFilter(&req.Filter)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: err.Error()})
+		return
+	}
+
+	raw, _ := c.Get("userID")
+	userID := raw.(uuid.UUID)
+
+	ss, err := ctl.svc.CreateSavedSearch(&service.CreateSavedSearchInput{
+		UserID:   userID,
+		Name:     req.Name,
+		Query:    req.Query,
+		Filter:   *filter,
+		Delivery: dao.SavedSearchDelivery(req.Delivery),
+	})
+	if err != nil {
+		handleError(c, err)
+		return
+	}
+	c.JSON(http.StatusCreated, toResponse(ss))
+}
+
+// List godoc
+// @Summary     列出我保存的搜索
+// @Tags        saved-search
+// @Security    BearerAuth
+// @Produce     json
+// @Param       cursor     query string false "上一页返回的 next_cursor，为空表示第一页"
+// @Param       size       query int    false "每页大小" default(20)
+// @Param       with_total query bool   false "是否返回总数"
+// @Success     200  {object} pagination.Result[SavedSearchResponse]
+// @Failure     401  {object} ErrorResponse "未授权"
+// @Failure     500  {object} ErrorResponse "服务器内部错误"
+// @Router      /api/saved-searches [get]
+func (ctl *SavedSearchController) List(c *gin.Context) {
+	raw, _ := c.Get("userID")
+	userID := raw.(uuid.UUID)
+
+	page, err := pagination.FromQuery(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: err.Error()})
+		return
+	}
+	list, err := ctl.svc.ListSavedSearches(userID, page)
+	if err != nil {
+		handleError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, pagination.Map(list, toResponse))
+}
+
+// Get godoc
+// @Summary     获取保存的搜索
+// @Tags        saved-search
+// @Security    BearerAuth
+// @Produce     json
+// @Param       id  path     string true "保存的搜索 ID"
+// @Success     200 {object} SavedSearchResponse
+// @Failure     400 {object} ErrorResponse "无效的 ID"
+// @Failure     403 {object} ErrorResponse "不是本人保存的搜索"
+// @Failure     404 {object} ErrorResponse "未找到"
+// @Failure     500 {object} ErrorResponse "服务器内部错误"
+// @Router      /api/saved-searches/{id} [get]
+func (ctl *SavedSearchController) Get(c *gin.Context) {
+	id, userID, ok := parseIDs(c)
+	if !ok {
+		return
+	}
+	ss, err := ctl.svc.GetSavedSearch(id, userID)
+	if err != nil {
+		handleError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, toResponse(ss))
+}
+
+// Update godoc
+// @Summary     修改保存的搜索
+// @Description 重命名，或修改检索词、筛选条件与通知方式；只影响之后发布的悬赏令
+// @Tags        saved-search
+// @Security    BearerAuth
+// @Accept      json
+// @Produce     json
+// @Param       id  path     string                   true "保存的搜索 ID"
+// @Param       req body     UpdateSavedSearchRequest true "需要修改的字段"
+// @Success     200 {object} SavedSearchResponse
+// @Failure     400 {object} ErrorResponse "参数格式错误"
+// @Failure     403 {object} ErrorResponse "不是本人保存的搜索"
+// @Failure     404 {object} ErrorResponse "未找到"
+// @Failure     500 {object} ErrorResponse "服务器内部错误"
+// @Router      /api/saved-searches/{id} [put]
+func (ctl *SavedSearchController) Update(c *gin.Context) {
+	id, userID, ok := parseIDs(c)
+	if !ok {
+		return
+	}
+
+	var req UpdateSavedSearchRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: err.Error()})
+		return
+	}
+	input := &service.UpdateSavedSearchInput{Name: req.Name, Query: req.Query}
+	if req.Filter != nil {
+		filter, err := toFilter(req.Filter)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, ErrorResponse{Error: err.Error()})
+			return
+		}
+		input.Filter = filter
+	}
+	if req.Delivery != nil {
+		d := dao.SavedSearchDelivery(*req.Delivery)
+		input.Delivery = &d
+	}
+
+	ss, err := ctl.svc.UpdateSavedSearch(id, userID, input)
+	if err != nil {
+		handleError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, toResponse(ss))
+}
+
+// Delete godoc
+// @Summary     删除保存的搜索
+// @Tags        saved-search
+// @Security    BearerAuth
+// @Param       id  path     string true "保存的搜索 ID"
+// @Success     204 "No Content"
+// @Failure     400 {object} ErrorResponse "无效的 ID"
+// @Failure     403 {object} ErrorResponse "不是本人保存的搜索"
+// @Failure     404 {object} ErrorResponse "未找到"
+// @Failure     500 {object} ErrorResponse "服务器内部错误"
+// @Router      /api/saved-searches/{id} [delete]
+func (ctl *SavedSearchController) Delete(c *gin.Context) {
+	id, userID, ok := parseIDs(c)
+	if !ok {
+		return
+	}
+	if err := ctl.svc.DeleteSavedSearch(id, userID); err != nil {
+		handleError(c, err)
+		return
+	}
+	c.Status(http.StatusNoContent)
+}
+
+// Pause godoc
+// @Summary     暂停保存的搜索
+// @Description 暂停期间发布的悬赏令不再比对，恢复后也不补发
+// @Tags        saved-search
+// @Security    BearerAuth
+// @Produce     json
+// @Param       id  path     string true "保存的搜索 ID"
+// @Success     200 {object} SavedSearchResponse
+// @Failure     403 {object} ErrorResponse "不是本人保存的搜索"
+// @Failure     404 {object} ErrorResponse "未找到"
+// @Router      /api/saved-searches/{id}/pause [post]
+func (ctl *SavedSearchController) Pause(c *gin.Context) {
+	ctl.changeState(c, ctl.svc.PauseSavedSearch)
+}
+
+// Resume godoc
+// @Summary     恢复保存的搜索
+// @Tags        saved-search
+// @Security    BearerAuth
+// @Produce     json
+// @Param       id  path     string true "保存的搜索 ID"
+// @Success     200 {object} SavedSearchResponse
+// @Failure     403 {object} ErrorResponse "不是本人保存的搜索"
+// @Failure     404 {object} ErrorResponse "未找到"
+// @Router      /api/saved-searches/{id}/resume [post]
+func (ctl *SavedSearchController) Resume(c *gin.Context) {
+	ctl.changeState(c, ctl.svc.ResumeSavedSearch)
+}
+
+// ListMatches godoc
+// @Summary     最近命中的悬赏令
+// @Description 按命中时间由新到旧列出，不含已删除或不再可见的悬赏令
+// @Tags        saved-search
+// @Security    BearerAuth
+// @Produce     json
+// @Param       id         path  string true  "保存的搜索 ID"
+// @Param       cursor     query string false "上一页返回的 next_cursor，为空表示第一页"
+// @Param       size       query int    false "每页大小" default(20)
+// @Param       with_total query bool   false "是否返回总数"
+// @Success     200  {object} pagination.Result[MatchResponse]
+// @Failure     403  {object} ErrorResponse "不是本人保存的搜索"
+// @Failure     404  {object} ErrorResponse "未找到"
+// @Failure     500  {object} ErrorResponse "服务器内部错误"
+// @Router      /api/saved-searches/{id}/matches [get]
+func (ctl *SavedSearchController) ListMatches(c *gin.Context) {
+	id, userID, ok := parseIDs(c)
+	if !ok {
+		return
+	}
+	page, err := pagination.FromQuery(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: err.Error()})
+		return
+	}
+	list, err := ctl.svc.ListMatches(id, userID, page)
+	if err != nil {
+		handleError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, pagination.Map(list, func(m *dao.SavedSearchMatch) MatchResponse {
+		return MatchResponse{
+			BountyID:   m.BountyID,
+			Title:      m.Bounty.Title,
+			Reward:     m.Bounty.Reward,
+			Currency:   m.Bounty.Currency,
+			Category:   m.Bounty.Category,
+			Tags:       m.Bounty.Tags,
+			Status:     string(m.Bounty.Status),
+			Deadline:   m.Bounty.Deadline,
+			MatchedAt:  m.CreatedAt,
+			NotifiedAt: m.NotifiedAt,
+		}
+	}))
+}
+
+// changeState 暂停／恢复共用的处理流程
+func (ctl *SavedSearchController) changeState(c *gin.Context, fn func(id, userID uuid.UUID) (*dao.SavedSearch, error)) {
+	id, userID, ok := parseIDs(c)
+	if !ok {
+		return
+	}
+	ss, err := fn(id, userID)
+	if err != nil {
+		handleError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, toResponse(ss))
+}
+
+// parseIDs 解析路径中的搜索 ID 与当前用户 ID
+func parseIDs(c *gin.Context) (uuid.UUID, uuid.UUID, bool) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: "invalid saved search ID"})
+		return uuid.Nil, uuid.Nil, false
+	}
+	raw, _ := c.Get("userID")
+	return id, raw.(uuid.UUID), true
+}
+
+// toFilter 将请求中的筛选条件转为 dao.SavedSearchFilter
+func toFilter(f *FilterBody) (*dao.SavedSearchFilter, error) {
+	out := &dao.SavedSearchFilter{
+		Location:   f.Location,
+		NearLat:    f.NearLat,
+		NearLng:    f.NearLng,
+		RadiusKm:   f.RadiusKm,
+		Categories: f.Categories,
+		Tags:       f.Tags,
+		Currency:   f.Currency,
+		Priorities: f.Priorities,
+		MinReward:  f.MinReward,
+		MaxReward:  f.MaxReward,
+	}
+	switch f.TagsMatch {
+	case "", "any":
+	case "all":
+		out.AllTags = true
+	default:
+		return nil, errors.New("invalid tags_match; use any or all")
+	}
+	return out, nil
+}
+
+// toResponse 将 dao.SavedSearch 转为返回体
+func toResponse(ss *dao.SavedSearch) SavedSearchResponse {
+	f := ss.Filter
+	filter := FilterBody{
+		Location:   f.Location,
+		NearLat:    f.NearLat,
+		NearLng:    f.NearLng,
+		RadiusKm:   f.RadiusKm,
+		Categories: f.Categories,
+		Tags:       f.Tags,
+		Currency:   f.Currency,
+		Priorities: f.Priorities,
+		MinReward:  f.MinReward,
+		MaxReward:  f.MaxReward,
+	}
+	if f.AllTags {
+		filter.TagsMatch = "all"
+	}
+	return SavedSearchResponse{
+		ID:             ss.ID,
+		Name:           ss.Name,
+		Query:          ss.Query,
+		Filter:         filter,
+		Delivery:       string(ss.Delivery),
+		Paused:         ss.Paused,
+		LastNotifiedAt: ss.LastNotifiedAt,
+		CreatedAt:      ss.CreatedAt,
+		UpdatedAt:      ss.UpdatedAt,
+	}
+}
+
+// handleError 将业务错误映射为 HTTP 状态码
+func handleError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, service.ErrSavedSearchNotFound):
+		c.JSON(http.StatusNotFound, ErrorResponse{Error: err.Error()})
+	case errors.Is(err, service.ErrNotSavedSearchOwner):
+		c.JSON(http.StatusForbidden, ErrorResponse{Error: err.Error()})
+	case errors.Is(err, service.ErrSavedSearchNameRequired),
+		errors.Is(err, service.ErrEmptySavedSearch),
+		errors.Is(err, service.ErrEmptySearchQuery),
+		errors.Is(err, service.ErrInvalidDelivery),
+		errors.Is(err, service.ErrInvalidCoordinates),
+		errors.Is(err, service.ErrInvalidRadius),
+		errors.Is(err, service.ErrInvalidRange),
+		errors.Is(err, service.ErrUnknownCategory):
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: err.Error()})
+	case errors.Is(err, service.ErrTooManySavedSearches):
+		c.JSON(http.StatusConflict, ErrorResponse{Error: err.Error()})
+	default:
+		c.JSON(http.StatusInternalServerError, ErrorResponse{Error: err.Error()})
+	}
+}
@@ -0,0 +1,260 @@
+package submission
+
+import (
+	"errors"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"net/http"
+	"onepenny-server/internal/pagination"
+	"onepenny-server/internal/repository"
+	"onepenny-server/internal/service"
+	"onepenny-server/model/dao"
+	"time"
+)
+
+// SubmissionController 提供交付物提交与审核相关的 HTTP 接口
+type SubmissionController struct {
+	svc service.SubmissionService
+}
+
+// NewSubmissionController 注入 SubmissionService
+func NewSubmissionController(svc service.SubmissionService) *SubmissionController {
+	return &SubmissionController{svc: svc}
+}
+
+// CreateSubmissionRequest 提交交付物请求体
+type CreateSubmissionRequest struct {
+	Content        string   `json:"content" binding:"required"`
+	AttachmentURLs []string `json:"attachment_urls,omitempty"`
+}
+
+// RequestChangesRequest 要求修改请求体
+type RequestChangesRequest struct {
+	Comment string `json:"comment" binding:"required"`
+}
+
+// SubmissionResponse 交付物返回体
+type SubmissionResponse struct {
+	ID             uuid.UUID  `json:"id"`
+	BountyID       uuid.UUID  `json:"bounty_id"`
+	SubmitterID    uuid.UUID  `json:"submitter_id"`
+	Version        int        `json:"version"`
+	Content        string     `json:"content"`
+	AttachmentURLs []string   `json:"attachment_urls,omitempty"`
+	Status         string     `json:"status"`
+	ReviewComment  string     `json:"review_comment,omitempty"`
+	ReviewedAt     *time.Time `json:"reviewed_at,omitempty"`
+	CreatedAt      time.Time  `json:"created_at"`
+}
+
+// ErrorResponse 通用错误返回体
+type ErrorResponse struct {
+	Error string `json:"error"`
+}
+
+// Create godoc
+// @Summary     提交交付物
+// @Description 接收者提交交付内容与附件并发起结算，每次提交生成新版本，悬赏令进入待结算
+// @Tags        submission
+// @Security    BearerAuth
+// @Accept      json
+// @Produce     json
+// @Param       id  path     string                  true "悬赏令 ID"
+// @Param       req body     CreateSubmissionRequest true "交付内容"
+// @Success     201 {object} SubmissionResponse
+// @Failure     400 {object} ErrorResponse "参数格式错误"
+// @Failure     401 {object} ErrorResponse "未授权"
+// @Failure     403 {object} ErrorResponse "不是接收者"
+// @Failure     404 {object} ErrorResponse "未找到悬赏令"
+// @Failure     409 {object} ErrorResponse "悬赏令当前状态不允许提交"
+// @Failure     500 {object} ErrorResponse "服务器内部错误"
+// @Router      /api/bounties/{id}/submissions [post]
+func (ctl *SubmissionController) Create(c *gin.Context) {
+	bountyID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: "invalid bounty ID"})
+		return
+	}
+
+	var req CreateSubmissionRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: err.Error()})
+		return
+	}
+
+	raw, _ := c.Get("userID")
+	userID := raw.(uuid.UUID)
+
+	s, err := ctl.svc.Submit(&service.SubmitInput{
+		BountyID:       bountyID,
+		SubmitterID:    userID,
+		Content:        req.Content,
+		AttachmentURLs: req.AttachmentURLs,
+	})
+	if err != nil {
+		handleError(c, err)
+		return
+	}
+	c.JSON(http.StatusCreated, toResponse(s))
+}
+
+// List godoc
+// @Summary     交付历史
+// @Description 发布者与接收者按版本顺序查看悬赏令的全部交付物及审核意见
+// @Tags        submission
+// @Security    BearerAuth
+// @Produce     json
+// @Param       id         path  string true  "悬赏令 ID"
+// @Param       cursor     query string false "上一页返回的 next_cursor，为空表示第一页"
+// @Param       size       query int    false "每页大小" default(20)
+// @Param       with_total query bool   false "是否返回总数"
+// @Success     200   {object}  pagination.Result[SubmissionResponse]
+// @Failure     400   {object}  ErrorResponse "无效的 ID"
+// @Failure     403   {object}  ErrorResponse "非悬赏令当事人"
+// @Failure     404   {object}  ErrorResponse "未找到悬赏令"
+// @Failure     500   {object}  ErrorResponse "服务器内部错误"
+// @Router      /api/bounties/{id}/submissions [get]
+func (ctl *SubmissionController) List(c *gin.Context) {
+	bountyID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: "invalid bounty ID"})
+		return
+	}
+
+	raw, _ := c.Get("userID")
+	userID := raw.(uuid.UUID)
+
+	page, err := pagination.FromQuery(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: err.Error()})
+		return
+	}
+	list, err := ctl.svc.ListByBounty(bountyID, userID, page)
+	if err != nil {
+		handleError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, pagination.Map(list, toResponse))
+}
+
+// Accept godoc
+// @Summary     通过交付物
+// @Description 发布者通过待审核的交付物，悬赏令结算并发放托管赏金
+// @Tags        submission
+// @Security    BearerAuth
+// @Produce     json
+// @Param       id            path     string true "悬赏令 ID"
+// @Param       submission_id path     string true "交付物 ID"
+// @Success     200 {object} SubmissionResponse
+// @Failure     400 {object} ErrorResponse "无效的 ID"
+// @Failure     403 {object} ErrorResponse "不是发布者"
+// @Failure     404 {object} ErrorResponse "未找到悬赏令或交付物"
+// @Failure     409 {object} ErrorResponse "交付物不在待审核状态"
+// @Failure     500 {object} ErrorResponse "服务器内部错误"
+// @Router      /api/bounties/{id}/submissions/{submission_id}/accept [post]
+func (ctl *SubmissionController) Accept(c *gin.Context) {
+	bountyID, subID, ok := parseIDs(c)
+	if !ok {
+		return
+	}
+
+	raw, _ := c.Get("userID")
+	userID := raw.(uuid.UUID)
+
+	s, err := ctl.svc.Accept(bountyID, subID, userID)
+	if err != nil {
+		handleError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, toResponse(s))
+}
+
+// RequestChanges godoc
+// @Summary     要求修改交付物
+// @Description 发布者附上修改意见退回交付物，悬赏令回到进行中，接收者可提交新版本
+// @Tags        submission
+// @Security    BearerAuth
+// @Accept      json
+// @Produce     json
+// @Param       id            path     string                true "悬赏令 ID"
+// @Param       submission_id path     string                true "交付物 ID"
+// @Param       req           body     RequestChangesRequest true "修改意见"
+// @Success     200 {object} SubmissionResponse
+// @Failure     400 {object} ErrorResponse "参数格式错误或无效的 ID"
+// @Failure     403 {object} ErrorResponse "不是发布者"
+// @Failure     404 {object} ErrorResponse "未找到悬赏令或交付物"
+// @Failure     409 {object} ErrorResponse "交付物不在待审核状态"
+// @Failure     500 {object} ErrorResponse "服务器内部错误"
+// @Router      /api/bounties/{id}/submissions/{submission_id}/request-changes [post]
+func (ctl *SubmissionController) RequestChanges(c *gin.Context) {
+	bountyID, subID, ok := parseIDs(c)
+	if !ok {
+		return
+	}
+
+	var req RequestChangesRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: err.Error()})
+		return
+	}
+
+	raw, _ := c.Get("userID")
+	userID := raw.(uuid.UUID)
+
+	s, err := ctl.svc.RequestChanges(bountyID, subID, userID, req.Comment)
+	if err != nil {
+		handleError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, toResponse(s))
+}
+
+// parseIDs 解析路径中的悬赏令 ID 与交付物 ID，失败时直接写入 400
+func parseIDs(c *gin.Context) (uuid.UUID, uuid.UUID, bool) {
+	bountyID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: "invalid bounty ID"})
+		return uuid.Nil, uuid.Nil, false
+	}
+	subID, err := uuid.Parse(c.Param("submission_id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: "invalid submission ID"})
+		return uuid.Nil, uuid.Nil, false
+	}
+	return bountyID, subID, true
+}
+
+// toResponse 将 dao.Submission 转为返回体
+func toResponse(s *dao.Submission) SubmissionResponse {
+	return SubmissionResponse{
+		ID:             s.ID,
+		BountyID:       s.BountyID,
+		SubmitterID:    s.SubmitterID,
+		Version:        s.Version,
+		Content:        s.Content,
+		AttachmentURLs: s.AttachmentURLs,
+		Status:         string(s.Status),
+		ReviewComment:  s.ReviewComment,
+		ReviewedAt:     s.ReviewedAt,
+		CreatedAt:      s.CreatedAt,
+	}
+}
+
+// handleError 将业务错误映射为 HTTP 状态码
+func handleError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, service.ErrBountyNotFound),
+		errors.Is(err, repository.ErrSubmissionNotFound):
+		c.JSON(http.StatusNotFound, ErrorResponse{Error: err.Error()})
+	case errors.Is(err, repository.ErrNotBountyParty),
+		errors.Is(err, repository.ErrNotBountyOwner),
+		errors.Is(err, repository.ErrNotBountyReceiver):
+		c.JSON(http.StatusForbidden, ErrorResponse{Error: err.Error()})
+	case errors.Is(err, repository.ErrSubmissionNotPending),
+		errors.Is(err, repository.ErrBountyHasMilestones),
+		errors.Is(err, service.ErrIllegalBountyTransition):
+		c.JSON(http.StatusConflict, ErrorResponse{Error: err.Error()})
+	default:
+		c.JSON(http.StatusInternalServerError, ErrorResponse{Error: err.Error()})
+	}
+}
@@ -0,0 +1,207 @@
+package tag
+
+import (
+	"errors"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"net/http"
+	"onepenny-server/internal/pagination"
+	"onepenny-server/internal/service"
+	"onepenny-server/model/dao"
+)
+
+// TagController 提供标签自动补全、关注与别名管理的 HTTP 接口
+type TagController struct {
+	svc service.TagService
+}
+
+// NewTagController 注入 TagService
+func NewTagController(svc service.TagService) *TagController {
+	return &TagController{svc: svc}
+}
+
+// TagResponse 标签返回体
+type TagResponse struct {
+	Slug       string   `json:"slug"`
+	Name       string   `json:"name"`
+	UsageCount int64    `json:"usage_count"`
+	Aliases    []string `json:"aliases,omitempty"`
+}
+
+// AddAliasRequest 添加别名请求体
+type AddAliasRequest struct {
+	Alias string `json:"alias" binding:"required"`
+}
+
+// ErrorResponse 通用错误返回体
+type ErrorResponse struct {
+	Error string `json:"error"`
+}
+
+// Autocomplete godoc
+// @Summary     标签自动补全
+// @Description 按前缀匹配标签的规范写法或别名，使用次数多的在前；前缀为空时列出最热门的标签
+// @Tags        tag
+// @Security    BearerAuth
+// @Produce     json
+// @Param       prefix     query string false "前缀，大小写、空格与连字符不敏感"
+// @Param       cursor     query string false "上一页返回的 next_cursor，为空表示第一页"
+// @Param       size       query int    false "每页大小" default(20)
+// @Param       with_total query bool   false "是否返回总数"
+// @Success     200 {object} pagination.Result[TagResponse]
+// @Failure     400 {object} ErrorResponse "参数格式错误"
+// @Failure     500 {object} ErrorResponse "服务器内部错误"
+// @Router      /api/tags [get]
+func (ctl *TagController) Autocomplete(c *gin.Context) {
+	page, err := pagination.FromQuery(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: err.Error()})
+		return
+	}
+
+	list, err := ctl.svc.Autocomplete(c.Query("prefix"), page)
+	if err != nil {
+		handleError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, pagination.Map(list, toResponse))
+}
+
+// ListFollowing godoc
+// @Summary     我关注的标签
+// @Description 按关注时间由新到旧分页列出当前用户关注的标签
+// @Tags        tag
+// @Security    BearerAuth
+// @Produce     json
+// @Param       cursor     query string false "上一页返回的 next_cursor，为空表示第一页"
+// @Param       size       query int    false "每页大小" default(20)
+// @Param       with_total query bool   false "是否返回总数"
+// @Success     200 {object} pagination.Result[TagResponse]
+// @Failure     400 {object} ErrorResponse "参数格式错误"
+// @Failure     401 {object} ErrorResponse "未授权"
+// @Failure     500 {object} ErrorResponse "服务器内部错误"
+// @Router      /api/tags/following [get]
+func (ctl *TagController) ListFollowing(c *gin.Context) {
+	page, err := pagination.FromQuery(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: err.Error()})
+		return
+	}
+
+	raw, _ := c.Get("userID")
+	userID := raw.(uuid.UUID)
+
+	list, err := ctl.svc.ListFollowing(userID, page)
+	if err != nil {
+		handleError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, pagination.Map(list, toResponse))
+}
+
+// Follow godoc
+// @Summary     关注标签
+// @Description 关注后，带有该标签的公开悬赏令发布时会收到通知；重复关注不报错
+// @Tags        tag
+// @Security    BearerAuth
+// @Produce     json
+// @Param       tag path     string true "标签，任意写法或别名均可"
+// @Success     200 {object} TagResponse
+// @Failure     400 {object} ErrorResponse "无效的标签"
+// @Failure     401 {object} ErrorResponse "未授权"
+// @Failure     404 {object} ErrorResponse "未找到标签"
+// @Failure     500 {object} ErrorResponse "服务器内部错误"
+// @Router      /api/tags/{tag}/follow [post]
+func (ctl *TagController) Follow(c *gin.Context) {
+	raw, _ := c.Get("userID")
+	userID := raw.(uuid.UUID)
+
+	t, err := ctl.svc.Follow(userID, c.Param("tag"))
+	if err != nil {
+		handleError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, toResponse(t))
+}
+
+// Unfollow godoc
+// @Summary     取消关注标签
+// @Tags        tag
+// @Security    BearerAuth
+// @Produce     json
+// @Param       tag path string true "标签，任意写法或别名均可"
+// @Success     204 "No Content"
+// @Failure     400 {object} ErrorResponse "无效的标签"
+// @Failure     401 {object} ErrorResponse "未授权"
+// @Failure     404 {object} ErrorResponse "未找到标签"
+// @Failure     500 {object} ErrorResponse "服务器内部错误"
+// @Router      /api/tags/{tag}/follow [delete]
+func (ctl *TagController) Unfollow(c *gin.Context) {
+	raw, _ := c.Get("userID")
+	userID := raw.(uuid.UUID)
+
+	if err := ctl.svc.Unfollow(userID, c.Param("tag")); err != nil {
+		handleError(c, err)
+		return
+	}
+	c.Status(http.StatusNoContent)
+}
+
+// AddAlias godoc
+// @Summary     添加标签别名
+// @Description 为标签添加同义写法（仅管理员）；别名恰为另一个已有标签时将其合并，相关悬赏令与关注者一并迁移
+// @Tags        admin
+// @Security    BearerAuth
+// @Accept      json
+// @Produce     json
+// @Param       tag path     string          true "目标标签"
+// @Param       req body     AddAliasRequest true "别名"
+// @Success     200 {object} TagResponse
+// @Failure     400 {object} ErrorResponse "参数格式错误"
+// @Failure     401 {object} ErrorResponse "未授权"
+// @Failure     403 {object} ErrorResponse "权限不足"
+// @Failure     404 {object} ErrorResponse "未找到标签"
+// @Failure     409 {object} ErrorResponse "别名已属于另一个标签"
+// @Failure     500 {object} ErrorResponse "服务器内部错误"
+// @Router      /api/admin/tags/{tag}/aliases [post]
+func (ctl *TagController) AddAlias(c *gin.Context) {
+	var req AddAliasRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: err.Error()})
+		return
+	}
+
+	t, err := ctl.svc.AddAlias(c.Param("tag"), req.Alias)
+	if err != nil {
+		handleError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, toResponse(t))
+}
+
+// toResponse 将 dao.Tag 转为返回体
+func toResponse(t *dao.Tag) TagResponse {
+	resp := TagResponse{
+		Slug:       t.Slug,
+		Name:       t.Name,
+		UsageCount: t.UsageCount,
+	}
+	for _, a := range t.Aliases {
+		resp.Aliases = append(resp.Aliases, a.Alias)
+	}
+	return resp
+}
+
+// handleError 将业务错误映射为 HTTP 状态码
+func handleError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, pagination.ErrInvalidCursor), errors.Is(err, service.ErrInvalidTag):
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: err.Error()})
+	case errors.Is(err, service.ErrTagNotFound):
+		c.JSON(http.StatusNotFound, ErrorResponse{Error: err.Error()})
+	case errors.Is(err, service.ErrTagAliasTaken):
+		c.JSON(http.StatusConflict, ErrorResponse{Error: err.Error()})
+	default:
+		c.JSON(http.StatusInternalServerError, ErrorResponse{Error: err.Error()})
+	}
+}
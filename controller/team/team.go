@@ -4,8 +4,9 @@ import (
 	"github.com/gin-gonic/gin"
 	"github.com/google/uuid"
 	"net/http"
+	"onepenny-server/internal/pagination"
 	"onepenny-server/internal/service"
-	"strconv"
+	"onepenny-server/model/dao"
 	"time"
 )
 
@@ -132,9 +133,10 @@ func (ctl *TeamController) Create(c *gin.Context) {
 // @Tags        team
 // @Security    BearerAuth
 // @Produce     json
-// @Param       page query int false "页码" default(1)
-// @Param       size query int false "每页大小" default(20)
-// @Success     200 {array} TeamResponse "团队列表"
+// @Param       cursor     query string false "上一页返回的 next_cursor，为空表示第一页"
+// @Param       size       query int    false "每页大小" default(20)
+// @Param       with_total query bool   false "是否返回总数"
+// @Success     200 {object} pagination.Result[TeamResponse] "团队列表"
 // @Failure     401 {object} ErrorResponse "未授权"
 // @Failure     500 {object} ErrorResponse "服务器内部错误"
 // @Router      /api/teams [get]
@@ -144,35 +146,28 @@ func (ctl *TeamController) ListByOwner(c *gin.Context) {
 	ownerID := raw.(uuid.UUID)
 
 	// 分页参数
-	page, size := 1, 20
-	if p := c.Query("page"); p != "" {
-		if v, err := strconv.Atoi(p); err == nil && v > 0 {
-			page = v
-		}
-	}
-	if s := c.Query("size"); s != "" {
-		if v, err := strconv.Atoi(s); err == nil && v > 0 {
-			size = v
-		}
+	page, err := pagination.FromQuery(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: err.Error()})
+		return
 	}
 
 	// Service 层返回 []dao.Team（带 Members 预加载）
-	teams, err := ctl.svc.ListTeamsByOwner(ownerID, page, size)
+	teams, err := ctl.svc.ListTeamsByOwner(ownerID, page)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
 	}
 
 	// 构造 response
-	var resp []TeamResponse
-	for _, t := range teams {
+	c.JSON(http.StatusOK, pagination.Map(teams, func(t *dao.Team) TeamResponse {
 		// 从 t.Members（[]User）提取出每个 User.ID
 		ids := make([]uuid.UUID, 0, len(t.Members))
 		for _, member := range t.Members {
 			ids = append(ids, member.ID)
 		}
 
-		resp = append(resp, TeamResponse{
+		return TeamResponse{
 			ID:          t.ID,
 			Name:        t.Name,
 			Description: t.Description,
@@ -180,10 +175,8 @@ func (ctl *TeamController) ListByOwner(c *gin.Context) {
 			MemberIDs:   ids, // ← 这里用我们新建的 ids 切片
 			CreatedAt:   t.CreatedAt,
 			UpdatedAt:   t.UpdatedAt,
-		})
-	}
-
-	c.JSON(http.StatusOK, resp)
+		}
+	}))
 }
 
 // Get godoc
@@ -359,10 +352,11 @@ func (ctl *TeamController) RemoveMember(c *gin.Context) {
 // @Tags        team
 // @Security    BearerAuth
 // @Produce     json
-// @Param       id   path     string  true  "团队 ID"
-// @Param       page query    int     false "页码"    default(1)
-// @Param       size query    int     false "每页数量" default(20)
-// @Success     200 {array}   MemberResponse      "成员列表"
+// @Param       id         path  string true  "团队 ID"
+// @Param       cursor     query string false "上一页返回的 next_cursor，为空表示第一页"
+// @Param       size       query int    false "每页数量" default(20)
+// @Param       with_total query bool   false "是否返回总数"
+// @Success     200 {object}  pagination.Result[MemberResponse]      "成员列表"
 // @Failure     400 {object}  ErrorResponse       "无效参数"
 // @Failure     500 {object}  ErrorResponse       "服务器内部错误"
 // @Router      /api/teams/{id}/members [get]
@@ -372,18 +366,12 @@ func (ctl *TeamController) ListMembers(c *gin.Context) {
 		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid team ID"})
 		return
 	}
-	page, size := 1, 20
-	if p := c.Query("page"); p != "" {
-		if v, err := strconv.Atoi(p); err == nil && v > 0 {
-			page = v
-		}
-	}
-	if s := c.Query("size"); s != "" {
-		if v, err := strconv.Atoi(s); err == nil && v > 0 {
-			size = v
-		}
+	page, err := pagination.FromQuery(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: err.Error()})
+		return
 	}
-	members, err := ctl.svc.ListTeamMembers(teamID, page, size)
+	members, err := ctl.svc.ListTeamMembers(teamID, page)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
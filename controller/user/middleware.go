@@ -2,6 +2,7 @@ package user
 
 import (
 	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
 	"net/http"
 	"onepenny-server/util"
 	"strings"
@@ -34,3 +35,26 @@ func AuthMiddleware() gin.HandlerFunc {
 		c.Next()
 	}
 }
+
+// RequireRole 必须挂在 AuthMiddleware 之后，仅放行具备任一给定角色的用户
+func (ctl *AuthController) RequireRole(roles ...string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		raw, _ := c.Get("userID")
+		userID, ok := raw.(uuid.UUID)
+		if !ok {
+			c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+			return
+		}
+
+		u, err := ctl.svc.GetProfile(userID)
+		if err != nil {
+			c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "user not found"})
+			return
+		}
+		if !u.HasRole(roles...) {
+			c.AbortWithStatusJSON(http.StatusForbidden, gin.H{"error": "permission denied"})
+			return
+		}
+		c.Next()
+	}
+}
@@ -1,8 +1,9 @@
 package user
 
 import (
+	"errors"
 	"net/http"
-	"strconv"
+	"onepenny-server/internal/pagination"
 
 	"github.com/gin-gonic/gin"
 	"github.com/google/uuid"
@@ -45,6 +46,11 @@ type TotalEarnedResponse struct {
 	TotalEarned float64 `json:"total_earned"`
 }
 
+// TotalContributedResponse 按币种统计的追加赏金总额
+type TotalContributedResponse struct {
+	TotalContributed map[string]float64 `json:"total_contributed"`
+}
+
 // CountResponse 通用计数返回体
 type CountResponse struct {
 	Count int64 `json:"count"`
@@ -71,10 +77,11 @@ type StatsErrorResponse struct {
 // @Tags        user
 // @Security    BearerAuth
 // @Produce     json
-// @Param       status query    string true  "状态"        Enums(Created,Settling,Settled,Cancelled)
-// @Param       page   query    int    false "页码"      default(1)
-// @Param       size   query    int    false "每页数量"  default(20)
-// @Success     200    {array}  BountySummary
+// @Param       status     query string true  "状态"        Enums(Created,Settling,Settled,Cancelled)
+// @Param       cursor     query string false "上一页返回的 next_cursor，为空表示第一页"
+// @Param       size       query int    false "每页数量"  default(20)
+// @Param       with_total query bool   false "是否返回总数"
+// @Success     200    {object} pagination.Result[BountySummary]
 // @Failure     401    {object} ErrorResponse
 // @Failure     500    {object} ErrorResponse
 // @Router      /api/user/bounties/status [get]
@@ -83,27 +90,20 @@ func (ctl *UserStatsController) ListMyBountiesByStatus(c *gin.Context) {
 	userID := raw.(uuid.UUID)
 
 	status := c.Query("status")
-	page, size := 1, 20
-	if p := c.Query("page"); p != "" {
-		if v, err := strconv.Atoi(p); err == nil && v > 0 {
-			page = v
-		}
-	}
-	if s := c.Query("size"); s != "" {
-		if v, err := strconv.Atoi(s); err == nil && v > 0 {
-			size = v
-		}
+	page, err := pagination.FromQuery(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, StatsErrorResponse{Error: err.Error()})
+		return
 	}
 
-	list, err := ctl.svc.ListMyBountiesByStatus(userID, status, page, size)
+	list, err := ctl.svc.ListMyBountiesByStatus(userID, status, page)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, ErrorResponse{Error: err.Error()})
 		return
 	}
 
-	resp := make([]BountySummary, len(list))
-	for i, b := range list {
-		resp[i] = BountySummary{
+	c.JSON(http.StatusOK, pagination.Map(list, func(b service.BountySummary) BountySummary {
+		return BountySummary{
 			ID:        b.ID,
 			Title:     b.Title,
 			Status:    b.Status,
@@ -112,8 +112,7 @@ func (ctl *UserStatsController) ListMyBountiesByStatus(c *gin.Context) {
 			CreatedAt: b.CreatedAt.Format("2006-01-02T15:04:05Z07:00"),
 			UpdatedAt: b.UpdatedAt.Format("2006-01-02T15:04:05Z07:00"),
 		}
-	}
-	c.JSON(http.StatusOK, resp)
+	}))
 }
 
 // GetApplicationsForMyBounty godoc
@@ -122,10 +121,11 @@ func (ctl *UserStatsController) ListMyBountiesByStatus(c *gin.Context) {
 // @Tags        user
 // @Security    BearerAuth
 // @Produce     json
-// @Param       bounty_id path     string true  "悬赏令 ID"
-// @Param       page      query    int    false "页码"     default(1)
-// @Param       size      query    int    false "每页数量" default(20)
-// @Success     200       {array}  ApplicationSummary
+// @Param       bounty_id  path  string true  "悬赏令 ID"
+// @Param       cursor     query string false "上一页返回的 next_cursor，为空表示第一页"
+// @Param       size       query int    false "每页数量" default(20)
+// @Param       with_total query bool   false "是否返回总数"
+// @Success     200       {object} pagination.Result[ApplicationSummary]
 // @Failure     400       {object} ErrorResponse
 // @Failure     401       {object} ErrorResponse
 // @Failure     500       {object} ErrorResponse
@@ -140,27 +140,20 @@ func (ctl *UserStatsController) GetApplicationsForMyBounty(c *gin.Context) {
 		return
 	}
 
-	page, size := 1, 20
-	if p := c.Query("page"); p != "" {
-		if v, err := strconv.Atoi(p); err == nil && v > 0 {
-			page = v
-		}
-	}
-	if s := c.Query("size"); s != "" {
-		if v, err := strconv.Atoi(s); err == nil && v > 0 {
-			size = v
-		}
+	page, err := pagination.FromQuery(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, StatsErrorResponse{Error: err.Error()})
+		return
 	}
 
-	list, err := ctl.svc.ListApplicationsForMyBounty(userID, bountyID, page, size)
+	list, err := ctl.svc.ListApplicationsForMyBounty(userID, bountyID, page)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, ErrorResponse{Error: err.Error()})
 		return
 	}
 
-	resp := make([]ApplicationSummary, len(list))
-	for i, a := range list {
-		resp[i] = ApplicationSummary{
+	c.JSON(http.StatusOK, pagination.Map(list, func(a service.ApplicationSummary) ApplicationSummary {
+		return ApplicationSummary{
 			ID:        a.ID,
 			BountyID:  a.BountyID,
 			Proposal:  a.Proposal,
@@ -168,8 +161,7 @@ func (ctl *UserStatsController) GetApplicationsForMyBounty(c *gin.Context) {
 			CreatedAt: a.CreatedAt.Format("2006-01-02T15:04:05Z07:00"),
 			UpdatedAt: a.UpdatedAt.Format("2006-01-02T15:04:05Z07:00"),
 		}
-	}
-	c.JSON(http.StatusOK, resp)
+	}))
 }
 
 // GetTotalEarned godoc
@@ -194,15 +186,38 @@ func (ctl *UserStatsController) GetTotalEarned(c *gin.Context) {
 	c.JSON(http.StatusOK, TotalEarnedResponse{TotalEarned: total})
 }
 
+// GetTotalContributed godoc
+// @Summary     统计追加赏金
+// @Description 按币种统计当前用户为他人悬赏令追加的赏金总额（已退回的不计入）
+// @Tags        user
+// @Security    BearerAuth
+// @Produce     json
+// @Success     200 {object} TotalContributedResponse
+// @Failure     401 {object} ErrorResponse
+// @Failure     500 {object} ErrorResponse
+// @Router      /api/user/stats/total-contributed [get]
+func (ctl *UserStatsController) GetTotalContributed(c *gin.Context) {
+	raw, _ := c.Get("userID")
+	userID := raw.(uuid.UUID)
+
+	totals, err := ctl.svc.GetTotalContributed(userID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, ErrorResponse{Error: err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, TotalContributedResponse{TotalContributed: totals})
+}
+
 // ListLikedBounties godoc
 // @Summary     列出已点赞的悬赏
 // @Description 分页获取当前用户点赞过的悬赏令
 // @Tags        user
 // @Security    BearerAuth
 // @Produce     json
-// @Param       page query int false "页码"    default(1)
-// @Param       size query int false "每页数量" default(20)
-// @Success     200 {array} BountySummary
+// @Param       cursor     query string false "上一页返回的 next_cursor，为空表示第一页"
+// @Param       size       query int    false "每页数量" default(20)
+// @Param       with_total query bool   false "是否返回总数"
+// @Success     200 {object} pagination.Result[BountySummary]
 // @Failure     401 {object} ErrorResponse
 // @Failure     500 {object} ErrorResponse
 // @Router      /api/user/stats/liked-bounties [get]
@@ -210,27 +225,24 @@ func (ctl *UserStatsController) ListLikedBounties(c *gin.Context) {
 	raw, _ := c.Get("userID")
 	userID := raw.(uuid.UUID)
 
-	page, size := 1, 20
-	if p := c.Query("page"); p != "" {
-		if v, err := strconv.Atoi(p); err == nil && v > 0 {
-			page = v
-		}
-	}
-	if s := c.Query("size"); s != "" {
-		if v, err := strconv.Atoi(s); err == nil && v > 0 {
-			size = v
-		}
+	page, err := pagination.FromQuery(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, StatsErrorResponse{Error: err.Error()})
+		return
 	}
 
-	list, err := ctl.svc.ListLikedBounties(userID, page, size)
+	list, err := ctl.svc.ListLikedBounties(userID, page)
+	if errors.Is(err, pagination.ErrInvalidCursor) {
+		c.JSON(http.StatusBadRequest, StatsErrorResponse{Error: err.Error()})
+		return
+	}
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, ErrorResponse{Error: err.Error()})
 		return
 	}
 
-	resp := make([]BountySummary, len(list))
-	for i, b := range list {
-		resp[i] = BountySummary{
+	c.JSON(http.StatusOK, pagination.Map(list, func(b service.BountySummary) BountySummary {
+		return BountySummary{
 			ID:        b.ID,
 			Title:     b.Title,
 			Status:    b.Status,
@@ -239,8 +251,7 @@ func (ctl *UserStatsController) ListLikedBounties(c *gin.Context) {
 			CreatedAt: b.CreatedAt.Format("2006-01-02T15:04:05Z07:00"),
 			UpdatedAt: b.UpdatedAt.Format("2006-01-02T15:04:05Z07:00"),
 		}
-	}
-	c.JSON(http.StatusOK, resp)
+	}))
 }
 
 // ListViewedBounties godoc
@@ -249,9 +260,10 @@ func (ctl *UserStatsController) ListLikedBounties(c *gin.Context) {
 // @Tags        user
 // @Security    BearerAuth
 // @Produce     json
-// @Param       page query int false "页码"    default(1)
-// @Param       size query int false "每页数量" default(20)
-// @Success     200 {array} BountySummary
+// @Param       cursor     query string false "上一页返回的 next_cursor，为空表示第一页"
+// @Param       size       query int    false "每页数量" default(20)
+// @Param       with_total query bool   false "是否返回总数"
+// @Success     200 {object} pagination.Result[BountySummary]
 // @Failure     401 {object} ErrorResponse
 // @Failure     500 {object} ErrorResponse
 // @Router      /api/user/stats/viewed-bounties [get]
@@ -259,27 +271,24 @@ func (ctl *UserStatsController) ListViewedBounties(c *gin.Context) {
 	raw, _ := c.Get("userID")
 	userID := raw.(uuid.UUID)
 
-	page, size := 1, 20
-	if p := c.Query("page"); p != "" {
-		if v, err := strconv.Atoi(p); err == nil && v > 0 {
-			page = v
-		}
-	}
-	if s := c.Query("size"); s != "" {
-		if v, err := strconv.Atoi(s); err == nil && v > 0 {
-			size = v
-		}
+	page, err := pagination.FromQuery(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, StatsErrorResponse{Error: err.Error()})
+		return
 	}
 
-	list, err := ctl.svc.ListViewedBounties(userID, page, size)
+	list, err := ctl.svc.ListViewedBounties(userID, page)
+	if errors.Is(err, pagination.ErrInvalidCursor) {
+		c.JSON(http.StatusBadRequest, StatsErrorResponse{Error: err.Error()})
+		return
+	}
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, ErrorResponse{Error: err.Error()})
 		return
 	}
 
-	resp := make([]BountySummary, len(list))
-	for i, b := range list {
-		resp[i] = BountySummary{
+	c.JSON(http.StatusOK, pagination.Map(list, func(b service.BountySummary) BountySummary {
+		return BountySummary{
 			ID:        b.ID,
 			Title:     b.Title,
 			Status:    b.Status,
@@ -288,8 +297,7 @@ func (ctl *UserStatsController) ListViewedBounties(c *gin.Context) {
 			CreatedAt: b.CreatedAt.Format("2006-01-02T15:04:05Z07:00"),
 			UpdatedAt: b.UpdatedAt.Format("2006-01-02T15:04:05Z07:00"),
 		}
-	}
-	c.JSON(http.StatusOK, resp)
+	}))
 }
 
 // CountApplications godoc
@@ -0,0 +1,194 @@
+package wallet
+
+import (
+	"errors"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"net/http"
+	"onepenny-server/internal/pagination"
+	"onepenny-server/internal/service"
+	"onepenny-server/model/dao"
+	"time"
+)
+
+// WalletController 提供钱包余额与流水相关的 HTTP 接口
+type WalletController struct {
+	svc service.WalletService
+}
+
+// NewWalletController 注入 WalletService
+func NewWalletController(svc service.WalletService) *WalletController {
+	return &WalletController{svc: svc}
+}
+
+// WalletResponse 钱包返回体
+type WalletResponse struct {
+	ID        uuid.UUID `json:"id"`
+	Currency  string    `json:"currency"`
+	Balance   float64   `json:"balance"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// LedgerEntryResponse 钱包流水返回体
+type LedgerEntryResponse struct {
+	ID            uuid.UUID  `json:"id"`
+	TransactionID uuid.UUID  `json:"transaction_id"`
+	Type          string     `json:"type"`
+	BountyID      *uuid.UUID `json:"bounty_id,omitempty"`
+	Currency      string     `json:"currency"`
+	Amount        float64    `json:"amount"`
+	BalanceAfter  float64    `json:"balance_after"`
+	CreatedAt     time.Time  `json:"created_at"`
+}
+
+// DepositRequest 充值请求体
+type DepositRequest struct {
+	UserID   uuid.UUID `json:"user_id"  binding:"required"`
+	Currency string    `json:"currency" binding:"required"`
+	Amount   float64   `json:"amount"   binding:"required"`
+}
+
+// ErrorResponse 通用错误返回体
+type ErrorResponse struct {
+	Error string `json:"error"`
+}
+
+// List godoc
+// @Summary     查看钱包余额
+// @Description 列出当前用户在各币种下的钱包余额（不含已托管的赏金）
+// @Tags        wallet
+// @Security    BearerAuth
+// @Produce     json
+// @Success     200 {object} pagination.Result[WalletResponse]
+// @Failure     401 {object} ErrorResponse "未授权"
+// @Failure     500 {object} ErrorResponse "服务器内部错误"
+// @Router      /api/wallet [get]
+func (ctl *WalletController) List(c *gin.Context) {
+	raw, exists := c.Get("userID")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, ErrorResponse{Error: "unauthorized"})
+		return
+	}
+	userID, ok := raw.(uuid.UUID)
+	if !ok {
+		c.JSON(http.StatusInternalServerError, ErrorResponse{Error: "invalid userID"})
+		return
+	}
+
+	list, err := ctl.svc.ListWallets(userID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, ErrorResponse{Error: err.Error()})
+		return
+	}
+
+	resp := make([]WalletResponse, len(list))
+	for i, w := range list {
+		resp[i] = WalletResponse{
+			ID:        w.ID,
+			Currency:  w.Currency,
+			Balance:   w.Balance,
+			UpdatedAt: w.UpdatedAt,
+		}
+	}
+	// 每个币种至多一个钱包，不分页
+	c.JSON(http.StatusOK, pagination.All(resp))
+}
+
+// ListEntries godoc
+// @Summary     查看钱包流水
+// @Description 分页获取当前用户钱包的记账分录，按时间倒序
+// @Tags        wallet
+// @Security    BearerAuth
+// @Produce     json
+// @Param       cursor     query string false "上一页返回的 next_cursor，为空表示第一页"
+// @Param       size       query int    false "每页大小" default(20)
+// @Param       with_total query bool   false "是否返回总数"
+// @Success     200   {object}  pagination.Result[LedgerEntryResponse]
+// @Failure     401   {object}  ErrorResponse "未授权"
+// @Failure     500   {object}  ErrorResponse "服务器内部错误"
+// @Router      /api/wallet/entries [get]
+func (ctl *WalletController) ListEntries(c *gin.Context) {
+	raw, exists := c.Get("userID")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, ErrorResponse{Error: "unauthorized"})
+		return
+	}
+	userID, ok := raw.(uuid.UUID)
+	if !ok {
+		c.JSON(http.StatusInternalServerError, ErrorResponse{Error: "invalid userID"})
+		return
+	}
+
+	page, err := pagination.FromQuery(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: err.Error()})
+		return
+	}
+
+	list, err := ctl.svc.ListEntries(userID, page)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, ErrorResponse{Error: err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, pagination.Map(list, func(e *dao.LedgerEntry) LedgerEntryResponse {
+		resp := LedgerEntryResponse{
+			ID:            e.ID,
+			TransactionID: e.TransactionID,
+			Amount:        e.Amount,
+			BalanceAfter:  e.BalanceAfter,
+			CreatedAt:     e.CreatedAt,
+		}
+		if e.Transaction != nil {
+			resp.Type = e.Transaction.Type
+			resp.BountyID = e.Transaction.BountyID
+		}
+		if e.Wallet != nil {
+			resp.Currency = e.Wallet.Currency
+		}
+		return resp
+	}))
+}
+
+// Deposit godoc
+// @Summary     钱包充值
+// @Description 管理员向指定用户指定币种的钱包充值；接入支付渠道前用于人工入账（仅管理员）
+// @Tags        admin
+// @Security    BearerAuth
+// @Accept      json
+// @Produce     json
+// @Param       req body     DepositRequest true "充值信息"
+// @Success     200 {object} WalletResponse
+// @Failure     400 {object} ErrorResponse "参数格式错误"
+// @Failure     401 {object} ErrorResponse "未授权"
+// @Failure     403 {object} ErrorResponse "无权限"
+// @Failure     500 {object} ErrorResponse "服务器内部错误"
+// @Router      /api/admin/wallets/deposit [post]
+func (ctl *WalletController) Deposit(c *gin.Context) {
+	var req DepositRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: err.Error()})
+		return
+	}
+
+	w, err := ctl.svc.Deposit(&service.DepositInput{
+		UserID:   req.UserID,
+		Currency: req.Currency,
+		Amount:   req.Amount,
+	})
+	if err != nil {
+		if errors.Is(err, service.ErrInvalidAmount) {
+			c.JSON(http.StatusBadRequest, ErrorResponse{Error: err.Error()})
+		} else {
+			c.JSON(http.StatusInternalServerError, ErrorResponse{Error: err.Error()})
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, WalletResponse{
+		ID:        w.ID,
+		Currency:  w.Currency,
+		Balance:   w.Balance,
+		UpdatedAt: w.UpdatedAt,
+	})
+}
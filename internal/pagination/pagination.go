@@ -0,0 +1,248 @@
+// Package pagination 提供列表接口共用的游标分页：
+// 按 (created_at, id) 定位的不透明游标、每页条数上限与统一的 {items, next_cursor, total} 返回体。
+package pagination
+
+import (
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+const (
+	// DefaultSize 未指定 size 时的每页条数
+	DefaultSize = 20
+	// MaxSize 每页条数上限，超出按上限处理
+	MaxSize = 100
+)
+
+// ErrInvalidCursor 游标无法解析或已被篡改
+var ErrInvalidCursor = errors.New("invalid cursor")
+
+// Order 按创建时间翻页的方向
+type Order int
+
+const (
+	NewestFirst Order = iota // 先新后旧
+	OldestFirst              // 先旧后新
+)
+
+// Keyed 可按 (created_at, id) 定位的记录，dao.BaseModel 已实现
+type Keyed interface {
+	PageKey() (time.Time, uuid.UUID)
+}
+
+// Page 一次分页请求
+type Page struct {
+	Size      int  // 每页条数，已限制在 [1, MaxSize]
+	WithTotal bool // 是否额外统计总数
+
+	after  *key // 上一页最后一条的位置
+	offset int  // 已跳过的条数：排序键不是创建时间的列表使用，也兼容旧的 page 参数
+}
+
+type key struct {
+	createdAt time.Time
+	id        uuid.UUID
+}
+
+// Result 统一的分页返回体；NextCursor 为空表示没有下一页，Total 仅在请求时返回
+type Result[T any] struct {
+	Items      []T    `json:"items"`
+	NextCursor string `json:"next_cursor"`
+	Total      *int64 `json:"total,omitempty"`
+}
+
+// New 构造分页请求：size 超出范围时取默认值或上限，cursor 为空表示第一页
+func New(size int, cursor string, withTotal bool) (Page, error) {
+	switch {
+	case size < 1:
+		size = DefaultSize
+	case size > MaxSize:
+		size = MaxSize
+	}
+	p := Page{Size: size, WithTotal: withTotal}
+	if cursor == "" {
+		return p, nil
+	}
+	if err := p.decode(cursor); err != nil {
+		return Page{}, err
+	}
+	return p, nil
+}
+
+// FromQuery 从请求参数 cursor、size、with_total 解析分页；
+// 未传 cursor 时仍接受旧的 page 参数，按偏移定位起始页
+func FromQuery(c *gin.Context) (Page, error) {
+	size, _ := strconv.Atoi(c.Query("size"))
+	withTotal, _ := strconv.ParseBool(c.Query("with_total"))
+
+	p, err := New(size, c.Query("cursor"), withTotal)
+	if err != nil {
+		return Page{}, err
+	}
+	if c.Query("cursor") == "" {
+		if v, err := strconv.Atoi(c.Query("page")); err == nil && v > 1 {
+			p.offset = (v - 1) * p.Size
+		}
+	}
+	return p, nil
+}
+
+// Skip 本页之前应跳过的条数，供自行以偏移翻页的查询使用；
+// 传入按创建时间生成的游标时返回 ErrInvalidCursor，以免静默地从第一页重新开始
+func (p Page) Skip() (int, error) {
+	if p.after != nil {
+		return 0, ErrInvalidCursor
+	}
+	return p.offset, nil
+}
+
+// Limit 本页应取的条数：多取一条用于判断是否还有下一页
+func (p Page) Limit() int { return p.Size + 1 }
+
+// Keyset 按 (created_at, id) 游标分页查询。
+// q 只应包含筛选条件，table 为排序列所属的表；预加载、Select 等只作用于取数的部分通过 scopes 传入，
+// 以免影响总数统计
+func Keyset[T Keyed](q *gorm.DB, p Page, table string, order Order, scopes ...func(*gorm.DB) *gorm.DB) (*Result[T], error) {
+	total, err := Total(q, p)
+	if err != nil {
+		return nil, err
+	}
+
+	dir, cmp := "DESC", "<"
+	if order == OldestFirst {
+		dir, cmp = "ASC", ">"
+	}
+	tx := q.Session(&gorm.Session{}).Scopes(scopes...)
+	switch {
+	case p.after != nil:
+		tx = tx.Where(fmt.Sprintf("(%s.created_at, %s.id) %s (?, ?)", table, table, cmp), p.after.createdAt, p.after.id)
+	case p.offset > 0:
+		tx = tx.Offset(p.offset)
+	}
+	var items []T
+	if err := tx.
+		Order(fmt.Sprintf("%s.created_at %s, %s.id %s", table, dir, table, dir)).
+		Limit(p.Limit()).
+		Find(&items).Error; err != nil {
+		return nil, err
+	}
+
+	res := &Result[T]{Total: total}
+	if len(items) > p.Size {
+		items = items[:p.Size]
+		createdAt, id := items[len(items)-1].PageKey()
+		res.NextCursor = encode(fmt.Sprintf("k:%d:%s", createdAt.UnixNano(), id))
+	}
+	res.Items = nonNil(items)
+	return res, nil
+}
+
+// Offset 按偏移分页查询，用于按赏金、热度、距离等非创建时间排序的列表；
+// 游标仍对调用方不透明。排序条件需通过 scopes 传入，以免影响总数统计
+func Offset[T any](q *gorm.DB, p Page, scopes ...func(*gorm.DB) *gorm.DB) (*Result[T], error) {
+	skip, err := p.Skip()
+	if err != nil {
+		return nil, err
+	}
+	total, err := Total(q, p)
+	if err != nil {
+		return nil, err
+	}
+	var items []T
+	if err := q.Session(&gorm.Session{}).
+		Scopes(scopes...).
+		Offset(skip).
+		Limit(p.Limit()).
+		Find(&items).Error; err != nil {
+		return nil, err
+	}
+	res := Slice(items, p)
+	res.Total = total
+	return res, nil
+}
+
+// Slice 由调用方按 Skip 与 Limit 自行取数后，截取本页并生成按偏移翻页的下一页游标
+func Slice[T any](items []T, p Page) *Result[T] {
+	res := &Result[T]{}
+	if len(items) > p.Size {
+		items = items[:p.Size]
+		res.NextCursor = encode(fmt.Sprintf("o:%d", p.offset+p.Size))
+	}
+	res.Items = nonNil(items)
+	return res
+}
+
+// All 包装不分页的有限列表，保持返回体一致
+func All[T any](items []T) *Result[T] {
+	return &Result[T]{Items: nonNil(items)}
+}
+
+// Map 转换分页结果中的每一项，游标与总数保持不变
+func Map[T, U any](r *Result[T], fn func(T) U) *Result[U] {
+	items := make([]U, len(r.Items))
+	for i, it := range r.Items {
+		items[i] = fn(it)
+	}
+	return &Result[U]{Items: items, NextCursor: r.NextCursor, Total: r.Total}
+}
+
+// Total 在请求了总数时统计 q 的记录数，否则返回 nil；q 不应包含排序与预加载
+func Total(q *gorm.DB, p Page) (*int64, error) {
+	if !p.WithTotal {
+		return nil, nil
+	}
+	var total int64
+	if err := q.Session(&gorm.Session{}).Count(&total).Error; err != nil {
+		return nil, err
+	}
+	return &total, nil
+}
+
+func nonNil[T any](items []T) []T {
+	if items == nil {
+		return []T{}
+	}
+	return items
+}
+
+func encode(s string) string {
+	return base64.RawURLEncoding.EncodeToString([]byte(s))
+}
+
+// decode 解析游标："k:<创建时间纳秒>:<id>" 为按创建时间定位，"o:<偏移>" 为按偏移定位
+func (p *Page) decode(cursor string) error {
+	raw, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil {
+		return ErrInvalidCursor
+	}
+	parts := strings.Split(string(raw), ":")
+	switch {
+	case len(parts) == 3 && parts[0] == "k":
+		ns, err := strconv.ParseInt(parts[1], 10, 64)
+		if err != nil {
+			return ErrInvalidCursor
+		}
+		id, err := uuid.Parse(parts[2])
+		if err != nil {
+			return ErrInvalidCursor
+		}
+		p.after = &key{createdAt: time.Unix(0, ns), id: id}
+	case len(parts) == 2 && parts[0] == "o":
+		n, err := strconv.Atoi(parts[1])
+		if err != nil || n < 0 {
+			return ErrInvalidCursor
+		}
+		p.offset = n
+	default:
+		return ErrInvalidCursor
+	}
+	return nil
+}
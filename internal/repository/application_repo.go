@@ -4,6 +4,7 @@ import (
 	"errors"
 	"github.com/google/uuid"
 	"gorm.io/gorm"
+	"onepenny-server/internal/pagination"
 	"onepenny-server/model/dao"
 )
 
@@ -31,8 +32,8 @@ type RejectApplicationInput struct {
 type ApplicationRepo interface {
 	Create(app *dao.Application) error
 	GetByID(id uuid.UUID) (*dao.Application, error)
-	ListByBounty(bountyID uuid.UUID, offset, limit int) ([]*dao.Application, error)
-	ListByUser(userID uuid.UUID, offset, limit int) ([]*dao.Application, error)
+	ListByBounty(bountyID uuid.UUID, page pagination.Page) (*pagination.Result[*dao.Application], error)
+	ListByUser(userID uuid.UUID, page pagination.Page) (*pagination.Result[*dao.Application], error)
 	Update(app *dao.Application) error
 	Delete(id uuid.UUID) error
 	ApproveApplication(input *ApproveApplicationInput) (*dao.Application, error)
@@ -63,28 +64,14 @@ func (r *applicationRepo) GetByID(id uuid.UUID) (*dao.Application, error) {
 	return &app, nil
 }
 
-func (r *applicationRepo) ListByBounty(bountyID uuid.UUID, offset, limit int) ([]*dao.Application, error) {
-	var list []*dao.Application
-	if err := r.db.
-		Where("bounty_id = ?", bountyID).
-		Offset(offset).
-		Limit(limit).
-		Find(&list).Error; err != nil {
-		return nil, err
-	}
-	return list, nil
+func (r *applicationRepo) ListByBounty(bountyID uuid.UUID, page pagination.Page) (*pagination.Result[*dao.Application], error) {
+	q := r.db.Model(&dao.Application{}).Where("bounty_id = ?", bountyID)
+	return pagination.Keyset[*dao.Application](q, page, "applications", pagination.OldestFirst)
 }
 
-func (r *applicationRepo) ListByUser(userID uuid.UUID, offset, limit int) ([]*dao.Application, error) {
-	var list []*dao.Application
-	if err := r.db.
-		Where("user_id = ?", userID).
-		Offset(offset).
-		Limit(limit).
-		Find(&list).Error; err != nil {
-		return nil, err
-	}
-	return list, nil
+func (r *applicationRepo) ListByUser(userID uuid.UUID, page pagination.Page) (*pagination.Result[*dao.Application], error) {
+	q := r.db.Model(&dao.Application{}).Where("user_id = ?", userID)
+	return pagination.Keyset[*dao.Application](q, page, "applications", pagination.NewestFirst)
 }
 
 func (r *applicationRepo) Update(app *dao.Application) error {
@@ -106,20 +93,31 @@ func (r *applicationRepo) ApproveApplication(input *ApproveApplicationInput) (*d
 		return nil, ErrNotApplicationOwner
 	}
 
-	// 事务：1) 更新申请状态 2) 更新悬赏令状态并设置接收者
+	// 事务：1) 悬赏令经状态机进入进行中 2) 设置接收者 3) 更新申请状态
 	err := r.db.Transaction(func(tx *gorm.DB) error {
-		// 1. 更新申请
-		if err := tx.Model(&app).Updates(map[string]interface{}{
-			"status": dao.ApplicationStatusAccepted,
-			"reason": input.Reason,
-		}).Error; err != nil {
+		// 1. 悬赏令 created → in_progress，非法流转直接回滚
+		if err := transitionBounty(tx, &app.Bounty, dao.BountyStatusInProgress, &dao.BountyEvent{
+			ActorID: &input.OwnerID,
+			Type:    dao.BountyEventApplicationApproved,
+			Payload: map[string]interface{}{
+				"application_id": app.ID,
+				"receiver_id":    app.UserID,
+				"reason":         input.Reason,
+			},
+		}); err != nil {
+			return err
+		}
+
+		// 2. 设置接收该申请的用户
+		if err := tx.Model(&dao.Bounty{}).Where("id = ?", app.BountyID).
+			Update("receiver_id", app.UserID).Error; err != nil {
 			return err
 		}
 
-		// 2. 更新悬赏令
-		if err := tx.Model(&dao.Bounty{}).Where("id = ?", app.BountyID).Updates(map[string]interface{}{
-			"status":      dao.BountyStatusInProgress, // 进行中
-			"receiver_id": app.UserID,                 // 接收该申请的用户
+		// 3. 更新申请
+		if err := tx.Model(&app).Updates(map[string]interface{}{
+			"status": dao.ApplicationStatusAccepted,
+			"reason": input.Reason,
 		}).Error; err != nil {
 			return err
 		}
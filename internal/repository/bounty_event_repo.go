@@ -0,0 +1,32 @@
+package repository
+
+import (
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"onepenny-server/internal/pagination"
+	"onepenny-server/model/dao"
+)
+
+// BountyEventRepo 定义悬赏令事件历史的查询接口，写入由各业务事务内部完成
+type BountyEventRepo interface {
+	ListByBounty(bountyID uuid.UUID, page pagination.Page) (*pagination.Result[*dao.BountyEvent], error)
+}
+
+type bountyEventRepo struct {
+	db *gorm.DB
+}
+
+// NewBountyEventRepo 构造函数
+func NewBountyEventRepo(db *gorm.DB) BountyEventRepo {
+	return &bountyEventRepo{db: db}
+}
+
+func (r *bountyEventRepo) ListByBounty(bountyID uuid.UUID, page pagination.Page) (*pagination.Result[*dao.BountyEvent], error) {
+	q := r.db.Model(&dao.BountyEvent{}).Where("bounty_id = ?", bountyID)
+	return pagination.Keyset[*dao.BountyEvent](q, page, "bounty_events", pagination.OldestFirst)
+}
+
+// recordBountyEvent 在给定事务中写入一条事件
+func recordBountyEvent(tx *gorm.DB, ev *dao.BountyEvent) error {
+	return tx.Create(ev).Error
+}
@@ -3,31 +3,162 @@ package repository
 import (
 	"errors"
 	"github.com/google/uuid"
+	"github.com/lib/pq"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"math"
+	"onepenny-server/internal/pagination"
 	"onepenny-server/model/dao"
+	"onepenny-server/util"
+	"strconv"
+	"time"
 )
 
 // ErrBountyNotFound 在查询不到记录时返回
 var ErrBountyNotFound = errors.New("bounty not found")
 
 var (
-	ErrAlreadyAccepted     = errors.New("悬赏令已被接受，无法重复接受")
-	ErrNotBountyReceiver   = errors.New("只有接受方可以发起结算")
-	ErrNotBountyOwner      = errors.New("只有发布方可以确认结算")
-	ErrBountyNotInSettling = errors.New("悬赏令不在进行中，无法发起结算")
-	ErrBountyNotInPending  = errors.New("悬赏令不在待结算状态，无法确认结算")
+	ErrAlreadyAccepted   = errors.New("悬赏令已被接受，无法重复接受")
+	ErrNotBountyReceiver = errors.New("只有接受方可以发起结算")
+	ErrNotBountyOwner    = errors.New("只有发布方可以执行此操作")
+	ErrRewardLocked      = errors.New("悬赏令已被承接，不能再修改赏金或币种")
+
+	ErrMilestoneNotFound     = errors.New("里程碑不存在")
+	ErrMilestonesLocked      = errors.New("悬赏令已被承接，不能再修改里程碑")
+	ErrMilestoneOutOfOrder   = errors.New("里程碑必须按顺序结算")
+	ErrMilestoneNotPending   = errors.New("该里程碑已发起结算或已结算")
+	ErrMilestoneNotRequested = errors.New("该里程碑尚未发起结算")
+	ErrBountyHasMilestones   = errors.New("分阶段悬赏令需按里程碑发起结算")
+	ErrBountyNotInProgress   = errors.New("悬赏令不在进行中，无法结算里程碑")
+
+	ErrCancelNotAllowed     = errors.New("悬赏令已进入结算或已结束，不能取消")
+	ErrInvalidKillFee       = errors.New("违约金必须大于 0 且小于托管余额")
+	ErrNoCancelRequest      = errors.New("悬赏令没有待处理的取消申请")
+	ErrCancelAlreadyPending = errors.New("已有待接收者确认的取消申请")
+	ErrBountyNotClosed      = errors.New("悬赏令尚未结束，请先取消后再删除")
+
+	ErrNotDraft = errors.New("悬赏令已发布，不是草稿")
+)
+
+// CancelBountyInput 发布者取消悬赏令输入
+type CancelBountyInput struct {
+	BountyID uuid.UUID
+	OwnerID  uuid.UUID
+	Reason   string
+	KillFee  float64 // 进行中时支付给接收者的违约金，0 表示改为征求接收者同意
+}
+
+// LocationRemote 远程悬赏令的 Location 取值
+const LocationRemote = "remote"
+
+// 悬赏令列表的排序方式
+const (
+	BountySortNewest   = "newest"   // 发布时间
+	BountySortReward   = "reward"   // 基础赏金
+	BountySortDeadline = "deadline" // 截止时间，无截止时间的排在最后
+	BountySortPopular  = "popular"  // 点赞数与申请数之和
+)
+
+// 分面统计的维度
+const (
+	BountyFacetStatus   = "status"
+	BountyFacetCategory = "category"
+	BountyFacetTags     = "tags"
 )
 
+// facetTagLimit 标签分面最多返回的标签数
+const facetTagLimit = 30
+
+// BountyFilter 悬赏令列表的筛选与排序条件，零值表示不限
+type BountyFilter struct {
+	// Location 为 "remote" 时只列出远程悬赏令，其他非空值按线下地址模糊匹配
+	Location string
+	// Near 非空时只列出 RadiusKm 范围内有坐标的悬赏令；未指定 Sort 时按距离由近到远排序
+	Near     *GeoPoint
+	RadiusKm float64
+
+	Statuses   []dao.BountyStatus
+	Categories []string
+	Tags       []string
+	AllTags    bool // true 时须包含全部 Tags，否则包含任一即可
+	Currency   string
+	Priorities []string
+	MinReward  *float64
+	MaxReward  *float64
+	// 截止时间窗口，任一端非空时不含无截止时间的悬赏令
+	DeadlineFrom *time.Time
+	DeadlineTo   *time.Time
+	PublisherID  *uuid.UUID
+	Unassigned   bool // 只列出尚无接收者的悬赏令
+
+	Sort  string // BountySort*，为空时按发布时间
+	Order string // "asc" 或 "desc"，为空时截止时间升序、其余降序
+}
+
+// BountyFacets 各维度的取值及悬赏令数量
+type BountyFacets struct {
+	Status   map[string]int64
+	Category map[string]int64
+	Tags     map[string]int64
+}
+
+// GeoPoint 经纬度坐标（WGS84）
+type GeoPoint struct {
+	Lat, Lng float64
+}
+
+// BountySearchResult 全文检索的一条结果
+type BountySearchResult struct {
+	Bounty *dao.Bounty
+	Rank   float64
+}
+
 // BountyRepo 定义了对 Bounty 表的基本持久化操作
 type BountyRepo interface {
 	Create(b *dao.Bounty) error
 	GetByID(id uuid.UUID) (*dao.Bounty, error)
-	List(offset, limit int) ([]*dao.Bounty, error)
-	Update(b *dao.Bounty) error
+	// List 按条件列出 viewerID 可见且公开列出的悬赏令（不含草稿与仅凭链接可见的）
+	List(viewerID uuid.UUID, filter *BountyFilter, page pagination.Page) (*pagination.Result[*dao.Bounty], error)
+	// Facets 按状态、分类与标签统计符合条件的悬赏令数量；
+	// 每个维度统计时忽略该维度自身的筛选条件，便于前端展示可切换的其他取值
+	Facets(viewerID uuid.UUID, filter *BountyFilter) (*BountyFacets, error)
+	// Search 按 tsquery 全文检索 viewerID 可见且公开列出的悬赏令，按相关度降序
+	Search(viewerID uuid.UUID, tsquery string, page pagination.Page) (*pagination.Result[*BountySearchResult], error)
+	// CanView 判断 viewerID 能否查看悬赏令，不存在时返回 false
+	CanView(bountyID, viewerID uuid.UUID) (bool, error)
+	// Update 保存悬赏令；milestones 非 nil 时整体替换里程碑，b.AllowedUsers 非 nil 时整体替换白名单，
+	// ev 非空时在同一事务中写入事件
+	Update(b *dao.Bounty, milestones []dao.Milestone, ev *dao.BountyEvent) error
 	Delete(id uuid.UUID) error
 
+	// ListDrafts 列出用户自己的草稿（含定时发布的）
+	ListDrafts(userID uuid.UUID, page pagination.Page) (*pagination.Result[*dao.Bounty], error)
+	// Publish 发布草稿：状态变为已创建，并从发布者钱包托管赏金
+	Publish(id uuid.UUID, actorID *uuid.UUID) (*dao.Bounty, error)
+	// ListDueDrafts 列出定时发布时间早于 now 的草稿
+	ListDueDrafts(now time.Time, limit int) ([]*dao.Bounty, error)
+	// ClearPublishAt 取消草稿的定时发布，用于自动发布失败后等待发布者处理
+	ClearPublishAt(id uuid.UUID) error
+
 	RequestSettlement(bountyID, receiverID uuid.UUID) (*dao.Bounty, error)
 	ConfirmSettlement(bountyID, ownerID uuid.UUID) (*dao.Bounty, error)
+	// AutoConfirmSettlements 将 before 之前发起且仍未确认的结算自动完成，返回被结算的悬赏令
+	AutoConfirmSettlements(before time.Time) ([]*dao.Bounty, error)
+	// Cancel 发布者取消悬赏令。进行中且未支付违约金时只登记取消申请，此时 applicants 为 nil；
+	// 真正取消时返回被自动拒绝或需要通知的全部申请人
+	Cancel(input *CancelBountyInput) (b *dao.Bounty, applicants []uuid.UUID, err error)
+	// RespondCancellation 接收者同意或拒绝发布者的取消申请
+	RespondCancellation(bountyID, receiverID uuid.UUID, accept bool) (b *dao.Bounty, applicants []uuid.UUID, err error)
+
+	// ExpireUnassigned 将截止时间早于 before 且仍无人承接的悬赏令置为过期，返回被处理的悬赏令
+	ExpireUnassigned(before time.Time) ([]*dao.Bounty, error)
+	// FlagOverdue 标记截止时间早于 before 仍在进行中的悬赏令，返回本次新标记的悬赏令
+	FlagOverdue(before time.Time) ([]*dao.Bounty, error)
+
+	// RequestMilestoneSettlement 接收者就单个里程碑发起结算，返回带最新里程碑的悬赏令
+	RequestMilestoneSettlement(bountyID, milestoneID, receiverID uuid.UUID) (*dao.Bounty, error)
+	// ConfirmMilestoneSettlement 发布者确认单个里程碑，从托管中发放该阶段赏金
+	ConfirmMilestoneSettlement(bountyID, milestoneID, ownerID uuid.UUID) (*dao.Bounty, error)
 }
 
 type bountyRepo struct {
@@ -39,13 +170,39 @@ func NewBountyRepo(db *gorm.DB) BountyRepo {
 	return &bountyRepo{db: db}
 }
 
+// Create 新建悬赏令（连同里程碑），写入发布事件，并从发布者钱包锁定赏金
 func (r *bountyRepo) Create(b *dao.Bounty) error {
-	return r.db.Create(b).Error
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		return createBounty(tx, b)
+	})
+}
+
+// createBounty 在事务中写入悬赏令与创建事件并锁定赏金（草稿除外），供周期悬赏生成新一期时复用
+func createBounty(tx *gorm.DB, b *dao.Bounty) error {
+	if err := tx.Create(b).Error; err != nil {
+		return err
+	}
+	if err := recordBountyEvent(tx, &dao.BountyEvent{
+		BountyID: b.ID,
+		ActorID:  &b.UserID,
+		Type:     dao.BountyEventCreated,
+		ToStatus: b.Status,
+	}); err != nil {
+		return err
+	}
+	if b.Status == dao.BountyStatusDraft {
+		return nil
+	}
+	return lockEscrow(tx, b)
 }
 
 func (r *bountyRepo) GetByID(id uuid.UUID) (*dao.Bounty, error) {
 	var b dao.Bounty
-	if err := r.db.First(&b, "id = ?", id).Error; err != nil {
+	if err := r.db.
+		Preload("Milestones", orderMilestones).
+		Preload("Contributions", "status <> ?", dao.ContributionStatusRefunded).
+		Preload("AllowedUsers").
+		First(&b, "id = ?", id).Error; err != nil {
 		if errors.Is(err, gorm.ErrRecordNotFound) {
 			return nil, ErrBountyNotFound
 		}
@@ -54,40 +211,599 @@ func (r *bountyRepo) GetByID(id uuid.UUID) (*dao.Bounty, error) {
 	return &b, nil
 }
 
-func (r *bountyRepo) List(offset, limit int) ([]*dao.Bounty, error) {
+func (r *bountyRepo) List(viewerID uuid.UUID, filter *BountyFilter, page pagination.Page) (*pagination.Result[*dao.Bounty], error) {
+	q := r.db.Model(&dao.Bounty{}).
+		Scopes(visibleTo(r.db, viewerID, false), filterBounties(filter, "")).
+		Where("bounties.status <> ?", dao.BountyStatusDraft)
+	preload := func(db *gorm.DB) *gorm.DB {
+		return db.Preload("Contributions", "status <> ?", dao.ContributionStatusRefunded)
+	}
+
+	// 按发布时间排序时用游标翻页，其余排序方式按偏移翻页
+	if order, ok := byCreatedAt(filter); ok {
+		return pagination.Keyset[*dao.Bounty](q, page, "bounties", order, preload)
+	}
+	return pagination.Offset[*dao.Bounty](q, page, preload, sortBounties(filter))
+}
+
+func (r *bountyRepo) Facets(viewerID uuid.UUID, filter *BountyFilter) (*BountyFacets, error) {
+	type row struct {
+		Value string
+		Count int64
+	}
+	base := func(except string) *gorm.DB {
+		return r.db.Model(&dao.Bounty{}).
+			Scopes(visibleTo(r.db, viewerID, false), filterBounties(filter, except)).
+			Where("bounties.status <> ?", dao.BountyStatusDraft)
+	}
+	toMap := func(rows []row) map[string]int64 {
+		m := make(map[string]int64, len(rows))
+		for _, rw := range rows {
+			m[rw.Value] = rw.Count
+		}
+		return m
+	}
+
+	var status, category, tags []row
+	if err := base(BountyFacetStatus).
+		Select("bounties.status AS value, COUNT(*) AS count").
+		Group("bounties.status").
+		Scan(&status).Error; err != nil {
+		return nil, err
+	}
+	if err := base(BountyFacetCategory).
+		Select("bounties.category AS value, COUNT(*) AS count").
+		Where("bounties.category <> ''").
+		Group("bounties.category").
+		Scan(&category).Error; err != nil {
+		return nil, err
+	}
+	if err := base(BountyFacetTags).
+		Joins("CROSS JOIN LATERAL unnest(bounties.tags) AS tag").
+		Select("tag AS value, COUNT(*) AS count").
+		Group("tag").
+		Order("count DESC, tag ASC").
+		Limit(facetTagLimit).
+		Scan(&tags).Error; err != nil {
+		return nil, err
+	}
+	return &BountyFacets{
+		Status:   toMap(status),
+		Category: toMap(category),
+		Tags:     toMap(tags),
+	}, nil
+}
+
+func (r *bountyRepo) Search(viewerID uuid.UUID, tsquery string, page pagination.Page) (*pagination.Result[*BountySearchResult], error) {
+	// 相关度排序以偏移翻页，不接受其他列表接口的游标
+	skip, err := page.Skip()
+	if err != nil {
+		return nil, err
+	}
+	q := r.db.Model(&dao.Bounty{}).
+		Scopes(visibleTo(r.db, viewerID, false)).
+		Where("bounties.status <> ?", dao.BountyStatusDraft).
+		Where("bounties.search_vector @@ to_tsquery('simple', ?)", tsquery)
+	total, err := pagination.Total(q, page)
+	if err != nil {
+		return nil, err
+	}
+
+	// 先按相关度取出一页 ID，再加载完整记录，避免排序表达式与预加载互相干扰；相关度排序以偏移翻页
+	var hits []struct {
+		ID   uuid.UUID
+		Rank float64
+	}
+	if err := q.
+		Select("bounties.id, ts_rank_cd(bounties.search_vector, to_tsquery('simple', ?)) AS rank", tsquery).
+		Order("rank DESC, bounties.created_at DESC, bounties.id DESC").
+		Offset(skip).
+		Limit(page.Limit()).
+		Scan(&hits).Error; err != nil {
+		return nil, err
+	}
+	res := pagination.Slice(hits, page)
+	res.Total = total
+	hits = res.Items
+	if len(hits) == 0 {
+		return &pagination.Result[*BountySearchResult]{Items: []*BountySearchResult{}, Total: total}, nil
+	}
+
+	ids := make([]uuid.UUID, len(hits))
+	for i, h := range hits {
+		ids[i] = h.ID
+	}
 	var list []*dao.Bounty
 	if err := r.db.
-		Offset(offset).
-		Limit(limit).
+		Preload("Contributions", "status <> ?", dao.ContributionStatusRefunded).
+		Where("id IN ?", ids).
 		Find(&list).Error; err != nil {
 		return nil, err
 	}
-	return list, nil
+	byID := make(map[uuid.UUID]*dao.Bounty, len(list))
+	for _, b := range list {
+		byID[b.ID] = b
+	}
+	results := make([]*BountySearchResult, 0, len(hits))
+	for _, h := range hits {
+		if b, ok := byID[h.ID]; ok {
+			results = append(results, &BountySearchResult{Bounty: b, Rank: h.Rank})
+		}
+	}
+	return &pagination.Result[*BountySearchResult]{Items: results, NextCursor: res.NextCursor, Total: total}, nil
 }
 
-func (r *bountyRepo) Update(b *dao.Bounty) error {
-	return r.db.Save(b).Error
+// filterBounties 将筛选条件转换为查询条件；except 指定的分面维度不参与筛选
+func filterBounties(f *BountyFilter, except string) func(*gorm.DB) *gorm.DB {
+	return func(q *gorm.DB) *gorm.DB {
+		if f == nil {
+			return q
+		}
+		switch {
+		case f.Location == LocationRemote:
+			q = q.Where("bounties.location = ?", LocationRemote)
+		case f.Location != "":
+			q = q.Where("bounties.location <> ? AND bounties.location ILIKE ?", LocationRemote, "%"+f.Location+"%")
+		}
+		if f.Near != nil {
+			q = withinRadius(q, *f.Near, f.RadiusKm)
+		}
+
+		if len(f.Statuses) > 0 && except != BountyFacetStatus {
+			q = q.Where("bounties.status IN ?", f.Statuses)
+		}
+		if len(f.Categories) > 0 && except != BountyFacetCategory {
+			q = q.Where("bounties.category IN ?", f.Categories)
+		}
+		if len(f.Tags) > 0 && except != BountyFacetTags {
+			if f.AllTags {
+				q = q.Where("bounties.tags @> ?", pq.StringArray(f.Tags))
+			} else {
+				q = q.Where("bounties.tags && ?", pq.StringArray(f.Tags))
+			}
+		}
+		if f.Currency != "" {
+			q = q.Where("bounties.currency = ?", f.Currency)
+		}
+		if len(f.Priorities) > 0 {
+			q = q.Where("bounties.priority IN ?", f.Priorities)
+		}
+		if f.MinReward != nil {
+			q = q.Where("bounties.reward >= ?", *f.MinReward)
+		}
+		if f.MaxReward != nil {
+			q = q.Where("bounties.reward <= ?", *f.MaxReward)
+		}
+		if f.DeadlineFrom != nil {
+			q = q.Where("bounties.deadline >= ?", *f.DeadlineFrom)
+		}
+		if f.DeadlineTo != nil {
+			q = q.Where("bounties.deadline <= ?", *f.DeadlineTo)
+		}
+		if f.PublisherID != nil {
+			q = q.Where("bounties.user_id = ?", *f.PublisherID)
+		}
+		if f.Unassigned {
+			q = q.Where("bounties.receiver_id IS NULL")
+		}
+		return q
+	}
+}
+
+// byCreatedAt 判断列表是否按发布时间排序，是则返回方向
+func byCreatedAt(f *BountyFilter) (pagination.Order, bool) {
+	if f == nil {
+		return pagination.NewestFirst, true
+	}
+	if f.Sort != BountySortNewest && (f.Sort != "" || f.Near != nil) {
+		return 0, false
+	}
+	if f.Order == "asc" {
+		return pagination.OldestFirst, true
+	}
+	return pagination.NewestFirst, true
+}
+
+// sortBounties 按排序方式追加 ORDER BY，最后以 ID 兜底保证分页稳定
+func sortBounties(f *BountyFilter) func(*gorm.DB) *gorm.DB {
+	return func(q *gorm.DB) *gorm.DB {
+		if f == nil {
+			f = &BountyFilter{}
+		}
+		dir := " DESC"
+		if f.Order == "asc" || (f.Order == "" && f.Sort == BountySortDeadline) {
+			dir = " ASC"
+		}
+		switch f.Sort {
+		case BountySortReward:
+			q = q.Order("bounties.reward" + dir)
+		case BountySortDeadline:
+			q = q.Order("bounties.deadline" + dir + " NULLS LAST")
+		case BountySortPopular:
+			q = q.Order("(SELECT COUNT(*) FROM likes WHERE likes.likeable_id = bounties.id AND likes.likeable_type = 'bounty' AND likes.deleted_at IS NULL)" +
+				" + (SELECT COUNT(*) FROM applications WHERE applications.bounty_id = bounties.id AND applications.deleted_at IS NULL)" + dir)
+		case "":
+			if f.Near != nil {
+				// 表达式排序会被后续 Order 覆盖，ID 兜底需写在同一子句中
+				return q.Clauses(clause.OrderBy{Expression: clause.Expr{
+					SQL:  "?, bounties.id",
+					Vars: []interface{}{distanceKm(*f.Near)},
+				}})
+			}
+			fallthrough
+		default:
+			q = q.Order("bounties.created_at" + dir)
+		}
+		return q.Order("bounties.id" + dir)
+	}
+}
+
+// distanceKm 按 haversine 公式计算悬赏令坐标到 p 的距离（千米）
+func distanceKm(p GeoPoint) clause.Expr {
+	return clause.Expr{
+		SQL: "? * 2 * ASIN(LEAST(1, SQRT(POWER(SIN(RADIANS(bounties.latitude - ?) / 2), 2) + " +
+			"COS(RADIANS(?)) * COS(RADIANS(bounties.latitude)) * POWER(SIN(RADIANS(bounties.longitude - ?) / 2), 2))))",
+		Vars: []interface{}{util.EarthRadiusKm, p.Lat, p.Lat, p.Lng},
+	}
+}
+
+// withinRadius 先按纬度包围盒粗筛以利用索引，再用 haversine 公式精确过滤
+func withinRadius(q *gorm.DB, p GeoPoint, radiusKm float64) *gorm.DB {
+	dLat := radiusKm / (util.EarthRadiusKm * math.Pi / 180)
+	return q.
+		Where("bounties.latitude BETWEEN ? AND ?", p.Lat-dLat, p.Lat+dLat).
+		Where("bounties.longitude IS NOT NULL").
+		Where("? <= ?", distanceKm(p), radiusKm)
+}
+
+func (r *bountyRepo) CanView(bountyID, viewerID uuid.UUID) (bool, error) {
+	var count int64
+	err := r.db.Model(&dao.Bounty{}).
+		Scopes(visibleTo(r.db, viewerID, true)).
+		Where("id = ?", bountyID).
+		Count(&count).Error
+	return count > 0, err
+}
+
+// visibleTo 按可见范围过滤悬赏令：发布者与接收者始终可见，草稿仅发布者可见；
+// withUnlisted 为 false 时不含仅凭链接可见的悬赏令，用于列表
+func visibleTo(db *gorm.DB, viewerID uuid.UUID, withUnlisted bool) func(*gorm.DB) *gorm.DB {
+	return func(q *gorm.DB) *gorm.DB {
+		open := []dao.BountyVisibility{dao.BountyVisibilityPublic}
+		if withUnlisted {
+			open = append(open, dao.BountyVisibilityUnlisted)
+		}
+		allowed := db.Model(&dao.BountyAllowedUser{}).
+			Select("bounty_id").
+			Where("user_id = ?", viewerID)
+		return q.
+			Where("bounties.status <> ? OR bounties.user_id = ?", dao.BountyStatusDraft, viewerID).
+			Where(db.
+				Where("bounties.user_id = ? OR bounties.receiver_id = ?", viewerID, viewerID).
+				Or("bounties.visibility IN ?", open).
+				Or("bounties.visibility = ? AND bounties.team_id IN (?)", dao.BountyVisibilityTeam, userTeamIDs(db, viewerID)).
+				Or("bounties.visibility = ? AND bounties.id IN (?)", dao.BountyVisibilityInviteOnly, allowed))
+	}
 }
 
+func (r *bountyRepo) Update(b *dao.Bounty, milestones []dao.Milestone, ev *dao.BountyEvent) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		var old dao.Bounty
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+			First(&old, "id = ?", b.ID).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return ErrBountyNotFound
+			}
+			return err
+		}
+		// 状态只能经状态机修改，这里以库中最新值为准
+		b.Status = old.Status
+
+		// 赏金只能在无人承接前调整：先退回原基础赏金，再按新金额重新锁定，众筹追加不受影响；
+		// 草稿尚未托管，可随意修改
+		if (old.Reward != b.Reward || old.Currency != b.Currency) && old.Status != dao.BountyStatusDraft {
+			if old.Status != dao.BountyStatusCreated {
+				return ErrRewardLocked
+			}
+			if err := refundBase(tx, &old); err != nil {
+				return err
+			}
+			if err := lockEscrow(tx, b); err != nil {
+				return err
+			}
+		}
+
+		// 里程碑同样只能在无人承接前整体替换
+		if milestones != nil {
+			if old.Status != dao.BountyStatusCreated && old.Status != dao.BountyStatusDraft {
+				return ErrMilestonesLocked
+			}
+			if err := tx.Unscoped().Where("bounty_id = ?", b.ID).Delete(&dao.Milestone{}).Error; err != nil {
+				return err
+			}
+			for i := range milestones {
+				milestones[i].BountyID = b.ID
+				milestones[i].Seq = i + 1
+			}
+			if len(milestones) > 0 {
+				if err := tx.Create(&milestones).Error; err != nil {
+					return err
+				}
+			}
+			b.Milestones = milestones
+		}
+
+		// 白名单整体替换
+		if b.AllowedUsers != nil {
+			if err := tx.Unscoped().Where("bounty_id = ?", b.ID).Delete(&dao.BountyAllowedUser{}).Error; err != nil {
+				return err
+			}
+			for i := range b.AllowedUsers {
+				b.AllowedUsers[i].BountyID = b.ID
+			}
+			if len(b.AllowedUsers) > 0 {
+				if err := tx.Create(&b.AllowedUsers).Error; err != nil {
+					return err
+				}
+			}
+		}
+
+		if err := tx.Omit(clause.Associations, "WatcherCount", "ViewCount").Save(b).Error; err != nil {
+			return err
+		}
+		if ev == nil {
+			return nil
+		}
+		ev.BountyID = b.ID
+		ev.FromStatus = b.Status
+		ev.ToStatus = b.Status
+		return recordBountyEvent(tx, ev)
+	})
+}
+
+// Delete 软删除悬赏令；托管资金必须已经结清，因此只允许删除草稿或已结束的悬赏令
 func (r *bountyRepo) Delete(id uuid.UUID) error {
+	var b dao.Bounty
+	if err := r.db.First(&b, "id = ?", id).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return ErrBountyNotFound
+		}
+		return err
+	}
+	if !b.Status.IsTerminal() && b.Status != dao.BountyStatusDraft {
+		return ErrBountyNotClosed
+	}
 	return r.db.Delete(&dao.Bounty{}, "id = ?", id).Error
 }
 
-// RequestSettlement 接收者发起结算请求
-func (r *bountyRepo) RequestSettlement(bountyID, receiverID uuid.UUID) (*dao.Bounty, error) {
-	var b dao.Bounty
-	if err := r.db.First(&b, "id = ?", bountyID).Error; err != nil {
+func (r *bountyRepo) ListDrafts(userID uuid.UUID, page pagination.Page) (*pagination.Result[*dao.Bounty], error) {
+	q := r.db.Model(&dao.Bounty{}).Where("user_id = ? AND status = ?", userID, dao.BountyStatusDraft)
+	// 最近编辑的在前，以偏移翻页
+	return pagination.Offset[*dao.Bounty](q, page, func(db *gorm.DB) *gorm.DB {
+		return db.Order("updated_at DESC, id DESC")
+	})
+}
+
+func (r *bountyRepo) Publish(id uuid.UUID, actorID *uuid.UUID) (*dao.Bounty, error) {
+	var b *dao.Bounty
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		var err error
+		if b, err = lockBounty(tx, id); err != nil {
+			return err
+		}
+		if b.Status != dao.BountyStatusDraft {
+			return ErrNotDraft
+		}
+		scheduled := b.PublishAt
+		now := time.Now()
+		b.PublishAt = &now
+		if err := tx.Model(b).Update("publish_at", now).Error; err != nil {
+			return err
+		}
+		// 草稿 → 已创建，托管赏金随之锁定
+		return transitionBounty(tx, b, dao.BountyStatusCreated, &dao.BountyEvent{
+			ActorID: actorID,
+			Type:    dao.BountyEventPublished,
+			Payload: map[string]interface{}{"scheduled_at": scheduled},
+		})
+	})
+	if err != nil {
 		return nil, err
 	}
-	// 只能发布者接受后、进行中才能申请结算
-	if b.Status != dao.BountyStatusInProgress {
-		return nil, ErrBountyNotInSettling
+	return r.GetByID(id)
+}
+
+func (r *bountyRepo) ListDueDrafts(now time.Time, limit int) ([]*dao.Bounty, error) {
+	var list []*dao.Bounty
+	if err := r.db.
+		Preload("Milestones", orderMilestones).
+		Where("status = ? AND publish_at <= ?", dao.BountyStatusDraft, now).
+		Order("publish_at ASC").
+		Limit(limit).
+		Find(&list).Error; err != nil {
+		return nil, err
 	}
-	if b.ReceiverID == nil || *b.ReceiverID != receiverID {
-		return nil, ErrNotBountyReceiver
+	return list, nil
+}
+
+func (r *bountyRepo) ClearPublishAt(id uuid.UUID) error {
+	return r.db.Model(&dao.Bounty{}).
+		Where("id = ? AND status = ?", id, dao.BountyStatusDraft).
+		Update("publish_at", nil).Error
+}
+
+// Cancel 发布者取消悬赏令：
+//   - 已创建：直接取消，全额退回
+//   - 进行中：支付违约金后立即取消，否则登记取消申请等待接收者同意
+//   - 待结算及之后：不允许取消
+func (r *bountyRepo) Cancel(input *CancelBountyInput) (*dao.Bounty, []uuid.UUID, error) {
+	var applicants []uuid.UUID
+	b := &dao.Bounty{}
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		var err error
+		if b, err = lockBounty(tx, input.BountyID); err != nil {
+			return err
+		}
+		if b.UserID != input.OwnerID {
+			return ErrNotBountyOwner
+		}
+
+		switch b.Status {
+		case dao.BountyStatusCreated:
+			applicants, err = cancelBounty(tx, b, input.OwnerID, input.Reason, 0)
+			return err
+		case dao.BountyStatusInProgress:
+			if input.KillFee > 0 {
+				applicants, err = cancelBounty(tx, b, input.OwnerID, input.Reason, input.KillFee)
+				return err
+			}
+			if b.CancelRequestedAt != nil {
+				return ErrCancelAlreadyPending
+			}
+			now := time.Now()
+			b.CancelRequestedAt = &now
+			b.CancelReason = input.Reason
+			if err := tx.Model(b).Updates(map[string]interface{}{
+				"cancel_requested_at": now,
+				"cancel_reason":       input.Reason,
+			}).Error; err != nil {
+				return err
+			}
+			return recordBountyEvent(tx, &dao.BountyEvent{
+				BountyID:   b.ID,
+				ActorID:    &input.OwnerID,
+				Type:       dao.BountyEventCancelRequested,
+				FromStatus: b.Status,
+				ToStatus:   b.Status,
+				Payload:    map[string]interface{}{"reason": input.Reason},
+			})
+		default:
+			return ErrCancelNotAllowed
+		}
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+	return b, applicants, nil
+}
+
+// RespondCancellation 接收者处理取消申请：同意则全额退款取消，拒绝则清除申请继续进行
+func (r *bountyRepo) RespondCancellation(bountyID, receiverID uuid.UUID, accept bool) (*dao.Bounty, []uuid.UUID, error) {
+	var applicants []uuid.UUID
+	b := &dao.Bounty{}
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		var err error
+		if b, err = lockBounty(tx, bountyID); err != nil {
+			return err
+		}
+		if b.ReceiverID == nil || *b.ReceiverID != receiverID {
+			return ErrNotBountyReceiver
+		}
+		if b.CancelRequestedAt == nil || b.Status != dao.BountyStatusInProgress {
+			return ErrNoCancelRequest
+		}
+		if accept {
+			applicants, err = cancelBounty(tx, b, receiverID, b.CancelReason, 0)
+			return err
+		}
+
+		reason := b.CancelReason
+		b.CancelRequestedAt = nil
+		b.CancelReason = ""
+		if err := tx.Model(b).Updates(map[string]interface{}{
+			"cancel_requested_at": nil,
+			"cancel_reason":       "",
+		}).Error; err != nil {
+			return err
+		}
+		return recordBountyEvent(tx, &dao.BountyEvent{
+			BountyID:   b.ID,
+			ActorID:    &receiverID,
+			Type:       dao.BountyEventCancelDeclined,
+			FromStatus: b.Status,
+			ToStatus:   b.Status,
+			Payload:    map[string]interface{}{"reason": reason},
+		})
+	})
+	if err != nil {
+		return nil, nil, err
 	}
-	// 更新为待结算
-	if err := r.db.Model(&b).Update("status", dao.BountyStatusPendingSettlement).Error; err != nil {
+	return b, applicants, nil
+}
+
+// cancelBounty 在事务中取消悬赏令：先向接收者支付违约金（如有），再退回剩余托管，
+// 最后自动拒绝仍在等待的申请，返回全部申请人以便通知
+func cancelBounty(tx *gorm.DB, b *dao.Bounty, actorID uuid.UUID, reason string, killFee float64) ([]uuid.UUID, error) {
+	if killFee > 0 {
+		if err := splitEscrow(tx, b, killFee); err != nil {
+			if errors.Is(err, ErrInvalidPayout) {
+				return nil, ErrInvalidKillFee
+			}
+			return nil, err
+		}
+	}
+	if err := transitionBounty(tx, b, dao.BountyStatusCancelled, &dao.BountyEvent{
+		ActorID: &actorID,
+		Type:    dao.BountyEventCancelled,
+		Payload: map[string]interface{}{"reason": reason, "kill_fee": killFee},
+	}); err != nil {
+		return nil, err
+	}
+	if err := tx.Model(b).Update("cancel_requested_at", nil).Error; err != nil {
+		return nil, err
+	}
+
+	var applicants []uuid.UUID
+	if err := tx.Model(&dao.Application{}).
+		Where("bounty_id = ?", b.ID).
+		Distinct("user_id").
+		Pluck("user_id", &applicants).Error; err != nil {
+		return nil, err
+	}
+	rejectReason := "悬赏令已取消：" + reason
+	if err := tx.Model(&dao.Application{}).
+		Where("bounty_id = ? AND status = ?", b.ID, dao.ApplicationStatusPending).
+		Updates(map[string]interface{}{
+			"status": dao.ApplicationStatusRejected,
+			"reason": rejectReason,
+		}).Error; err != nil {
+		return nil, err
+	}
+	return applicants, nil
+}
+
+// RequestSettlement 接收者发起结算请求
+func (r *bountyRepo) RequestSettlement(bountyID, receiverID uuid.UUID) (*dao.Bounty, error) {
+	var b dao.Bounty
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.First(&b, "id = ?", bountyID).Error; err != nil {
+			return err
+		}
+		if b.ReceiverID == nil || *b.ReceiverID != receiverID {
+			return ErrNotBountyReceiver
+		}
+		var n int64
+		if err := tx.Model(&dao.Milestone{}).Where("bounty_id = ?", b.ID).Count(&n).Error; err != nil {
+			return err
+		}
+		if n > 0 {
+			return ErrBountyHasMilestones
+		}
+		// 进行中 → 待结算
+		if err := transitionBounty(tx, &b, dao.BountyStatusPendingSettlement, &dao.BountyEvent{
+			ActorID: &receiverID,
+			Type:    dao.BountyEventSettlementRequested,
+		}); err != nil {
+			return err
+		}
+		now := time.Now()
+		b.SettlementRequestedAt = &now
+		return tx.Model(&b).Update("settlement_requested_at", now).Error
+	})
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrBountyNotFound
+		}
 		return nil, err
 	}
 	return &b, nil
@@ -96,18 +812,326 @@ func (r *bountyRepo) RequestSettlement(bountyID, receiverID uuid.UUID) (*dao.Bou
 // ConfirmSettlement 发布者确认结算
 func (r *bountyRepo) ConfirmSettlement(bountyID, ownerID uuid.UUID) (*dao.Bounty, error) {
 	var b dao.Bounty
-	if err := r.db.First(&b, "id = ?", bountyID).Error; err != nil {
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.First(&b, "id = ?", bountyID).Error; err != nil {
+			return err
+		}
+		if b.UserID != ownerID {
+			return ErrNotBountyOwner
+		}
+		// 待结算 → 已结算
+		return transitionBounty(tx, &b, dao.BountyStatusSettled, &dao.BountyEvent{
+			ActorID: &ownerID,
+			Type:    dao.BountyEventSettlementConfirmed,
+			Payload: map[string]interface{}{"receiver_id": b.ReceiverID},
+		})
+	})
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrBountyNotFound
+		}
+		return nil, err
+	}
+	return &b, nil
+}
+
+func (r *bountyRepo) AutoConfirmSettlements(before time.Time) ([]*dao.Bounty, error) {
+	var ids []uuid.UUID
+	if err := r.db.Model(&dao.Bounty{}).
+		Where("status = ? AND settlement_requested_at < ?", dao.BountyStatusPendingSettlement, before).
+		Pluck("id", &ids).Error; err != nil {
+		return nil, err
+	}
+
+	var done []*dao.Bounty
+	for _, id := range ids {
+		var b dao.Bounty
+		err := r.db.Transaction(func(tx *gorm.DB) error {
+			if err := tx.First(&b, "id = ?", id).Error; err != nil {
+				return err
+			}
+			return transitionBounty(tx, &b, dao.BountyStatusSettled, &dao.BountyEvent{
+				Type:    dao.BountyEventSettlementAutoDone,
+				Payload: map[string]interface{}{"settlement_requested_at": b.SettlementRequestedAt},
+			})
+		})
+		// 期间状态被并发修改（如发起了争议）时跳过
+		if errors.Is(err, dao.ErrIllegalBountyTransition) {
+			continue
+		}
+		if err != nil {
+			return done, err
+		}
+		done = append(done, &b)
+	}
+	return done, nil
+}
+
+func (r *bountyRepo) ExpireUnassigned(before time.Time) ([]*dao.Bounty, error) {
+	var ids []uuid.UUID
+	if err := r.db.Model(&dao.Bounty{}).
+		Where("status = ? AND deadline < ?", dao.BountyStatusCreated, before).
+		Pluck("id", &ids).Error; err != nil {
+		return nil, err
+	}
+
+	var done []*dao.Bounty
+	for _, id := range ids {
+		var b dao.Bounty
+		err := r.db.Transaction(func(tx *gorm.DB) error {
+			if err := tx.First(&b, "id = ?", id).Error; err != nil {
+				return err
+			}
+			// 已创建 → 已过期，托管赏金随之退回发布者
+			return transitionBounty(tx, &b, dao.BountyStatusExpired, &dao.BountyEvent{
+				Type:    dao.BountyEventExpired,
+				Payload: map[string]interface{}{"deadline": b.Deadline},
+			})
+		})
+		// 期间已被承接或取消时跳过
+		if errors.Is(err, dao.ErrIllegalBountyTransition) {
+			continue
+		}
+		if err != nil {
+			return done, err
+		}
+		done = append(done, &b)
+	}
+	return done, nil
+}
+
+func (r *bountyRepo) FlagOverdue(before time.Time) ([]*dao.Bounty, error) {
+	var list []*dao.Bounty
+	if err := r.db.
+		Where("status = ? AND deadline < ? AND overdue_at IS NULL", dao.BountyStatusInProgress, before).
+		Find(&list).Error; err != nil {
+		return nil, err
+	}
+
+	var done []*dao.Bounty
+	for _, b := range list {
+		err := r.db.Transaction(func(tx *gorm.DB) error {
+			res := tx.Model(&dao.Bounty{}).
+				Where("id = ? AND status = ? AND overdue_at IS NULL", b.ID, dao.BountyStatusInProgress).
+				Update("overdue_at", before)
+			if res.Error != nil || res.RowsAffected == 0 {
+				return res.Error
+			}
+			b.OverdueAt = &before
+			return recordBountyEvent(tx, &dao.BountyEvent{
+				BountyID:   b.ID,
+				Type:       dao.BountyEventOverdue,
+				FromStatus: b.Status,
+				ToStatus:   b.Status,
+				Payload:    map[string]interface{}{"deadline": b.Deadline},
+			})
+		})
+		if err != nil {
+			return done, err
+		}
+		if b.OverdueAt != nil {
+			done = append(done, b)
+		}
+	}
+	return done, nil
+}
+
+// RequestMilestoneSettlement 接收者就某个里程碑发起结算。
+// 若这是最后一个未结算的里程碑，悬赏令同时进入待结算，沿用整体结算的确认与超时自动确认流程。
+func (r *bountyRepo) RequestMilestoneSettlement(bountyID, milestoneID, receiverID uuid.UUID) (*dao.Bounty, error) {
+	var b dao.Bounty
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		m, last, err := lockMilestone(tx, &b, bountyID, milestoneID)
+		if err != nil {
+			return err
+		}
+		if b.ReceiverID == nil || *b.ReceiverID != receiverID {
+			return ErrNotBountyReceiver
+		}
+		if m.Status != dao.MilestoneStatusPending {
+			return ErrMilestoneNotPending
+		}
+
+		now := time.Now()
+		if err := tx.Model(m).Updates(map[string]interface{}{
+			"status":                  dao.MilestoneStatusPendingSettlement,
+			"settlement_requested_at": now,
+		}).Error; err != nil {
+			return err
+		}
+		ev := &dao.BountyEvent{
+			ActorID: &receiverID,
+			Type:    dao.BountyEventMilestoneRequested,
+			Payload: map[string]interface{}{"milestone_id": m.ID, "seq": m.Seq, "amount": m.Amount},
+		}
+		if !last {
+			ev.BountyID = b.ID
+			ev.FromStatus = b.Status
+			ev.ToStatus = b.Status
+			return recordBountyEvent(tx, ev)
+		}
+		// 最后一个里程碑：进行中 → 待结算
+		if err := transitionBounty(tx, &b, dao.BountyStatusPendingSettlement, ev); err != nil {
+			return err
+		}
+		b.SettlementRequestedAt = &now
+		return tx.Model(&b).Update("settlement_requested_at", now).Error
+	})
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrBountyNotFound
+		}
 		return nil, err
 	}
-	if b.Status != dao.BountyStatusPendingSettlement {
-		return nil, ErrBountyNotInPending
+	if err := r.db.Scopes(orderMilestones).Where("bounty_id = ?", b.ID).Find(&b.Milestones).Error; err != nil {
+		return nil, err
 	}
-	if b.UserID != ownerID {
-		return nil, ErrNotBountyOwner
+	return &b, nil
+}
+
+// ConfirmMilestoneSettlement 发布者确认某个里程碑，从托管中发放该阶段赏金。
+// 最后一个里程碑的确认等同于整体结算：悬赏令进入已结算，托管余额全部发放。
+func (r *bountyRepo) ConfirmMilestoneSettlement(bountyID, milestoneID, ownerID uuid.UUID) (*dao.Bounty, error) {
+	var b dao.Bounty
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		m, last, err := lockMilestone(tx, &b, bountyID, milestoneID)
+		if err != nil {
+			return err
+		}
+		if b.UserID != ownerID {
+			return ErrNotBountyOwner
+		}
+		if m.Status != dao.MilestoneStatusPendingSettlement {
+			return ErrMilestoneNotRequested
+		}
+
+		ev := &dao.BountyEvent{
+			ActorID: &ownerID,
+			Type:    dao.BountyEventMilestoneSettled,
+			Payload: map[string]interface{}{"milestone_id": m.ID, "seq": m.Seq, "amount": m.Amount, "receiver_id": b.ReceiverID},
+		}
+		if last {
+			// 待结算 → 已结算，里程碑随之标记为已结算
+			return transitionBounty(tx, &b, dao.BountyStatusSettled, ev)
+		}
+
+		escrow, err := lockWallet(tx, dao.WalletOwnerEscrow, b.ID, b.Currency)
+		if err != nil {
+			return err
+		}
+		if err := releaseEscrowPart(tx, &b, escrow, m.Amount, "milestone "+strconv.Itoa(m.Seq)); err != nil {
+			return err
+		}
+		if err := tx.Model(m).Updates(map[string]interface{}{
+			"status":     dao.MilestoneStatusSettled,
+			"settled_at": time.Now(),
+		}).Error; err != nil {
+			return err
+		}
+		ev.BountyID = b.ID
+		ev.FromStatus = b.Status
+		ev.ToStatus = b.Status
+		return recordBountyEvent(tx, ev)
+	})
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrBountyNotFound
+		}
+		return nil, err
 	}
-	// 更新为已结算
-	if err := r.db.Model(&b).Update("status", dao.BountyStatusSettled).Error; err != nil {
+	if err := r.db.Scopes(orderMilestones).Where("bounty_id = ?", b.ID).Find(&b.Milestones).Error; err != nil {
 		return nil, err
 	}
 	return &b, nil
 }
+
+// lockMilestone 锁定悬赏令并取出指定里程碑，校验悬赏令处于进行中且前序里程碑均已结算；
+// last 表示该里程碑是否为最后一个未结算的里程碑
+func lockMilestone(tx *gorm.DB, b *dao.Bounty, bountyID, milestoneID uuid.UUID) (m *dao.Milestone, last bool, err error) {
+	if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).First(b, "id = ?", bountyID).Error; err != nil {
+		return nil, false, err
+	}
+	var list []dao.Milestone
+	if err := tx.Scopes(orderMilestones).Where("bounty_id = ?", bountyID).Find(&list).Error; err != nil {
+		return nil, false, err
+	}
+
+	remaining := 0
+	for i := range list {
+		if list[i].ID == milestoneID {
+			m = &list[i]
+		}
+		if list[i].Status == dao.MilestoneStatusSettled {
+			continue
+		}
+		remaining++
+		if m == nil {
+			// 在目标之前存在未结算的里程碑
+			return nil, false, ErrMilestoneOutOfOrder
+		}
+	}
+	if m == nil {
+		return nil, false, ErrMilestoneNotFound
+	}
+
+	// 最后一个里程碑确认时悬赏令已处于待结算，其余情况必须在进行中
+	switch {
+	case b.Status == dao.BountyStatusInProgress:
+	case b.Status == dao.BountyStatusPendingSettlement && remaining == 1:
+	default:
+		return nil, false, ErrBountyNotInProgress
+	}
+	return m, remaining == 1, nil
+}
+
+func orderMilestones(db *gorm.DB) *gorm.DB {
+	return db.Order("seq ASC")
+}
+
+// transitionBounty 在给定事务中执行一次状态流转，并写入对应事件 ev。
+// 更新语句带上旧状态作为条件，若期间状态已被并发修改，则同样视为非法流转。
+func transitionBounty(tx *gorm.DB, b *dao.Bounty, to dao.BountyStatus, ev *dao.BountyEvent) error {
+	from := b.Status
+	if err := b.TransitionTo(to); err != nil {
+		return err
+	}
+	res := tx.Model(&dao.Bounty{}).
+		Where("id = ? AND status = ?", b.ID, from).
+		Update("status", to)
+	if res.Error != nil {
+		return res.Error
+	}
+	if res.RowsAffected == 0 {
+		return &dao.BountyTransitionError{From: from, To: to}
+	}
+
+	ev.BountyID = b.ID
+	ev.FromStatus = from
+	ev.ToStatus = to
+	if err := recordBountyEvent(tx, ev); err != nil {
+		return err
+	}
+
+	// 资金随状态流转：发布时锁定，结算发放给接收者，取消或过期退回发布者
+	switch to {
+	case dao.BountyStatusCreated:
+		return lockEscrow(tx, b)
+	case dao.BountyStatusSettled:
+		// 未单独确认的里程碑随整体结算一并完成
+		if err := tx.Model(&dao.Milestone{}).
+			Where("bounty_id = ? AND status <> ?", b.ID, dao.MilestoneStatusSettled).
+			Updates(map[string]interface{}{"status": dao.MilestoneStatusSettled, "settled_at": time.Now()}).Error; err != nil {
+			return err
+		}
+		// 未审核的交付物（如超时自动结算、仲裁全额发放）视为通过
+		if err := tx.Model(&dao.Submission{}).
+			Where("bounty_id = ? AND status = ?", b.ID, dao.SubmissionStatusPending).
+			Updates(map[string]interface{}{"status": dao.SubmissionStatusAccepted, "reviewed_at": time.Now()}).Error; err != nil {
+			return err
+		}
+		return releaseEscrow(tx, b)
+	case dao.BountyStatusCancelled, dao.BountyStatusExpired:
+		return refundEscrow(tx, b)
+	}
+	return nil
+}
@@ -0,0 +1,143 @@
+package repository
+
+import (
+	"errors"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"onepenny-server/internal/pagination"
+	"onepenny-server/model/dao"
+	"time"
+)
+
+var (
+	// ErrSeriesNotFound 找不到对应的周期悬赏
+	ErrSeriesNotFound = errors.New("bounty series not found")
+	// ErrSeriesNotDue 周期悬赏已被暂停、修改或由其他实例生成，本次跳过
+	ErrSeriesNotDue = errors.New("bounty series is not due")
+)
+
+// SpawnOccurrenceInput 生成一期悬赏令所需的数据，由 Service 层根据计划计算
+type SpawnOccurrenceInput struct {
+	SeriesID  uuid.UUID
+	RunAt     time.Time   // 本期的计划时间，须与系列当前的 NextRunAt 一致
+	Bounty    *dao.Bounty // 本期悬赏令，SeriesID 与 Occurrence 由 Repo 填写
+	NextRunAt *time.Time  // 下一期计划时间，为空表示系列结束
+}
+
+// BountySeriesRepo 定义周期悬赏的持久化接口
+type BountySeriesRepo interface {
+	Create(s *dao.BountySeries) error
+	GetByID(id uuid.UUID) (*dao.BountySeries, error)
+	ListByUser(userID uuid.UUID, page pagination.Page) (*pagination.Result[*dao.BountySeries], error)
+	// Update 保存系列的定义与计划，已生成期数只由 Spawn 维护
+	Update(s *dao.BountySeries) error
+	// ListDue 列出计划时间早于 now 的进行中系列
+	ListDue(now time.Time, limit int) ([]*dao.BountySeries, error)
+	// Spawn 生成一期悬赏令并推进计划。赏金托管失败时不生成悬赏令，但仍推进计划并记录原因，
+	// 此时返回托管失败的错误
+	Spawn(input *SpawnOccurrenceInput) (*dao.Bounty, error)
+	// ListOccurrences 按期数倒序列出系列已生成的悬赏令
+	ListOccurrences(seriesID uuid.UUID, page pagination.Page) (*pagination.Result[*dao.Bounty], error)
+}
+
+type bountySeriesRepo struct {
+	db *gorm.DB
+}
+
+// NewBountySeriesRepo 构造函数
+func NewBountySeriesRepo(db *gorm.DB) BountySeriesRepo {
+	return &bountySeriesRepo{db: db}
+}
+
+func (r *bountySeriesRepo) Create(s *dao.BountySeries) error {
+	return r.db.Create(s).Error
+}
+
+func (r *bountySeriesRepo) GetByID(id uuid.UUID) (*dao.BountySeries, error) {
+	var s dao.BountySeries
+	if err := r.db.First(&s, "id = ?", id).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrSeriesNotFound
+		}
+		return nil, err
+	}
+	return &s, nil
+}
+
+func (r *bountySeriesRepo) ListByUser(userID uuid.UUID, page pagination.Page) (*pagination.Result[*dao.BountySeries], error) {
+	q := r.db.Model(&dao.BountySeries{}).Where("user_id = ?", userID)
+	return pagination.Keyset[*dao.BountySeries](q, page, "bounty_series", pagination.NewestFirst)
+}
+
+func (r *bountySeriesRepo) Update(s *dao.BountySeries) error {
+	return r.db.Omit("OccurrenceCount").Save(s).Error
+}
+
+func (r *bountySeriesRepo) ListDue(now time.Time, limit int) ([]*dao.BountySeries, error) {
+	var list []*dao.BountySeries
+	if err := r.db.
+		Where("status = ? AND next_run_at <= ?", dao.BountySeriesStatusActive, now).
+		Order("next_run_at ASC").
+		Limit(limit).
+		Find(&list).Error; err != nil {
+		return nil, err
+	}
+	return list, nil
+}
+
+func (r *bountySeriesRepo) Spawn(input *SpawnOccurrenceInput) (*dao.Bounty, error) {
+	var spawned *dao.Bounty
+	var spawnErr error
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		var s dao.BountySeries
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+			First(&s, "id = ?", input.SeriesID).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return ErrSeriesNotFound
+			}
+			return err
+		}
+		if s.Status != dao.BountySeriesStatusActive || s.NextRunAt == nil || !s.NextRunAt.Equal(input.RunAt) {
+			return ErrSeriesNotDue
+		}
+
+		b := input.Bounty
+		b.SeriesID = &s.ID
+		b.Occurrence = s.OccurrenceCount + 1
+		// 在保存点中生成，托管失败只回滚本期悬赏令
+		spawnErr = tx.Transaction(func(tx *gorm.DB) error {
+			return createBounty(tx, b)
+		})
+		if spawnErr == nil {
+			spawned = b
+			s.OccurrenceCount++
+			s.LastError = ""
+		} else {
+			s.LastError = spawnErr.Error()
+		}
+
+		s.NextRunAt = input.NextRunAt
+		if s.MaxOccurrences > 0 && s.OccurrenceCount >= s.MaxOccurrences {
+			s.NextRunAt = nil
+		}
+		if s.NextRunAt == nil {
+			s.Status = dao.BountySeriesStatusEnded
+		}
+		return tx.Model(&s).Updates(map[string]interface{}{
+			"occurrence_count": s.OccurrenceCount,
+			"last_error":       s.LastError,
+			"next_run_at":      s.NextRunAt,
+			"status":           s.Status,
+		}).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+	return spawned, spawnErr
+}
+
+func (r *bountySeriesRepo) ListOccurrences(seriesID uuid.UUID, page pagination.Page) (*pagination.Result[*dao.Bounty], error) {
+	q := r.db.Model(&dao.Bounty{}).Where("series_id = ?", seriesID)
+	return pagination.Keyset[*dao.Bounty](q, page, "bounties", pagination.NewestFirst)
+}
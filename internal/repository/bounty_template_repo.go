@@ -0,0 +1,72 @@
+package repository
+
+import (
+	"errors"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"onepenny-server/internal/pagination"
+	"onepenny-server/model/dao"
+)
+
+var (
+	// ErrTemplateNotFound 找不到对应的悬赏令模板
+	ErrTemplateNotFound = errors.New("bounty template not found")
+)
+
+// BountyTemplateRepo 定义悬赏令模板的持久化接口
+type BountyTemplateRepo interface {
+	Create(t *dao.BountyTemplate) error
+	GetByID(id uuid.UUID) (*dao.BountyTemplate, error)
+	// ListAccessible 列出用户可用的模板：自己的个人模板，以及所属团队的模板；teamID 非空时只看该团队
+	ListAccessible(userID uuid.UUID, teamID *uuid.UUID, page pagination.Page) (*pagination.Result[*dao.BountyTemplate], error)
+	Update(t *dao.BountyTemplate) error
+	Delete(id uuid.UUID) error
+}
+
+type bountyTemplateRepo struct {
+	db *gorm.DB
+}
+
+// NewBountyTemplateRepo 构造函数
+func NewBountyTemplateRepo(db *gorm.DB) BountyTemplateRepo {
+	return &bountyTemplateRepo{db: db}
+}
+
+func (r *bountyTemplateRepo) Create(t *dao.BountyTemplate) error {
+	return r.db.Create(t).Error
+}
+
+func (r *bountyTemplateRepo) GetByID(id uuid.UUID) (*dao.BountyTemplate, error) {
+	var t dao.BountyTemplate
+	if err := r.db.First(&t, "id = ?", id).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrTemplateNotFound
+		}
+		return nil, err
+	}
+	return &t, nil
+}
+
+func (r *bountyTemplateRepo) ListAccessible(userID uuid.UUID, teamID *uuid.UUID, page pagination.Page) (*pagination.Result[*dao.BountyTemplate], error) {
+	teams := userTeamIDs(r.db, userID)
+
+	q := r.db.Model(&dao.BountyTemplate{})
+	if teamID != nil {
+		q = q.Where("team_id = ? AND team_id IN (?)", *teamID, teams)
+	} else {
+		q = q.Where("(team_id IS NULL AND user_id = ?) OR team_id IN (?)", userID, teams)
+	}
+
+	// 按名称排序，以偏移翻页
+	return pagination.Offset[*dao.BountyTemplate](q, page, func(db *gorm.DB) *gorm.DB {
+		return db.Order("name ASC, id ASC")
+	})
+}
+
+func (r *bountyTemplateRepo) Update(t *dao.BountyTemplate) error {
+	return r.db.Save(t).Error
+}
+
+func (r *bountyTemplateRepo) Delete(id uuid.UUID) error {
+	return r.db.Delete(&dao.BountyTemplate{}, "id = ?", id).Error
+}
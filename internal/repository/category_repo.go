@@ -0,0 +1,186 @@
+package repository
+
+import (
+	"errors"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"onepenny-server/model/dao"
+	"strings"
+)
+
+var (
+	// ErrCategoryNotFound 找不到对应的分类
+	ErrCategoryNotFound = errors.New("category not found")
+	// ErrCategoryCycle 不能将分类移动到自身或其子孙分类之下
+	ErrCategoryCycle = errors.New("category cannot be moved under itself or its descendants")
+	// ErrCategoryHasChildren 仍有子分类的分类不能删除
+	ErrCategoryHasChildren = errors.New("category still has child categories")
+	// ErrCategoryInUse 仍被悬赏令、模板或周期悬赏使用的分类不能删除
+	ErrCategoryInUse = errors.New("category is still used by bounties, templates or series")
+)
+
+// CategoryRepo 定义分类树的持久化接口。Path 由仓储根据父分类维护，调用方无需设置
+type CategoryRepo interface {
+	Create(c *dao.Category) error
+	GetByID(id uuid.UUID) (*dao.Category, error)
+	GetBySlug(slug string) (*dao.Category, error)
+	// List 列出全部分类，按层级、排序值与 Slug 排列，父分类总在子分类之前
+	List() ([]*dao.Category, error)
+	// Update 保存分类；父分类变化时重新计算自身及全部子孙分类的 Path
+	Update(c *dao.Category) error
+	// Delete 删除没有子分类且未被使用的分类
+	Delete(id uuid.UUID) error
+	// ExpandSlugs 返回给定分类及其全部子孙分类的 Slug，不存在的 Slug 原样保留
+	ExpandSlugs(slugs []string) ([]string, error)
+}
+
+type categoryRepo struct {
+	db *gorm.DB
+}
+
+// NewCategoryRepo 构造函数
+func NewCategoryRepo(db *gorm.DB) CategoryRepo {
+	return &categoryRepo{db: db}
+}
+
+func (r *categoryRepo) Create(c *dao.Category) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		path, err := categoryPath(tx, c.ParentID, c.Slug)
+		if err != nil {
+			return err
+		}
+		c.Path = path
+		return tx.Create(c).Error
+	})
+}
+
+// categoryPath 根据父分类计算 Path
+func categoryPath(tx *gorm.DB, parentID *uuid.UUID, slug string) (string, error) {
+	if parentID == nil {
+		return "/" + slug + "/", nil
+	}
+	var parent dao.Category
+	if err := tx.First(&parent, "id = ?", *parentID).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return "", ErrCategoryNotFound
+		}
+		return "", err
+	}
+	return parent.Path + slug + "/", nil
+}
+
+func (r *categoryRepo) GetByID(id uuid.UUID) (*dao.Category, error) {
+	var c dao.Category
+	if err := r.db.First(&c, "id = ?", id).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrCategoryNotFound
+		}
+		return nil, err
+	}
+	return &c, nil
+}
+
+func (r *categoryRepo) GetBySlug(slug string) (*dao.Category, error) {
+	var c dao.Category
+	if err := r.db.First(&c, "slug = ?", slug).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrCategoryNotFound
+		}
+		return nil, err
+	}
+	return &c, nil
+}
+
+func (r *categoryRepo) List() ([]*dao.Category, error) {
+	var list []*dao.Category
+	err := r.db.
+		Order("array_length(string_to_array(path, '/'), 1) ASC, sort_order ASC, slug ASC").
+		Find(&list).Error
+	return list, err
+}
+
+func (r *categoryRepo) Update(c *dao.Category) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		var cur dao.Category
+		if err := tx.First(&cur, "id = ?", c.ID).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return ErrCategoryNotFound
+			}
+			return err
+		}
+		path, err := categoryPath(tx, c.ParentID, cur.Slug)
+		if err != nil {
+			return err
+		}
+		if path != cur.Path {
+			// 新路径以旧路径开头，说明新的父分类是自身或其子孙
+			if strings.HasPrefix(path, cur.Path) {
+				return ErrCategoryCycle
+			}
+			// Slug 只含小写字母、数字与连字符，无需转义 LIKE 通配符
+			if err := tx.Model(&dao.Category{}).
+				Where("path LIKE ? AND id <> ?", cur.Path+"%", cur.ID).
+				Update("path", gorm.Expr("? || substr(path, ?)", path, len(cur.Path)+1)).Error; err != nil {
+				return err
+			}
+		}
+		c.Slug = cur.Slug
+		c.Path = path
+		return tx.Save(c).Error
+	})
+}
+
+func (r *categoryRepo) Delete(id uuid.UUID) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		var c dao.Category
+		if err := tx.First(&c, "id = ?", id).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return ErrCategoryNotFound
+			}
+			return err
+		}
+		var n int64
+		if err := tx.Model(&dao.Category{}).Where("parent_id = ?", id).Count(&n).Error; err != nil {
+			return err
+		}
+		if n > 0 {
+			return ErrCategoryHasChildren
+		}
+		for _, m := range []interface{}{&dao.Bounty{}, &dao.BountyTemplate{}, &dao.BountySeries{}} {
+			if err := tx.Model(m).Where("category = ?", c.Slug).Count(&n).Error; err != nil {
+				return err
+			}
+			if n > 0 {
+				return ErrCategoryInUse
+			}
+		}
+		// Slug 带唯一索引，需物理删除以便重新创建
+		return tx.Unscoped().Delete(&dao.Category{}, "id = ?", id).Error
+	})
+}
+
+func (r *categoryRepo) ExpandSlugs(slugs []string) ([]string, error) {
+	if len(slugs) == 0 {
+		return slugs, nil
+	}
+	var expanded []string
+	err := r.db.Table("categories AS c").
+		Joins("JOIN categories AS p ON c.path LIKE p.path || '%'").
+		Where("p.slug IN ? AND p.deleted_at IS NULL AND c.deleted_at IS NULL", slugs).
+		Distinct().
+		Pluck("c.slug", &expanded).Error
+	if err != nil {
+		return nil, err
+	}
+	seen := make(map[string]bool, len(expanded))
+	for _, s := range expanded {
+		seen[s] = true
+	}
+	for _, s := range slugs {
+		if !seen[s] {
+			seen[s] = true
+			expanded = append(expanded, s)
+		}
+	}
+	return expanded, nil
+}
@@ -4,6 +4,7 @@ import (
 	"errors"
 	"github.com/google/uuid"
 	"gorm.io/gorm"
+	"onepenny-server/internal/pagination"
 	"onepenny-server/model/dao"
 )
 
@@ -16,9 +17,9 @@ var (
 type CommentRepo interface {
 	Create(c *dao.Comment) error
 	GetByID(id uuid.UUID) (*dao.Comment, error)
-	ListByBounty(bountyID uuid.UUID, offset, limit int) ([]*dao.Comment, error)
-	ListReplies(parentID uuid.UUID, offset, limit int) ([]*dao.Comment, error)
-	ListByUser(userID uuid.UUID, offset, limit int) ([]*dao.Comment, error)
+	ListByBounty(bountyID uuid.UUID, page pagination.Page) (*pagination.Result[*dao.Comment], error)
+	ListReplies(parentID uuid.UUID, page pagination.Page) (*pagination.Result[*dao.Comment], error)
+	ListByUser(userID uuid.UUID, page pagination.Page) (*pagination.Result[*dao.Comment], error)
 	Update(c *dao.Comment) error
 	Delete(id uuid.UUID) error
 }
@@ -47,43 +48,19 @@ func (r *commentRepo) GetByID(id uuid.UUID) (*dao.Comment, error) {
 	return &c, nil
 }
 
-func (r *commentRepo) ListByBounty(bountyID uuid.UUID, offset, limit int) ([]*dao.Comment, error) {
-	var list []*dao.Comment
-	if err := r.db.
-		Where("bounty_id = ? AND parent_id IS NULL", bountyID).
-		Order("created_at ASC").
-		Offset(offset).
-		Limit(limit).
-		Find(&list).Error; err != nil {
-		return nil, err
-	}
-	return list, nil
+func (r *commentRepo) ListByBounty(bountyID uuid.UUID, page pagination.Page) (*pagination.Result[*dao.Comment], error) {
+	q := r.db.Model(&dao.Comment{}).Where("bounty_id = ? AND parent_id IS NULL", bountyID)
+	return pagination.Keyset[*dao.Comment](q, page, "comments", pagination.OldestFirst)
 }
 
-func (r *commentRepo) ListReplies(parentID uuid.UUID, offset, limit int) ([]*dao.Comment, error) {
-	var list []*dao.Comment
-	if err := r.db.
-		Where("parent_id = ?", parentID).
-		Order("created_at ASC").
-		Offset(offset).
-		Limit(limit).
-		Find(&list).Error; err != nil {
-		return nil, err
-	}
-	return list, nil
+func (r *commentRepo) ListReplies(parentID uuid.UUID, page pagination.Page) (*pagination.Result[*dao.Comment], error) {
+	q := r.db.Model(&dao.Comment{}).Where("parent_id = ?", parentID)
+	return pagination.Keyset[*dao.Comment](q, page, "comments", pagination.OldestFirst)
 }
 
-func (r *commentRepo) ListByUser(userID uuid.UUID, offset, limit int) ([]*dao.Comment, error) {
-	var list []*dao.Comment
-	if err := r.db.
-		Where("user_id = ?", userID).
-		Order("created_at DESC").
-		Offset(offset).
-		Limit(limit).
-		Find(&list).Error; err != nil {
-		return nil, err
-	}
-	return list, nil
+func (r *commentRepo) ListByUser(userID uuid.UUID, page pagination.Page) (*pagination.Result[*dao.Comment], error) {
+	q := r.db.Model(&dao.Comment{}).Where("user_id = ?", userID)
+	return pagination.Keyset[*dao.Comment](q, page, "comments", pagination.NewestFirst)
 }
 
 func (r *commentRepo) Update(c *dao.Comment) error {
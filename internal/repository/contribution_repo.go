@@ -0,0 +1,107 @@
+package repository
+
+import (
+	"errors"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"onepenny-server/internal/pagination"
+	"onepenny-server/model/dao"
+)
+
+var (
+	// ErrContributionClosed 悬赏令已进入结算或已结束，不再接受追加
+	ErrContributionClosed = errors.New("bounty no longer accepts contributions")
+	// ErrOwnerCannotContribute 发布者应直接修改赏金，而不是追加
+	ErrOwnerCannotContribute = errors.New("bounty owner cannot contribute to own bounty")
+)
+
+// CreateContributionInput 追加赏金输入
+type CreateContributionInput struct {
+	BountyID uuid.UUID
+	UserID   uuid.UUID
+	Currency string
+	Amount   float64
+	Message  string
+}
+
+// ContributionRepo 定义众筹追加的持久化接口
+type ContributionRepo interface {
+	Create(input *CreateContributionInput) (*dao.Contribution, error)
+	// ListByBounty 按时间先后列出悬赏令的出资记录（含已退回的）
+	ListByBounty(bountyID uuid.UUID, page pagination.Page) (*pagination.Result[*dao.Contribution], error)
+}
+
+type contributionRepo struct {
+	db *gorm.DB
+}
+
+// NewContributionRepo 构造函数
+func NewContributionRepo(db *gorm.DB) ContributionRepo {
+	return &contributionRepo{db: db}
+}
+
+// Create 出资人追加赏金：从出资人钱包转入悬赏令对应币种的托管账户
+func (r *contributionRepo) Create(input *CreateContributionInput) (*dao.Contribution, error) {
+	ct := &dao.Contribution{
+		BaseModel: dao.BaseModel{ID: uuid.New()},
+		BountyID:  input.BountyID,
+		UserID:    input.UserID,
+		Currency:  input.Currency,
+		Amount:    input.Amount,
+		Message:   input.Message,
+		Status:    dao.ContributionStatusActive,
+	}
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		b, err := lockBounty(tx, input.BountyID)
+		if err != nil {
+			return err
+		}
+		if b.UserID == input.UserID {
+			return ErrOwnerCannotContribute
+		}
+		if b.Status != dao.BountyStatusCreated && b.Status != dao.BountyStatusInProgress {
+			return ErrContributionClosed
+		}
+
+		backer, err := lockWallet(tx, dao.WalletOwnerUser, input.UserID, input.Currency)
+		if err != nil {
+			return err
+		}
+		escrow, err := lockWallet(tx, dao.WalletOwnerEscrow, b.ID, input.Currency)
+		if err != nil {
+			return err
+		}
+		if err := postLedger(tx, dao.LedgerTxContribution, &b.ID, "",
+			ledgerLeg{wallet: backer, amount: -input.Amount},
+			ledgerLeg{wallet: escrow, amount: input.Amount},
+		); err != nil {
+			return err
+		}
+		if err := tx.Create(ct).Error; err != nil {
+			return err
+		}
+		return recordBountyEvent(tx, &dao.BountyEvent{
+			BountyID:   b.ID,
+			ActorID:    &input.UserID,
+			Type:       dao.BountyEventContributionAdded,
+			FromStatus: b.Status,
+			ToStatus:   b.Status,
+			Payload: map[string]interface{}{
+				"contribution_id": ct.ID,
+				"currency":        ct.Currency,
+				"amount":          ct.Amount,
+			},
+		})
+	})
+	if err != nil {
+		return nil, err
+	}
+	return ct, nil
+}
+
+func (r *contributionRepo) ListByBounty(bountyID uuid.UUID, page pagination.Page) (*pagination.Result[*dao.Contribution], error) {
+	q := r.db.Model(&dao.Contribution{}).Where("bounty_id = ?", bountyID)
+	return pagination.Keyset[*dao.Contribution](q, page, "contributions", pagination.OldestFirst, func(db *gorm.DB) *gorm.DB {
+		return db.Preload("User")
+	})
+}
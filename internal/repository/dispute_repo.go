@@ -0,0 +1,268 @@
+package repository
+
+import (
+	"errors"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"onepenny-server/internal/pagination"
+	"onepenny-server/model/dao"
+	"time"
+)
+
+var (
+	// ErrDisputeNotFound 找不到争议
+	ErrDisputeNotFound = errors.New("dispute not found")
+	// ErrNotBountyParty 只有悬赏令的发布者或接收者可以发起争议
+	ErrNotBountyParty = errors.New("only the bounty owner or receiver can dispute")
+	// ErrNotDisputeParty 只有争议双方可以查看或补充证据
+	ErrNotDisputeParty = errors.New("only dispute parties can access this dispute")
+	// ErrNotDisputeInitiator 只有发起方可以撤回争议
+	ErrNotDisputeInitiator = errors.New("only the initiator can withdraw the dispute")
+	// ErrDisputeNotOpen 争议已结束
+	ErrDisputeNotOpen = errors.New("dispute is not open")
+	// ErrInvalidRuling 未定义的裁决结果
+	ErrInvalidRuling = errors.New("invalid dispute ruling")
+	// ErrInvalidPayout 部分发放的金额必须大于 0 且小于托管余额
+	ErrInvalidPayout = errors.New("invalid payout amount")
+)
+
+// OpenDisputeInput 发起争议输入
+type OpenDisputeInput struct {
+	BountyID     uuid.UUID
+	InitiatorID  uuid.UUID
+	Reason       string
+	EvidenceURLs []string
+}
+
+// ResolveDisputeInput 仲裁裁决输入
+type ResolveDisputeInput struct {
+	DisputeID    uuid.UUID
+	ArbitratorID uuid.UUID
+	Ruling       dao.DisputeRuling
+	PayoutAmount float64 // 仅部分发放时使用
+	Note         string
+}
+
+// DisputeRepo 定义争议表的持久化接口
+type DisputeRepo interface {
+	Open(input *OpenDisputeInput) (*dao.Dispute, error)
+	GetByID(id uuid.UUID) (*dao.Dispute, error)
+	ListByBounty(bountyID, userID uuid.UUID, page pagination.Page) (*pagination.Result[*dao.Dispute], error)
+	ListByStatus(status dao.DisputeStatus, page pagination.Page) (*pagination.Result[*dao.Dispute], error)
+	AddEvidence(id, userID uuid.UUID, urls []string) (*dao.Dispute, error)
+	Withdraw(id, userID uuid.UUID) (*dao.Dispute, error)
+	Resolve(input *ResolveDisputeInput) (*dao.Dispute, error)
+}
+
+type disputeRepo struct {
+	db *gorm.DB
+}
+
+// NewDisputeRepo 构造函数
+func NewDisputeRepo(db *gorm.DB) DisputeRepo {
+	return &disputeRepo{db: db}
+}
+
+// Open 发起争议：悬赏令进入 disputed 状态，并写入争议记录
+func (r *disputeRepo) Open(input *OpenDisputeInput) (*dao.Dispute, error) {
+	d := &dao.Dispute{
+		BaseModel:    dao.BaseModel{ID: uuid.New()},
+		BountyID:     input.BountyID,
+		InitiatorID:  input.InitiatorID,
+		Reason:       input.Reason,
+		EvidenceURLs: input.EvidenceURLs,
+		Status:       dao.DisputeStatusOpen,
+	}
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		var b dao.Bounty
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+			First(&b, "id = ?", input.BountyID).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return ErrBountyNotFound
+			}
+			return err
+		}
+		if b.ReceiverID == nil {
+			return &dao.BountyTransitionError{From: b.Status, To: dao.BountyStatusDisputed}
+		}
+		switch input.InitiatorID {
+		case b.UserID:
+			d.RespondentID = *b.ReceiverID
+		case *b.ReceiverID:
+			d.RespondentID = b.UserID
+		default:
+			return ErrNotBountyParty
+		}
+		d.PrevStatus = b.Status
+
+		if err := transitionBounty(tx, &b, dao.BountyStatusDisputed, &dao.BountyEvent{
+			ActorID: &input.InitiatorID,
+			Type:    dao.BountyEventDisputeOpened,
+			Payload: map[string]interface{}{"dispute_id": d.ID, "reason": input.Reason},
+		}); err != nil {
+			return err
+		}
+		return tx.Create(d).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+	return d, nil
+}
+
+func (r *disputeRepo) GetByID(id uuid.UUID) (*dao.Dispute, error) {
+	var d dao.Dispute
+	if err := r.db.First(&d, "id = ?", id).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrDisputeNotFound
+		}
+		return nil, err
+	}
+	return &d, nil
+}
+
+// ListByBounty 列出某悬赏令下 userID 作为当事人的争议
+func (r *disputeRepo) ListByBounty(bountyID, userID uuid.UUID, page pagination.Page) (*pagination.Result[*dao.Dispute], error) {
+	q := r.db.Model(&dao.Dispute{}).Where("bounty_id = ? AND (initiator_id = ? OR respondent_id = ?)", bountyID, userID, userID)
+	return pagination.Keyset[*dao.Dispute](q, page, "disputes", pagination.NewestFirst)
+}
+
+// ListByStatus 仲裁队列，按发起时间先后排列
+func (r *disputeRepo) ListByStatus(status dao.DisputeStatus, page pagination.Page) (*pagination.Result[*dao.Dispute], error) {
+	q := r.db.Model(&dao.Dispute{}).Where("status = ?", status)
+	return pagination.Keyset[*dao.Dispute](q, page, "disputes", pagination.OldestFirst)
+}
+
+// AddEvidence 争议任一方补充证据
+func (r *disputeRepo) AddEvidence(id, userID uuid.UUID, urls []string) (*dao.Dispute, error) {
+	var d dao.Dispute
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		if err := lockOpenDispute(tx, id, &d); err != nil {
+			return err
+		}
+		if d.InitiatorID != userID && d.RespondentID != userID {
+			return ErrNotDisputeParty
+		}
+		d.EvidenceURLs = append(d.EvidenceURLs, urls...)
+		return tx.Model(&d).Update("evidence_urls", d.EvidenceURLs).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &d, nil
+}
+
+// Withdraw 发起方撤回争议，悬赏令恢复到争议前的状态
+func (r *disputeRepo) Withdraw(id, userID uuid.UUID) (*dao.Dispute, error) {
+	var d dao.Dispute
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		if err := lockOpenDispute(tx, id, &d); err != nil {
+			return err
+		}
+		if d.InitiatorID != userID {
+			return ErrNotDisputeInitiator
+		}
+
+		var b dao.Bounty
+		if err := tx.First(&b, "id = ?", d.BountyID).Error; err != nil {
+			return err
+		}
+		if err := transitionBounty(tx, &b, d.PrevStatus, &dao.BountyEvent{
+			ActorID: &userID,
+			Type:    dao.BountyEventDisputeWithdrawn,
+			Payload: map[string]interface{}{"dispute_id": d.ID},
+		}); err != nil {
+			return err
+		}
+		// 回到待结算时重新计时，给发布者留出确认时间
+		if d.PrevStatus == dao.BountyStatusPendingSettlement {
+			if err := tx.Model(&b).Update("settlement_requested_at", time.Now()).Error; err != nil {
+				return err
+			}
+		}
+
+		d.Status = dao.DisputeStatusWithdrawn
+		return tx.Model(&d).Update("status", d.Status).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &d, nil
+}
+
+// Resolve 仲裁裁决：按裁决结果分配托管赏金，并结束悬赏令
+func (r *disputeRepo) Resolve(input *ResolveDisputeInput) (*dao.Dispute, error) {
+	var d dao.Dispute
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		if err := lockOpenDispute(tx, input.DisputeID, &d); err != nil {
+			return err
+		}
+		var b dao.Bounty
+		if err := tx.First(&b, "id = ?", d.BountyID).Error; err != nil {
+			return err
+		}
+
+		escrow, err := lockWallet(tx, dao.WalletOwnerEscrow, b.ID, b.Currency)
+		if err != nil {
+			return err
+		}
+
+		// 全额发放与退款由状态流转自动完成，部分发放需先拆分托管余额
+		to := dao.BountyStatusSettled
+		payout := escrow.Balance
+		switch input.Ruling {
+		case dao.DisputeRulingFullPayout:
+		case dao.DisputeRulingRefund:
+			to = dao.BountyStatusCancelled
+			payout = 0
+		case dao.DisputeRulingPartialPayout:
+			payout = input.PayoutAmount
+			if err := splitEscrow(tx, &b, payout); err != nil {
+				return err
+			}
+		default:
+			return ErrInvalidRuling
+		}
+
+		if err := transitionBounty(tx, &b, to, &dao.BountyEvent{
+			ActorID: &input.ArbitratorID,
+			Type:    dao.BountyEventDisputeResolved,
+			Payload: map[string]interface{}{
+				"dispute_id":    d.ID,
+				"ruling":        input.Ruling,
+				"payout_amount": payout,
+			},
+		}); err != nil {
+			return err
+		}
+
+		now := time.Now()
+		d.Status = dao.DisputeStatusResolved
+		d.ArbitratorID = &input.ArbitratorID
+		d.Ruling = input.Ruling
+		d.PayoutAmount = payout
+		d.RulingNote = input.Note
+		d.ResolvedAt = &now
+		return tx.Save(&d).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &d, nil
+}
+
+// lockOpenDispute 加锁读取争议，并要求其仍处于 open 状态
+func lockOpenDispute(tx *gorm.DB, id uuid.UUID, d *dao.Dispute) error {
+	if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+		First(d, "id = ?", id).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return ErrDisputeNotFound
+		}
+		return err
+	}
+	if d.Status != dao.DisputeStatusOpen {
+		return ErrDisputeNotOpen
+	}
+	return nil
+}
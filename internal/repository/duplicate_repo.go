@@ -0,0 +1,146 @@
+package repository
+
+import (
+	"fmt"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"math"
+	"onepenny-server/model/dao"
+	"strconv"
+	"time"
+)
+
+// duplicateStatuses 参与查重的悬赏令状态：尚未完成的
+var duplicateStatuses = []dao.BountyStatus{dao.BountyStatusCreated, dao.BountyStatusInProgress}
+
+// duplicateTitleFloor 报告自连接时标题相似度的下限，与 pg_trgm 的默认阈值一致，低于它的组合不比较
+const duplicateTitleFloor = 0.3
+
+// similarityExpr 两条悬赏令的相似度（0~1）：标题占七成、描述占三成，任一方没有描述时只比较标题
+func similarityExpr(titleA, titleB, descA, descB string) string {
+	return fmt.Sprintf("CASE WHEN COALESCE(%[3]s, '') = '' OR COALESCE(%[4]s, '') = '' THEN similarity(%[1]s, %[2]s) "+
+		"ELSE 0.7 * similarity(%[1]s, %[2]s) + 0.3 * similarity(%[3]s, %[4]s) END",
+		titleA, titleB, descA, descB)
+}
+
+// SimilarQuery 按标题与描述查找相似悬赏令的条件
+type SimilarQuery struct {
+	ViewerID    uuid.UUID // 只比对其可见且公开列出的悬赏令，自己的全部参与
+	Title       string
+	Description string
+	Since       time.Time // 只比对该时间之后创建的
+	MinScore    float64
+	Limit       int
+}
+
+// SimilarBounty 相似的悬赏令及相似度
+type SimilarBounty struct {
+	Bounty *dao.Bounty
+	Score  float64
+}
+
+// DuplicatePair 一对相似的悬赏令，AID < BID
+type DuplicatePair struct {
+	AID   uuid.UUID `gorm:"column:a_id"`
+	BID   uuid.UUID `gorm:"column:b_id"`
+	Score float64
+}
+
+// DuplicateRepo 定义基于 pg_trgm 三元组相似度的悬赏令查重接口
+type DuplicateRepo interface {
+	// FindSimilar 按相似度降序列出近期未完成的相似悬赏令
+	FindSimilar(q *SimilarQuery) ([]*SimilarBounty, error)
+	// ListPairs 列出 since 之后创建、尚未完成且相似度不低于 minScore 的悬赏令对，按相似度降序；
+	// 只比较标题相似度足以达到 minScore（且不低于 0.3）的组合，以便用 % 运算符走标题三元组索引
+	ListPairs(since time.Time, minScore float64, limit int) ([]DuplicatePair, error)
+	// ListBounties 按 ID 加载悬赏令
+	ListBounties(ids []uuid.UUID) ([]*dao.Bounty, error)
+}
+
+type duplicateRepo struct {
+	db *gorm.DB
+}
+
+// NewDuplicateRepo 构造函数
+func NewDuplicateRepo(db *gorm.DB) DuplicateRepo {
+	return &duplicateRepo{db: db}
+}
+
+func (r *duplicateRepo) FindSimilar(q *SimilarQuery) ([]*SimilarBounty, error) {
+	score := similarityExpr("bounties.title", "probe.title", "bounties.description", "probe.description")
+	var hits []struct {
+		ID    uuid.UUID
+		Score float64
+	}
+	if err := r.db.Model(&dao.Bounty{}).
+		Scopes(visibleTo(r.db, q.ViewerID, false)).
+		Joins("CROSS JOIN (SELECT ?::text AS title, ?::text AS description) AS probe", q.Title, q.Description).
+		Where("bounties.status IN ? AND bounties.created_at >= ?", duplicateStatuses, q.Since).
+		Where(score+" >= ?", q.MinScore).
+		Select("bounties.id, " + score + " AS score").
+		Order("score DESC, bounties.created_at DESC").
+		Limit(q.Limit).
+		Scan(&hits).Error; err != nil {
+		return nil, err
+	}
+	if len(hits) == 0 {
+		return []*SimilarBounty{}, nil
+	}
+
+	ids := make([]uuid.UUID, len(hits))
+	for i, h := range hits {
+		ids[i] = h.ID
+	}
+	list, err := r.ListBounties(ids)
+	if err != nil {
+		return nil, err
+	}
+	byID := make(map[uuid.UUID]*dao.Bounty, len(list))
+	for _, b := range list {
+		byID[b.ID] = b
+	}
+	res := make([]*SimilarBounty, 0, len(hits))
+	for _, h := range hits {
+		if b, ok := byID[h.ID]; ok {
+			res = append(res, &SimilarBounty{Bounty: b, Score: h.Score})
+		}
+	}
+	return res, nil
+}
+
+func (r *duplicateRepo) ListPairs(since time.Time, minScore float64, limit int) ([]DuplicatePair, error) {
+	// 描述最多贡献 0.3，标题相似度低于 (minScore-0.3)/0.7 的组合不可能达到 minScore
+	titleMin := math.Max((minScore-0.3)/0.7, duplicateTitleFloor)
+	score := similarityExpr("a.title", "b.title", "a.description", "b.description")
+	var pairs []DuplicatePair
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		// % 运算符按 pg_trgm.similarity_threshold 判断，仅在本事务内调整
+		if err := tx.Exec("SELECT set_config('pg_trgm.similarity_threshold', ?, true)",
+			strconv.FormatFloat(titleMin, 'f', -1, 64)).Error; err != nil {
+			return err
+		}
+		return tx.Raw(`SELECT * FROM (
+			SELECT a.id AS a_id, b.id AS b_id, `+score+` AS score
+			FROM bounties a
+			JOIN bounties b ON a.id < b.id AND a.title % b.title
+			WHERE a.deleted_at IS NULL AND b.deleted_at IS NULL
+				AND a.status IN ? AND b.status IN ?
+				AND a.created_at >= ? AND b.created_at >= ?
+		) AS pairs
+		WHERE score >= ?
+		ORDER BY score DESC
+		LIMIT ?`,
+			duplicateStatuses, duplicateStatuses, since, since, minScore, limit).
+			Scan(&pairs).Error
+	})
+	return pairs, err
+}
+
+func (r *duplicateRepo) ListBounties(ids []uuid.UUID) ([]*dao.Bounty, error) {
+	var list []*dao.Bounty
+	err := r.db.
+		Preload("Contributions", "status <> ?", dao.ContributionStatusRefunded).
+		Where("id IN ?", ids).
+		Find(&list).Error
+	return list, err
+}
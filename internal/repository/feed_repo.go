@@ -0,0 +1,135 @@
+package repository
+
+import (
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+	"gorm.io/gorm"
+	"onepenny-server/model/dao"
+	"time"
+)
+
+// 推荐信号的来源
+const (
+	FeedSignalView   = "view"   // 浏览过
+	FeedSignalLike   = "like"   // 点赞过
+	FeedSignalApply  = "apply"  // 申请过
+	FeedSignalAccept = "accept" // 承接过
+)
+
+// FeedSignal 用户与某个悬赏令的一次交互，连同该悬赏令的分类与标签
+type FeedSignal struct {
+	Kind     string
+	Category string
+	Tags     pq.StringArray `gorm:"type:text[]"`
+}
+
+// FeedCandidateQuery 推荐候选的查询条件
+type FeedCandidateQuery struct {
+	ViewerID   uuid.UUID
+	Categories []string // 分类或标签任一命中即可；两者都为空时不限
+	Tags       []string
+	Since      *time.Time // 只取此后发布的悬赏令，用于增量刷新
+	Limit      int
+}
+
+// FeedRepo 定义个性化推荐所需的查询。可推荐的悬赏令须已发布、尚无人承接、对用户公开列出，
+// 且不是用户自己发布或已申请过的
+type FeedRepo interface {
+	// ListSignals 列出用户 since 之后的浏览、点赞、申请与承接记录
+	ListSignals(userID uuid.UUID, since time.Time) ([]FeedSignal, error)
+	// ListCandidates 按发布时间倒序列出可推荐的悬赏令，只取分类、标签与时间字段
+	ListCandidates(q *FeedCandidateQuery) ([]*dao.Bounty, error)
+	// FilterRecommendable 返回 ids 中仍可推荐给 viewerID 的悬赏令，顺序不定
+	FilterRecommendable(viewerID uuid.UUID, ids []uuid.UUID) ([]*dao.Bounty, error)
+	// GetTaxonomy 只取悬赏令的分类与标签
+	GetTaxonomy(ids []uuid.UUID) ([]*dao.Bounty, error)
+}
+
+type feedRepo struct {
+	db *gorm.DB
+}
+
+// NewFeedRepo 构造函数
+func NewFeedRepo(db *gorm.DB) FeedRepo {
+	return &feedRepo{db: db}
+}
+
+func (r *feedRepo) ListSignals(userID uuid.UUID, since time.Time) ([]FeedSignal, error) {
+	var signals []FeedSignal
+	err := r.db.Raw(`
+		SELECT s.kind, b.category, b.tags FROM (
+			SELECT bounty_id, ? AS kind FROM bounty_views
+			WHERE user_id = ? AND created_at >= ? AND deleted_at IS NULL
+			UNION ALL
+			SELECT likeable_id, ? FROM likes
+			WHERE user_id = ? AND likeable_type = 'bounty' AND created_at >= ? AND deleted_at IS NULL
+			UNION ALL
+			SELECT bounty_id, ? FROM applications
+			WHERE user_id = ? AND created_at >= ? AND deleted_at IS NULL
+			UNION ALL
+			SELECT id, ? FROM bounties
+			WHERE receiver_id = ? AND updated_at >= ? AND deleted_at IS NULL
+		) AS s JOIN bounties b ON b.id = s.bounty_id AND b.deleted_at IS NULL`,
+		FeedSignalView, userID, since,
+		FeedSignalLike, userID, since,
+		FeedSignalApply, userID, since,
+		FeedSignalAccept, userID, since,
+	).Scan(&signals).Error
+	return signals, err
+}
+
+// recommendable 可推荐给 viewerID 的悬赏令
+func (r *feedRepo) recommendable(viewerID uuid.UUID) *gorm.DB {
+	applied := r.db.Model(&dao.Application{}).Select("bounty_id").Where("user_id = ?", viewerID)
+	return r.db.Model(&dao.Bounty{}).
+		Scopes(visibleTo(r.db, viewerID, false)).
+		Where("bounties.status = ? AND bounties.receiver_id IS NULL", dao.BountyStatusCreated).
+		Where("bounties.user_id <> ? AND bounties.id NOT IN (?)", viewerID, applied)
+}
+
+func (r *feedRepo) ListCandidates(q *FeedCandidateQuery) ([]*dao.Bounty, error) {
+	tx := r.recommendable(q.ViewerID)
+	switch {
+	case len(q.Categories) > 0 && len(q.Tags) > 0:
+		tx = tx.Where("bounties.category IN ? OR bounties.tags && ?", q.Categories, pq.StringArray(q.Tags))
+	case len(q.Categories) > 0:
+		tx = tx.Where("bounties.category IN ?", q.Categories)
+	case len(q.Tags) > 0:
+		tx = tx.Where("bounties.tags && ?", pq.StringArray(q.Tags))
+	}
+	if q.Since != nil {
+		// 由草稿发布的悬赏令以实际发布时间为准
+		tx = tx.Where("COALESCE(bounties.publish_at, bounties.created_at) > ?", *q.Since)
+	}
+	var list []*dao.Bounty
+	err := tx.
+		Select("bounties.id, bounties.category, bounties.tags, bounties.created_at, bounties.publish_at").
+		Order("COALESCE(bounties.publish_at, bounties.created_at) DESC").
+		Limit(q.Limit).
+		Find(&list).Error
+	return list, err
+}
+
+func (r *feedRepo) FilterRecommendable(viewerID uuid.UUID, ids []uuid.UUID) ([]*dao.Bounty, error) {
+	var list []*dao.Bounty
+	if len(ids) == 0 {
+		return list, nil
+	}
+	err := r.recommendable(viewerID).
+		Where("bounties.id IN ?", ids).
+		Preload("Contributions", "status <> ?", dao.ContributionStatusRefunded).
+		Find(&list).Error
+	return list, err
+}
+
+func (r *feedRepo) GetTaxonomy(ids []uuid.UUID) ([]*dao.Bounty, error) {
+	var list []*dao.Bounty
+	if len(ids) == 0 {
+		return list, nil
+	}
+	err := r.db.Model(&dao.Bounty{}).
+		Select("id, category, tags").
+		Where("id IN ?", ids).
+		Find(&list).Error
+	return list, err
+}
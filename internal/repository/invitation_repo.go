@@ -4,7 +4,9 @@ import (
 	"errors"
 	"github.com/google/uuid"
 	"gorm.io/gorm"
+	"onepenny-server/internal/pagination"
 	"onepenny-server/model/dao"
+	"time"
 )
 
 var (
@@ -16,11 +18,13 @@ var (
 type InvitationRepo interface {
 	Create(inv *dao.Invitation) error
 	GetByID(id uuid.UUID) (*dao.Invitation, error)
-	ListByInvitee(inviteeID uuid.UUID, offset, limit int) ([]*dao.Invitation, error)
-	ListByInviter(inviterID uuid.UUID, offset, limit int) ([]*dao.Invitation, error)
-	ListByTeam(teamID uuid.UUID, offset, limit int) ([]*dao.Invitation, error)
+	ListByInvitee(inviteeID uuid.UUID, page pagination.Page) (*pagination.Result[*dao.Invitation], error)
+	ListByInviter(inviterID uuid.UUID, page pagination.Page) (*pagination.Result[*dao.Invitation], error)
+	ListByTeam(teamID uuid.UUID, page pagination.Page) (*pagination.Result[*dao.Invitation], error)
 	Update(inv *dao.Invitation) error
 	Delete(id uuid.UUID) error
+	// RejectExpired 将 before 之前到期仍未回应的邀请标记为拒绝，返回处理条数
+	RejectExpired(before time.Time) (int64, error)
 }
 
 type invitationRepo struct {
@@ -47,40 +51,19 @@ func (r *invitationRepo) GetByID(id uuid.UUID) (*dao.Invitation, error) {
 	return &inv, nil
 }
 
-func (r *invitationRepo) ListByInvitee(inviteeID uuid.UUID, offset, limit int) ([]*dao.Invitation, error) {
-	var list []*dao.Invitation
-	if err := r.db.
-		Where("invitee_id = ?", inviteeID).
-		Offset(offset).
-		Limit(limit).
-		Find(&list).Error; err != nil {
-		return nil, err
-	}
-	return list, nil
+func (r *invitationRepo) ListByInvitee(inviteeID uuid.UUID, page pagination.Page) (*pagination.Result[*dao.Invitation], error) {
+	q := r.db.Model(&dao.Invitation{}).Where("invitee_id = ?", inviteeID)
+	return pagination.Keyset[*dao.Invitation](q, page, "invitations", pagination.NewestFirst)
 }
 
-func (r *invitationRepo) ListByInviter(inviterID uuid.UUID, offset, limit int) ([]*dao.Invitation, error) {
-	var list []*dao.Invitation
-	if err := r.db.
-		Where("inviter_id = ?", inviterID).
-		Offset(offset).
-		Limit(limit).
-		Find(&list).Error; err != nil {
-		return nil, err
-	}
-	return list, nil
+func (r *invitationRepo) ListByInviter(inviterID uuid.UUID, page pagination.Page) (*pagination.Result[*dao.Invitation], error) {
+	q := r.db.Model(&dao.Invitation{}).Where("inviter_id = ?", inviterID)
+	return pagination.Keyset[*dao.Invitation](q, page, "invitations", pagination.NewestFirst)
 }
 
-func (r *invitationRepo) ListByTeam(teamID uuid.UUID, offset, limit int) ([]*dao.Invitation, error) {
-	var list []*dao.Invitation
-	if err := r.db.
-		Where("team_id = ?", teamID).
-		Offset(offset).
-		Limit(limit).
-		Find(&list).Error; err != nil {
-		return nil, err
-	}
-	return list, nil
+func (r *invitationRepo) ListByTeam(teamID uuid.UUID, page pagination.Page) (*pagination.Result[*dao.Invitation], error) {
+	q := r.db.Model(&dao.Invitation{}).Where("team_id = ?", teamID)
+	return pagination.Keyset[*dao.Invitation](q, page, "invitations", pagination.NewestFirst)
 }
 
 func (r *invitationRepo) Update(inv *dao.Invitation) error {
@@ -90,3 +73,13 @@ func (r *invitationRepo) Update(inv *dao.Invitation) error {
 func (r *invitationRepo) Delete(id uuid.UUID) error {
 	return r.db.Delete(&dao.Invitation{}, "id = ?", id).Error
 }
+
+func (r *invitationRepo) RejectExpired(before time.Time) (int64, error) {
+	res := r.db.Model(&dao.Invitation{}).
+		Where("status = ? AND expires_at < ?", dao.InvitationStatusPending, before).
+		Updates(map[string]interface{}{
+			"status":       dao.InvitationStatusRejected,
+			"responded_at": before,
+		})
+	return res.RowsAffected, res.Error
+}
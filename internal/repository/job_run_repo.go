@@ -0,0 +1,35 @@
+package repository
+
+import (
+	"gorm.io/gorm"
+	"onepenny-server/internal/pagination"
+	"onepenny-server/model/dao"
+)
+
+// JobRunRepo 定义后台任务执行记录的持久化接口
+type JobRunRepo interface {
+	Create(run *dao.JobRun) error
+	// List 按开始时间倒序列出执行记录，job 为空时列出全部任务
+	List(job string, page pagination.Page) (*pagination.Result[*dao.JobRun], error)
+}
+
+type jobRunRepo struct {
+	db *gorm.DB
+}
+
+// NewJobRunRepo 构造函数
+func NewJobRunRepo(db *gorm.DB) JobRunRepo {
+	return &jobRunRepo{db: db}
+}
+
+func (r *jobRunRepo) Create(run *dao.JobRun) error {
+	return r.db.Create(run).Error
+}
+
+func (r *jobRunRepo) List(job string, page pagination.Page) (*pagination.Result[*dao.JobRun], error) {
+	q := r.db.Model(&dao.JobRun{})
+	if job != "" {
+		q = q.Where("job = ?", job)
+	}
+	return pagination.Keyset[*dao.JobRun](q, page, "job_runs", pagination.NewestFirst)
+}
@@ -0,0 +1,308 @@
+package repository
+
+import (
+	"errors"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"math"
+	"onepenny-server/internal/pagination"
+	"onepenny-server/model/dao"
+)
+
+var (
+	// ErrInsufficientBalance 可用余额不足
+	ErrInsufficientBalance = errors.New("insufficient wallet balance")
+	// ErrUnbalancedLedger 凭证借贷不平衡，属于程序错误
+	ErrUnbalancedLedger = errors.New("ledger transaction is unbalanced")
+	// ErrNoBountyReceiver 悬赏令没有接收者，无法发放赏金
+	ErrNoBountyReceiver = errors.New("bounty has no receiver")
+)
+
+// ledgerEpsilon 浮点金额比较的容差
+const ledgerEpsilon = 1e-9
+
+// LedgerRepo 定义钱包与复式记账的持久化接口
+type LedgerRepo interface {
+	ListWallets(userID uuid.UUID) ([]*dao.Wallet, error)
+	ListEntries(userID uuid.UUID, page pagination.Page) (*pagination.Result[*dao.LedgerEntry], error)
+	Deposit(userID uuid.UUID, currency string, amount float64) (*dao.Wallet, error)
+}
+
+type ledgerRepo struct {
+	db *gorm.DB
+}
+
+// NewLedgerRepo 构造函数
+func NewLedgerRepo(db *gorm.DB) LedgerRepo {
+	return &ledgerRepo{db: db}
+}
+
+func (r *ledgerRepo) ListWallets(userID uuid.UUID) ([]*dao.Wallet, error) {
+	var list []*dao.Wallet
+	if err := r.db.
+		Where("owner_type = ? AND owner_id = ?", dao.WalletOwnerUser, userID).
+		Order("currency ASC").
+		Find(&list).Error; err != nil {
+		return nil, err
+	}
+	return list, nil
+}
+
+func (r *ledgerRepo) ListEntries(userID uuid.UUID, page pagination.Page) (*pagination.Result[*dao.LedgerEntry], error) {
+	q := r.db.Model(&dao.LedgerEntry{}).
+		Joins("JOIN wallets ON wallets.id = ledger_entries.wallet_id").
+		Where("wallets.owner_type = ? AND wallets.owner_id = ?", dao.WalletOwnerUser, userID)
+	return pagination.Keyset[*dao.LedgerEntry](q, page, "ledger_entries", pagination.NewestFirst, func(db *gorm.DB) *gorm.DB {
+		return db.Preload("Transaction").Preload("Wallet")
+	})
+}
+
+// Deposit 充值：系统账户出账，用户账户入账
+func (r *ledgerRepo) Deposit(userID uuid.UUID, currency string, amount float64) (*dao.Wallet, error) {
+	var w *dao.Wallet
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		sys, err := lockWallet(tx, dao.WalletOwnerSystem, uuid.Nil, currency)
+		if err != nil {
+			return err
+		}
+		if w, err = lockWallet(tx, dao.WalletOwnerUser, userID, currency); err != nil {
+			return err
+		}
+		return postLedger(tx, dao.LedgerTxDeposit, nil, "",
+			ledgerLeg{wallet: sys, amount: -amount},
+			ledgerLeg{wallet: w, amount: amount},
+		)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return w, nil
+}
+
+// ledgerLeg 凭证中的一条分录
+type ledgerLeg struct {
+	wallet *dao.Wallet
+	amount float64
+}
+
+// lockWallet 在事务中取得（必要时创建）账户并加行锁
+func lockWallet(tx *gorm.DB, ownerType string, ownerID uuid.UUID, currency string) (*dao.Wallet, error) {
+	if err := tx.Clauses(clause.OnConflict{DoNothing: true}).Create(&dao.Wallet{
+		OwnerType: ownerType,
+		OwnerID:   ownerID,
+		Currency:  currency,
+	}).Error; err != nil {
+		return nil, err
+	}
+	var w dao.Wallet
+	if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+		First(&w, "owner_type = ? AND owner_id = ? AND currency = ?", ownerType, ownerID, currency).Error; err != nil {
+		return nil, err
+	}
+	return &w, nil
+}
+
+// postLedger 写入一张凭证及其分录，并同步各账户余额。
+// 所有分录之和必须为 0；除系统账户外，任何账户余额都不允许为负。
+func postLedger(tx *gorm.DB, txType string, bountyID *uuid.UUID, memo string, legs ...ledgerLeg) error {
+	var sum float64
+	for _, l := range legs {
+		sum += l.amount
+	}
+	if math.Abs(sum) > ledgerEpsilon {
+		return ErrUnbalancedLedger
+	}
+
+	lt := &dao.LedgerTransaction{Type: txType, BountyID: bountyID, Memo: memo}
+	if err := tx.Create(lt).Error; err != nil {
+		return err
+	}
+	for _, l := range legs {
+		l.wallet.Balance += l.amount
+		if l.wallet.OwnerType != dao.WalletOwnerSystem && l.wallet.Balance < -ledgerEpsilon {
+			return ErrInsufficientBalance
+		}
+		if err := tx.Model(l.wallet).Update("balance", l.wallet.Balance).Error; err != nil {
+			return err
+		}
+		if err := tx.Create(&dao.LedgerEntry{
+			TransactionID: lt.ID,
+			WalletID:      l.wallet.ID,
+			Amount:        l.amount,
+			BalanceAfter:  l.wallet.Balance,
+		}).Error; err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// lockEscrow 发布悬赏时，从发布者账户锁定赏金至托管账户
+func lockEscrow(tx *gorm.DB, b *dao.Bounty) error {
+	if b.Reward <= 0 {
+		return nil
+	}
+	owner, err := lockWallet(tx, dao.WalletOwnerUser, b.UserID, b.Currency)
+	if err != nil {
+		return err
+	}
+	escrow, err := lockWallet(tx, dao.WalletOwnerEscrow, b.ID, b.Currency)
+	if err != nil {
+		return err
+	}
+	return postLedger(tx, dao.LedgerTxEscrowLock, &b.ID, "",
+		ledgerLeg{wallet: owner, amount: -b.Reward},
+		ledgerLeg{wallet: escrow, amount: b.Reward},
+	)
+}
+
+// releaseEscrow 结算时将各币种托管账户（含众筹追加）中剩余的金额全部发放给接收者
+func releaseEscrow(tx *gorm.DB, b *dao.Bounty) error {
+	if b.ReceiverID == nil {
+		return ErrNoBountyReceiver
+	}
+	if err := tx.Model(&dao.Contribution{}).
+		Where("bounty_id = ? AND status = ?", b.ID, dao.ContributionStatusActive).
+		Update("status", dao.ContributionStatusReleased).Error; err != nil {
+		return err
+	}
+	return drainEscrow(tx, b, *b.ReceiverID, dao.LedgerTxEscrowRelease)
+}
+
+// refundEscrow 取消或过期时先将众筹追加原路退回各出资人，再将剩余赏金退回发布者
+func refundEscrow(tx *gorm.DB, b *dao.Bounty) error {
+	if err := refundContributions(tx, b); err != nil {
+		return err
+	}
+	return drainEscrow(tx, b, b.UserID, dao.LedgerTxEscrowRefund)
+}
+
+// refundBase 只退回发布者自己锁定的基础赏金，众筹追加保持托管；用于修改赏金前的重新锁定
+func refundBase(tx *gorm.DB, b *dao.Bounty) error {
+	escrow, err := lockWallet(tx, dao.WalletOwnerEscrow, b.ID, b.Currency)
+	if err != nil {
+		return err
+	}
+	base, err := escrowBase(tx, escrow, b)
+	if err != nil || base <= ledgerEpsilon {
+		return err
+	}
+	owner, err := lockWallet(tx, dao.WalletOwnerUser, b.UserID, b.Currency)
+	if err != nil {
+		return err
+	}
+	return postLedger(tx, dao.LedgerTxEscrowRefund, &b.ID, "",
+		ledgerLeg{wallet: escrow, amount: -base},
+		ledgerLeg{wallet: owner, amount: base},
+	)
+}
+
+// refundContributions 将仍在托管中的众筹追加逐笔退回出资人
+func refundContributions(tx *gorm.DB, b *dao.Bounty) error {
+	var list []dao.Contribution
+	if err := tx.Where("bounty_id = ? AND status = ?", b.ID, dao.ContributionStatusActive).
+		Find(&list).Error; err != nil {
+		return err
+	}
+	for _, ct := range list {
+		escrow, err := lockWallet(tx, dao.WalletOwnerEscrow, b.ID, ct.Currency)
+		if err != nil {
+			return err
+		}
+		backer, err := lockWallet(tx, dao.WalletOwnerUser, ct.UserID, ct.Currency)
+		if err != nil {
+			return err
+		}
+		if err := postLedger(tx, dao.LedgerTxEscrowRefund, &b.ID, "contribution",
+			ledgerLeg{wallet: escrow, amount: -ct.Amount},
+			ledgerLeg{wallet: backer, amount: ct.Amount},
+		); err != nil {
+			return err
+		}
+		if err := tx.Model(&ct).Update("status", dao.ContributionStatusRefunded).Error; err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// escrowBase 托管账户中属于基础赏金的部分，即扣除同币种仍在托管的众筹追加后的余额
+func escrowBase(tx *gorm.DB, escrow *dao.Wallet, b *dao.Bounty) (float64, error) {
+	var pledged float64
+	if err := tx.Model(&dao.Contribution{}).
+		Where("bounty_id = ? AND currency = ? AND status = ?", b.ID, escrow.Currency, dao.ContributionStatusActive).
+		Select("COALESCE(SUM(amount),0)").Scan(&pledged).Error; err != nil {
+		return 0, err
+	}
+	return escrow.Balance - pledged, nil
+}
+
+// splitEscrow 拆分托管的基础赏金：amount 发放给接收者，剩余退回发布者，众筹追加退回出资人
+func splitEscrow(tx *gorm.DB, b *dao.Bounty, amount float64) error {
+	if b.ReceiverID == nil {
+		return ErrNoBountyReceiver
+	}
+	escrow, err := lockWallet(tx, dao.WalletOwnerEscrow, b.ID, b.Currency)
+	if err != nil {
+		return err
+	}
+	base, err := escrowBase(tx, escrow, b)
+	if err != nil {
+		return err
+	}
+	if amount <= 0 || amount >= base {
+		return ErrInvalidPayout
+	}
+	if err := releaseEscrowPart(tx, b, escrow, amount, ""); err != nil {
+		return err
+	}
+	return refundEscrow(tx, b)
+}
+
+// releaseEscrowPart 从已加锁的托管账户中发放部分赏金给接收者
+func releaseEscrowPart(tx *gorm.DB, b *dao.Bounty, escrow *dao.Wallet, amount float64, memo string) error {
+	if b.ReceiverID == nil {
+		return ErrNoBountyReceiver
+	}
+	receiver, err := lockWallet(tx, dao.WalletOwnerUser, *b.ReceiverID, b.Currency)
+	if err != nil {
+		return err
+	}
+	return postLedger(tx, dao.LedgerTxEscrowRelease, &b.ID, memo,
+		ledgerLeg{wallet: escrow, amount: -amount},
+		ledgerLeg{wallet: receiver, amount: amount},
+	)
+}
+
+// drainEscrow 将悬赏令各币种托管账户的余额全部转给 to
+func drainEscrow(tx *gorm.DB, b *dao.Bounty, to uuid.UUID, txType string) error {
+	var currencies []string
+	if err := tx.Model(&dao.Wallet{}).
+		Where("owner_type = ? AND owner_id = ?", dao.WalletOwnerEscrow, b.ID).
+		Order("currency ASC").
+		Pluck("currency", &currencies).Error; err != nil {
+		return err
+	}
+	for _, cur := range currencies {
+		escrow, err := lockWallet(tx, dao.WalletOwnerEscrow, b.ID, cur)
+		if err != nil {
+			return err
+		}
+		if escrow.Balance <= ledgerEpsilon {
+			continue
+		}
+		dst, err := lockWallet(tx, dao.WalletOwnerUser, to, cur)
+		if err != nil {
+			return err
+		}
+		amount := escrow.Balance
+		if err := postLedger(tx, txType, &b.ID, "",
+			ledgerLeg{wallet: escrow, amount: -amount},
+			ledgerLeg{wallet: dst, amount: amount},
+		); err != nil {
+			return err
+		}
+	}
+	return nil
+}
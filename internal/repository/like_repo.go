@@ -4,6 +4,7 @@ import (
 	"errors"
 	"github.com/google/uuid"
 	"gorm.io/gorm"
+	"onepenny-server/internal/pagination"
 	"onepenny-server/model/dao"
 )
 
@@ -18,8 +19,8 @@ type LikeRepo interface {
 	Delete(userID, targetID uuid.UUID, targetType string) error
 	Exists(userID, targetID uuid.UUID, targetType string) (bool, error)
 	CountByTarget(targetID uuid.UUID, targetType string) (int64, error)
-	ListByTarget(targetID uuid.UUID, targetType string, offset, limit int) ([]*dao.Like, error)
-	ListByUser(userID uuid.UUID, offset, limit int) ([]*dao.Like, error)
+	ListByTarget(targetID uuid.UUID, targetType string, page pagination.Page) (*pagination.Result[*dao.Like], error)
+	ListByUser(userID uuid.UUID, page pagination.Page) (*pagination.Result[*dao.Like], error)
 }
 
 type likeRepo struct {
@@ -63,24 +64,14 @@ func (r *likeRepo) CountByTarget(targetID uuid.UUID, targetType string) (int64,
 	return cnt, err
 }
 
-func (r *likeRepo) ListByTarget(targetID uuid.UUID, targetType string, offset, limit int) ([]*dao.Like, error) {
-	var list []*dao.Like
-	err := r.db.
-		Where("likeable_id = ? AND likeable_type = ?", targetID, targetType).
-		Offset(offset).
-		Limit(limit).
-		Find(&list).Error
-	return list, err
+func (r *likeRepo) ListByTarget(targetID uuid.UUID, targetType string, page pagination.Page) (*pagination.Result[*dao.Like], error) {
+	q := r.db.Model(&dao.Like{}).Where("likeable_id = ? AND likeable_type = ?", targetID, targetType)
+	return pagination.Keyset[*dao.Like](q, page, "likes", pagination.NewestFirst)
 }
 
-func (r *likeRepo) ListByUser(userID uuid.UUID, offset, limit int) ([]*dao.Like, error) {
-	var list []*dao.Like
-	err := r.db.
-		Where("user_id = ?", userID).
-		Offset(offset).
-		Limit(limit).
-		Find(&list).Error
-	return list, err
+func (r *likeRepo) ListByUser(userID uuid.UUID, page pagination.Page) (*pagination.Result[*dao.Like], error) {
+	q := r.db.Model(&dao.Like{}).Where("user_id = ?", userID)
+	return pagination.Keyset[*dao.Like](q, page, "likes", pagination.NewestFirst)
 }
 
 func NewLikeRepo(db *gorm.DB) LikeRepo {
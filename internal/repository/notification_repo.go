@@ -4,7 +4,9 @@ import (
 	"errors"
 	"github.com/google/uuid"
 	"gorm.io/gorm"
+	"onepenny-server/internal/pagination"
 	"onepenny-server/model/dao"
+	"time"
 )
 
 var (
@@ -16,10 +18,14 @@ var (
 type NotificationRepo interface {
 	Create(n *dao.Notification) error
 	GetByID(id uuid.UUID) (*dao.Notification, error)
-	ListByUser(userID uuid.UUID, offset, limit int) ([]*dao.Notification, error)
+	ListByUser(userID uuid.UUID, page pagination.Page) (*pagination.Result[*dao.Notification], error)
 	CountUnread(userID uuid.UUID) (int64, error)
 	Update(n *dao.Notification) error
+	// MarkAllRead 将用户的未读通知全部标记为已读
+	MarkAllRead(userID uuid.UUID) error
 	Delete(id uuid.UUID) error
+	// PurgeExpired 物理删除 before 之前已过期的通知，返回删除条数
+	PurgeExpired(before time.Time) (int64, error)
 }
 
 type notificationRepo struct {
@@ -41,17 +47,15 @@ func (r *notificationRepo) GetByID(id uuid.UUID) (*dao.Notification, error) {
 	return &n, nil
 }
 
-func (r *notificationRepo) ListByUser(userID uuid.UUID, offset, limit int) ([]*dao.Notification, error) {
-	var list []*dao.Notification
-	if err := r.db.
-		Where("user_id = ?", userID).
-		Order("created_at DESC").
-		Offset(offset).
-		Limit(limit).
-		Find(&list).Error; err != nil {
-		return nil, err
-	}
-	return list, nil
+func (r *notificationRepo) MarkAllRead(userID uuid.UUID) error {
+	return r.db.Model(&dao.Notification{}).
+		Where("user_id = ? AND is_read = ?", userID, false).
+		Updates(map[string]interface{}{"is_read": true, "updated_at": time.Now()}).Error
+}
+
+func (r *notificationRepo) ListByUser(userID uuid.UUID, page pagination.Page) (*pagination.Result[*dao.Notification], error) {
+	q := r.db.Model(&dao.Notification{}).Where("user_id = ?", userID)
+	return pagination.Keyset[*dao.Notification](q, page, "notifications", pagination.NewestFirst)
 }
 
 func (r *notificationRepo) CountUnread(userID uuid.UUID) (int64, error) {
@@ -77,3 +81,10 @@ func (r *notificationRepo) Delete(id uuid.UUID) error {
 func NewNotificationRepo(db *gorm.DB) NotificationRepo {
 	return &notificationRepo{db: db}
 }
+
+func (r *notificationRepo) PurgeExpired(before time.Time) (int64, error) {
+	res := r.db.Unscoped().
+		Where("expires_at < ?", before).
+		Delete(&dao.Notification{})
+	return res.RowsAffected, res.Error
+}
@@ -0,0 +1,163 @@
+package repository
+
+import (
+	"errors"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"onepenny-server/internal/pagination"
+	"onepenny-server/model/dao"
+	"time"
+)
+
+// ErrSavedSearchNotFound 找不到对应的保存的搜索
+var ErrSavedSearchNotFound = errors.New("saved search not found")
+
+// SavedSearchRepo 定义保存的搜索及其命中记录的持久化接口
+type SavedSearchRepo interface {
+	Create(s *dao.SavedSearch) error
+	GetByID(id uuid.UUID) (*dao.SavedSearch, error)
+	CountByUser(userID uuid.UUID) (int64, error)
+	ListByUser(userID uuid.UUID, page pagination.Page) (*pagination.Result[*dao.SavedSearch], error)
+	Update(s *dao.SavedSearch) error
+	// Delete 删除保存的搜索及其命中记录
+	Delete(id uuid.UUID) error
+
+	// ListCandidates 列出可能命中悬赏令的未暂停搜索：不属于发布者，且检索词为空或与悬赏令匹配
+	ListCandidates(bountyID, ownerID uuid.UUID) ([]*dao.SavedSearch, error)
+	// Matches 判断悬赏令是否满足筛选条件且对 viewerID 公开列出
+	Matches(bountyID, viewerID uuid.UUID, filter *BountyFilter) (bool, error)
+	// RecordMatch 记录一次命中；已记录过时返回 false
+	RecordMatch(m *dao.SavedSearchMatch) (bool, error)
+	// ListMatches 按命中时间倒序列出命中记录，只含对 viewerID 仍可见的悬赏令
+	ListMatches(savedSearchID, viewerID uuid.UUID, page pagination.Page) (*pagination.Result[*dao.SavedSearchMatch], error)
+
+	// ListDigestDue 列出按天汇总、未暂停、有待通知的命中且上次通知早于 before 的搜索
+	ListDigestDue(before time.Time, limit int) ([]*dao.SavedSearch, error)
+	// MarkNotified 将搜索的待通知命中标记为已通知并更新通知时间，返回标记的条数
+	MarkNotified(savedSearchID uuid.UUID, at time.Time) (int64, error)
+}
+
+type savedSearchRepo struct {
+	db *gorm.DB
+}
+
+// NewSavedSearchRepo 构造函数
+func NewSavedSearchRepo(db *gorm.DB) SavedSearchRepo {
+	return &savedSearchRepo{db: db}
+}
+
+func (r *savedSearchRepo) Create(s *dao.SavedSearch) error {
+	return r.db.Create(s).Error
+}
+
+func (r *savedSearchRepo) GetByID(id uuid.UUID) (*dao.SavedSearch, error) {
+	var s dao.SavedSearch
+	if err := r.db.First(&s, "id = ?", id).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrSavedSearchNotFound
+		}
+		return nil, err
+	}
+	return &s, nil
+}
+
+func (r *savedSearchRepo) CountByUser(userID uuid.UUID) (int64, error) {
+	var n int64
+	err := r.db.Model(&dao.SavedSearch{}).Where("user_id = ?", userID).Count(&n).Error
+	return n, err
+}
+
+func (r *savedSearchRepo) ListByUser(userID uuid.UUID, page pagination.Page) (*pagination.Result[*dao.SavedSearch], error) {
+	q := r.db.Model(&dao.SavedSearch{}).Where("user_id = ?", userID)
+	return pagination.Keyset[*dao.SavedSearch](q, page, "saved_searches", pagination.NewestFirst)
+}
+
+func (r *savedSearchRepo) Update(s *dao.SavedSearch) error {
+	return r.db.Save(s).Error
+}
+
+func (r *savedSearchRepo) Delete(id uuid.UUID) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("saved_search_id = ?", id).Delete(&dao.SavedSearchMatch{}).Error; err != nil {
+			return err
+		}
+		res := tx.Delete(&dao.SavedSearch{}, "id = ?", id)
+		if res.Error != nil {
+			return res.Error
+		}
+		if res.RowsAffected == 0 {
+			return ErrSavedSearchNotFound
+		}
+		return nil
+	})
+}
+
+func (r *savedSearchRepo) ListCandidates(bountyID, ownerID uuid.UUID) ([]*dao.SavedSearch, error) {
+	text := r.db.Model(&dao.Bounty{}).
+		Select("1").
+		Where("bounties.id = ? AND bounties.search_vector @@ to_tsquery('simple', saved_searches.ts_query)", bountyID)
+	var list []*dao.SavedSearch
+	err := r.db.
+		Where("paused = ? AND user_id <> ?", false, ownerID).
+		Where("ts_query = '' OR EXISTS (?)", text).
+		Find(&list).Error
+	return list, err
+}
+
+func (r *savedSearchRepo) Matches(bountyID, viewerID uuid.UUID, filter *BountyFilter) (bool, error) {
+	var n int64
+	err := r.db.Model(&dao.Bounty{}).
+		Scopes(visibleTo(r.db, viewerID, false), filterBounties(filter, "")).
+		Where("bounties.id = ? AND bounties.status <> ?", bountyID, dao.BountyStatusDraft).
+		Count(&n).Error
+	return n > 0, err
+}
+
+func (r *savedSearchRepo) RecordMatch(m *dao.SavedSearchMatch) (bool, error) {
+	res := r.db.Clauses(clause.OnConflict{DoNothing: true}).Create(m)
+	return res.RowsAffected > 0, res.Error
+}
+
+func (r *savedSearchRepo) ListMatches(savedSearchID, viewerID uuid.UUID, page pagination.Page) (*pagination.Result[*dao.SavedSearchMatch], error) {
+	visible := r.db.Model(&dao.Bounty{}).
+		Scopes(visibleTo(r.db, viewerID, false)).
+		Select("bounties.id")
+	q := r.db.Model(&dao.SavedSearchMatch{}).
+		Where("saved_search_id = ? AND bounty_id IN (?)", savedSearchID, visible)
+	return pagination.Keyset[*dao.SavedSearchMatch](q, page, "saved_search_matches", pagination.NewestFirst, func(db *gorm.DB) *gorm.DB {
+		return db.Preload("Bounty.Contributions", "status <> ?", dao.ContributionStatusRefunded)
+	})
+}
+
+func (r *savedSearchRepo) ListDigestDue(before time.Time, limit int) ([]*dao.SavedSearch, error) {
+	pending := r.db.Model(&dao.SavedSearchMatch{}).
+		Select("1").
+		Where("saved_search_matches.saved_search_id = saved_searches.id AND saved_search_matches.notified_at IS NULL")
+	var list []*dao.SavedSearch
+	err := r.db.
+		Where("delivery = ? AND paused = ?", dao.SavedSearchDeliveryDaily, false).
+		Where("last_notified_at IS NULL OR last_notified_at <= ?", before).
+		Where("EXISTS (?)", pending).
+		Order("last_notified_at ASC NULLS FIRST").
+		Limit(limit).
+		Find(&list).Error
+	return list, err
+}
+
+func (r *savedSearchRepo) MarkNotified(savedSearchID uuid.UUID, at time.Time) (int64, error) {
+	var n int64
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		res := tx.Model(&dao.SavedSearchMatch{}).
+			Where("saved_search_id = ? AND notified_at IS NULL", savedSearchID).
+			Update("notified_at", at)
+		if res.Error != nil {
+			return res.Error
+		}
+		n = res.RowsAffected
+		return tx.Model(&dao.SavedSearch{}).
+			Where("id = ?", savedSearchID).
+			Update("last_notified_at", at).Error
+	})
+	return n, err
+}
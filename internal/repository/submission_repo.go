@@ -0,0 +1,195 @@
+package repository
+
+import (
+	"errors"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"onepenny-server/internal/pagination"
+	"onepenny-server/model/dao"
+	"time"
+)
+
+var (
+	// ErrSubmissionNotFound 找不到交付物
+	ErrSubmissionNotFound = errors.New("submission not found")
+	// ErrSubmissionNotPending 交付物已审核过，或已有更新的版本
+	ErrSubmissionNotPending = errors.New("submission is not awaiting review")
+)
+
+// CreateSubmissionInput 提交交付物输入
+type CreateSubmissionInput struct {
+	BountyID       uuid.UUID
+	SubmitterID    uuid.UUID
+	Content        string
+	AttachmentURLs []string
+}
+
+// SubmissionRepo 定义交付物表的持久化接口
+type SubmissionRepo interface {
+	Create(input *CreateSubmissionInput) (*dao.Submission, error)
+	ListByBounty(bountyID uuid.UUID, page pagination.Page) (*pagination.Result[*dao.Submission], error)
+	Accept(bountyID, submissionID, ownerID uuid.UUID) (*dao.Submission, error)
+	RequestChanges(bountyID, submissionID, ownerID uuid.UUID, comment string) (*dao.Submission, error)
+}
+
+type submissionRepo struct {
+	db *gorm.DB
+}
+
+// NewSubmissionRepo 构造函数
+func NewSubmissionRepo(db *gorm.DB) SubmissionRepo {
+	return &submissionRepo{db: db}
+}
+
+// Create 接收者提交新版本的交付物，悬赏令由进行中进入待结算
+func (r *submissionRepo) Create(input *CreateSubmissionInput) (*dao.Submission, error) {
+	s := &dao.Submission{
+		BaseModel:      dao.BaseModel{ID: uuid.New()},
+		BountyID:       input.BountyID,
+		SubmitterID:    input.SubmitterID,
+		Content:        input.Content,
+		AttachmentURLs: input.AttachmentURLs,
+		Status:         dao.SubmissionStatusPending,
+	}
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		b, err := lockBounty(tx, input.BountyID)
+		if err != nil {
+			return err
+		}
+		if b.ReceiverID == nil || *b.ReceiverID != input.SubmitterID {
+			return ErrNotBountyReceiver
+		}
+		var n int64
+		if err := tx.Model(&dao.Milestone{}).Where("bounty_id = ?", b.ID).Count(&n).Error; err != nil {
+			return err
+		}
+		if n > 0 {
+			return ErrBountyHasMilestones
+		}
+
+		var last int
+		if err := tx.Model(&dao.Submission{}).
+			Where("bounty_id = ?", b.ID).
+			Select("COALESCE(MAX(version),0)").Scan(&last).Error; err != nil {
+			return err
+		}
+		s.Version = last + 1
+
+		// 进行中 → 待结算
+		if err := transitionBounty(tx, b, dao.BountyStatusPendingSettlement, &dao.BountyEvent{
+			ActorID: &input.SubmitterID,
+			Type:    dao.BountyEventSubmissionCreated,
+			Payload: map[string]interface{}{"submission_id": s.ID, "version": s.Version},
+		}); err != nil {
+			return err
+		}
+		if err := tx.Model(b).Update("settlement_requested_at", time.Now()).Error; err != nil {
+			return err
+		}
+		return tx.Create(s).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
+// ListByBounty 按版本先后列出悬赏令的全部交付物
+func (r *submissionRepo) ListByBounty(bountyID uuid.UUID, page pagination.Page) (*pagination.Result[*dao.Submission], error) {
+	q := r.db.Model(&dao.Submission{}).Where("bounty_id = ?", bountyID)
+	return pagination.Keyset[*dao.Submission](q, page, "submissions", pagination.OldestFirst)
+}
+
+// Accept 发布者通过交付物，悬赏令进入已结算并发放托管赏金
+func (r *submissionRepo) Accept(bountyID, submissionID, ownerID uuid.UUID) (*dao.Submission, error) {
+	var s dao.Submission
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		b, err := lockPendingSubmission(tx, &s, bountyID, submissionID, ownerID)
+		if err != nil {
+			return err
+		}
+		// 待结算 → 已结算，同时将该交付物标记为通过
+		if err := transitionBounty(tx, b, dao.BountyStatusSettled, &dao.BountyEvent{
+			ActorID: &ownerID,
+			Type:    dao.BountyEventSubmissionAccepted,
+			Payload: map[string]interface{}{"submission_id": s.ID, "version": s.Version, "receiver_id": b.ReceiverID},
+		}); err != nil {
+			return err
+		}
+		return tx.First(&s, "id = ?", s.ID).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &s, nil
+}
+
+// RequestChanges 发布者要求修改交付物，悬赏令退回进行中
+func (r *submissionRepo) RequestChanges(bountyID, submissionID, ownerID uuid.UUID, comment string) (*dao.Submission, error) {
+	var s dao.Submission
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		b, err := lockPendingSubmission(tx, &s, bountyID, submissionID, ownerID)
+		if err != nil {
+			return err
+		}
+		// 待结算 → 进行中
+		if err := transitionBounty(tx, b, dao.BountyStatusInProgress, &dao.BountyEvent{
+			ActorID: &ownerID,
+			Type:    dao.BountyEventChangesRequested,
+			Payload: map[string]interface{}{"submission_id": s.ID, "version": s.Version, "comment": comment},
+		}); err != nil {
+			return err
+		}
+		if err := tx.Model(b).Update("settlement_requested_at", nil).Error; err != nil {
+			return err
+		}
+		now := time.Now()
+		s.Status = dao.SubmissionStatusChangesRequested
+		s.ReviewComment = comment
+		s.ReviewedAt = &now
+		return tx.Model(&s).Updates(map[string]interface{}{
+			"status":         s.Status,
+			"review_comment": comment,
+			"reviewed_at":    now,
+		}).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &s, nil
+}
+
+// lockBounty 以 FOR UPDATE 锁定悬赏令
+func lockBounty(tx *gorm.DB, id uuid.UUID) (*dao.Bounty, error) {
+	var b dao.Bounty
+	if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).First(&b, "id = ?", id).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrBountyNotFound
+		}
+		return nil, err
+	}
+	return &b, nil
+}
+
+// lockPendingSubmission 锁定悬赏令并取出待审核的交付物，校验操作者为发布者
+func lockPendingSubmission(tx *gorm.DB, s *dao.Submission, bountyID, submissionID, ownerID uuid.UUID) (*dao.Bounty, error) {
+	b, err := lockBounty(tx, bountyID)
+	if err != nil {
+		return nil, err
+	}
+	if b.UserID != ownerID {
+		return nil, ErrNotBountyOwner
+	}
+	if err := tx.First(s, "id = ? AND bounty_id = ?", submissionID, bountyID).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrSubmissionNotFound
+		}
+		return nil, err
+	}
+	// 争议期间交付物由仲裁处理，发布者不能直接审核
+	if s.Status != dao.SubmissionStatusPending || b.Status != dao.BountyStatusPendingSettlement {
+		return nil, ErrSubmissionNotPending
+	}
+	return b, nil
+}
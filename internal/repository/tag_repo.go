@@ -0,0 +1,254 @@
+package repository
+
+import (
+	"errors"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"onepenny-server/internal/pagination"
+	"onepenny-server/model/dao"
+)
+
+var (
+	// ErrTagNotFound 找不到对应的标签
+	ErrTagNotFound = errors.New("tag not found")
+	// ErrTagAliasTaken 别名已属于另一个标签
+	ErrTagAliasTaken = errors.New("alias already belongs to another tag")
+)
+
+// TagRepo 定义标签、别名与关注的持久化接口。标签以归并键（Key）或别名查找，悬赏令中保存其 Slug
+type TagRepo interface {
+	// Resolve 按归并键查找标签，别名同样生效；返回归并键到标签的映射，找不到的键不出现在结果中
+	Resolve(keys []string) (map[string]*dao.Tag, error)
+	// FirstOrCreate 按归并键查找标签，不存在时创建；并发创建同一标签时返回先创建的记录
+	FirstOrCreate(t *dao.Tag) (*dao.Tag, error)
+	// GetBySlug 按规范写法查找标签，并预加载别名
+	GetBySlug(slug string) (*dao.Tag, error)
+	// Autocomplete 按归并键或别名的前缀匹配标签，使用次数多的在前
+	Autocomplete(prefix string, page pagination.Page) (*pagination.Result[*dao.Tag], error)
+	// Recount 按未删除的悬赏令重新统计标签的使用次数
+	Recount(slugs []string) error
+	// AddAlias 为标签添加别名。别名恰为另一个标签的归并键时将其合并：
+	// 悬赏令、模板与周期悬赏中的标签改写为目标标签，关注者与别名一并转移
+	AddAlias(tagID uuid.UUID, alias string) error
+
+	// Follow 关注标签，重复关注不报错
+	Follow(userID, tagID uuid.UUID) error
+	Unfollow(userID, tagID uuid.UUID) error
+	ListFollowing(userID uuid.UUID, page pagination.Page) (*pagination.Result[*dao.TagFollow], error)
+	// ListFollowerIDs 关注了任一标签的用户，不含 excludeID
+	ListFollowerIDs(slugs []string, excludeID uuid.UUID) ([]uuid.UUID, error)
+}
+
+type tagRepo struct {
+	db *gorm.DB
+}
+
+// NewTagRepo 构造函数
+func NewTagRepo(db *gorm.DB) TagRepo {
+	return &tagRepo{db: db}
+}
+
+func (r *tagRepo) Resolve(keys []string) (map[string]*dao.Tag, error) {
+	res := map[string]*dao.Tag{}
+	if len(keys) == 0 {
+		return res, nil
+	}
+	var tags []*dao.Tag
+	if err := r.db.Where("key IN ?", keys).Find(&tags).Error; err != nil {
+		return nil, err
+	}
+	for _, t := range tags {
+		res[t.Key] = t
+	}
+
+	var missing []string
+	for _, k := range keys {
+		if res[k] == nil {
+			missing = append(missing, k)
+		}
+	}
+	if len(missing) == 0 {
+		return res, nil
+	}
+	var aliases []dao.TagAlias
+	if err := r.db.Where("alias IN ?", missing).Find(&aliases).Error; err != nil {
+		return nil, err
+	}
+	if len(aliases) == 0 {
+		return res, nil
+	}
+	ids := make([]uuid.UUID, 0, len(aliases))
+	for _, a := range aliases {
+		ids = append(ids, a.TagID)
+	}
+	var targets []*dao.Tag
+	if err := r.db.Where("id IN ?", ids).Find(&targets).Error; err != nil {
+		return nil, err
+	}
+	byID := make(map[uuid.UUID]*dao.Tag, len(targets))
+	for _, t := range targets {
+		byID[t.ID] = t
+	}
+	for _, a := range aliases {
+		if t := byID[a.TagID]; t != nil {
+			res[a.Alias] = t
+		}
+	}
+	return res, nil
+}
+
+func (r *tagRepo) FirstOrCreate(t *dao.Tag) (*dao.Tag, error) {
+	if err := r.db.Clauses(clause.OnConflict{DoNothing: true}).Create(t).Error; err != nil {
+		return nil, err
+	}
+	var got dao.Tag
+	if err := r.db.First(&got, "key = ?", t.Key).Error; err != nil {
+		return nil, err
+	}
+	return &got, nil
+}
+
+func (r *tagRepo) GetBySlug(slug string) (*dao.Tag, error) {
+	var t dao.Tag
+	if err := r.db.Preload("Aliases").First(&t, "slug = ?", slug).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrTagNotFound
+		}
+		return nil, err
+	}
+	return &t, nil
+}
+
+func (r *tagRepo) Autocomplete(prefix string, page pagination.Page) (*pagination.Result[*dao.Tag], error) {
+	q := r.db.Model(&dao.Tag{})
+	if prefix != "" {
+		// 归并键只含字母、数字与 + #，无需转义 LIKE 通配符
+		aliased := r.db.Model(&dao.TagAlias{}).Select("tag_id").Where("alias LIKE ?", prefix+"%")
+		q = q.Where("tags.key LIKE ? OR tags.id IN (?)", prefix+"%", aliased)
+	}
+	// 按热度排序，以偏移翻页
+	return pagination.Offset[*dao.Tag](q, page, func(db *gorm.DB) *gorm.DB {
+		return db.Order("tags.usage_count DESC, tags.slug ASC")
+	})
+}
+
+func (r *tagRepo) Recount(slugs []string) error {
+	return recountTags(r.db, slugs)
+}
+
+// recountTags 在给定连接（可为事务）上重新统计标签的使用次数
+func recountTags(db *gorm.DB, slugs []string) error {
+	if len(slugs) == 0 {
+		return nil
+	}
+	return db.Model(&dao.Tag{}).
+		Where("slug IN ?", slugs).
+		Update("usage_count", gorm.Expr(
+			"(SELECT COUNT(*) FROM bounties WHERE bounties.deleted_at IS NULL AND tags.slug = ANY(bounties.tags))",
+		)).Error
+}
+
+func (r *tagRepo) AddAlias(tagID uuid.UUID, alias string) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		var target dao.Tag
+		if err := tx.First(&target, "id = ?", tagID).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return ErrTagNotFound
+			}
+			return err
+		}
+		if alias == target.Key {
+			return nil
+		}
+
+		var existing dao.TagAlias
+		err := tx.Where("alias = ?", alias).Take(&existing).Error
+		switch {
+		case err == nil && existing.TagID == tagID:
+			return nil
+		case err == nil:
+			return ErrTagAliasTaken
+		case !errors.Is(err, gorm.ErrRecordNotFound):
+			return err
+		}
+
+		var other dao.Tag
+		err = tx.Where("key = ?", alias).Take(&other).Error
+		switch {
+		case err == nil:
+			if err := mergeTag(tx, &other, &target); err != nil {
+				return err
+			}
+		case !errors.Is(err, gorm.ErrRecordNotFound):
+			return err
+		}
+		return tx.Create(&dao.TagAlias{TagID: tagID, Alias: alias}).Error
+	})
+}
+
+// mergeTag 将 from 合并进 into 后删除 from；同时带有两个标签的记录只保留 into
+func mergeTag(tx *gorm.DB, from, into *dao.Tag) error {
+	rewrite := gorm.Expr(
+		"CASE WHEN ? = ANY(tags) THEN array_remove(tags, ?) ELSE array_replace(tags, ?, ?) END",
+		into.Slug, from.Slug, from.Slug, into.Slug,
+	)
+	for _, m := range []interface{}{&dao.Bounty{}, &dao.BountyTemplate{}, &dao.BountySeries{}} {
+		if err := tx.Model(m).Where("? = ANY(tags)", from.Slug).Update("tags", rewrite).Error; err != nil {
+			return err
+		}
+	}
+
+	if err := tx.Exec(
+		`INSERT INTO tag_follows (id, created_at, updated_at, user_id, tag_id)
+		SELECT uuid_generate_v4(), created_at, NOW(), user_id, ? FROM tag_follows
+		WHERE tag_id = ? AND deleted_at IS NULL
+		ON CONFLICT DO NOTHING`,
+		into.ID, from.ID,
+	).Error; err != nil {
+		return err
+	}
+	if err := tx.Unscoped().Where("tag_id = ?", from.ID).Delete(&dao.TagFollow{}).Error; err != nil {
+		return err
+	}
+	if err := tx.Model(&dao.TagAlias{}).Where("tag_id = ?", from.ID).Update("tag_id", into.ID).Error; err != nil {
+		return err
+	}
+	// 归并键带唯一索引，需物理删除以便作为别名
+	if err := tx.Unscoped().Delete(&dao.Tag{}, "id = ?", from.ID).Error; err != nil {
+		return err
+	}
+	return recountTags(tx, []string{into.Slug})
+}
+
+func (r *tagRepo) Follow(userID, tagID uuid.UUID) error {
+	return r.db.Clauses(clause.OnConflict{DoNothing: true}).
+		Create(&dao.TagFollow{UserID: userID, TagID: tagID}).Error
+}
+
+func (r *tagRepo) Unfollow(userID, tagID uuid.UUID) error {
+	// 物理删除，以便再次关注时不与唯一索引冲突
+	return r.db.Unscoped().
+		Where("user_id = ? AND tag_id = ?", userID, tagID).
+		Delete(&dao.TagFollow{}).Error
+}
+
+func (r *tagRepo) ListFollowing(userID uuid.UUID, page pagination.Page) (*pagination.Result[*dao.TagFollow], error) {
+	q := r.db.Model(&dao.TagFollow{}).Where("user_id = ?", userID)
+	return pagination.Keyset[*dao.TagFollow](q, page, "tag_follows", pagination.NewestFirst, func(db *gorm.DB) *gorm.DB {
+		return db.Preload("Tag")
+	})
+}
+
+func (r *tagRepo) ListFollowerIDs(slugs []string, excludeID uuid.UUID) ([]uuid.UUID, error) {
+	var ids []uuid.UUID
+	if len(slugs) == 0 {
+		return ids, nil
+	}
+	err := r.db.Model(&dao.TagFollow{}).
+		Joins("JOIN tags ON tags.id = tag_follows.tag_id").
+		Where("tags.slug IN ? AND tag_follows.user_id <> ?", slugs, excludeID).
+		Distinct().
+		Pluck("tag_follows.user_id", &ids).Error
+	return ids, err
+}
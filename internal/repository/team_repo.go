@@ -4,6 +4,7 @@ import (
 	"errors"
 	"github.com/google/uuid"
 	"gorm.io/gorm"
+	"onepenny-server/internal/pagination"
 	"onepenny-server/model/dao"
 )
 
@@ -16,13 +17,15 @@ var (
 type TeamRepo interface {
 	Create(team *dao.Team) error
 	GetByID(id uuid.UUID) (*dao.Team, error)
-	ListByOwner(ownerID uuid.UUID, offset, limit int) ([]*dao.Team, error)
+	ListByOwner(ownerID uuid.UUID, page pagination.Page) (*pagination.Result[*dao.Team], error)
 	Update(team *dao.Team) error
 	Delete(id uuid.UUID) error
 
 	AddMember(teamID, userID uuid.UUID) error
 	RemoveMember(teamID, userID uuid.UUID) error
-	ListMembers(teamID uuid.UUID, offset, limit int) ([]*dao.User, error)
+	ListMembers(teamID uuid.UUID, page pagination.Page) (*pagination.Result[*dao.User], error)
+	// IsMember 判断用户是否为团队成员（团队创建者视为成员）
+	IsMember(teamID, userID uuid.UUID) (bool, error)
 }
 
 type teamRepo struct {
@@ -51,16 +54,9 @@ func (r *teamRepo) GetByID(id uuid.UUID) (*dao.Team, error) {
 	return &t, nil
 }
 
-func (r *teamRepo) ListByOwner(ownerID uuid.UUID, offset, limit int) ([]*dao.Team, error) {
-	var list []*dao.Team
-	if err := r.db.
-		Where("owner_id = ?", ownerID).
-		Offset(offset).
-		Limit(limit).
-		Find(&list).Error; err != nil {
-		return nil, err
-	}
-	return list, nil
+func (r *teamRepo) ListByOwner(ownerID uuid.UUID, page pagination.Page) (*pagination.Result[*dao.Team], error) {
+	q := r.db.Model(&dao.Team{}).Where("owner_id = ?", ownerID)
+	return pagination.Keyset[*dao.Team](q, page, "teams", pagination.NewestFirst)
 }
 
 func (r *teamRepo) Update(team *dao.Team) error {
@@ -88,23 +84,32 @@ func (r *teamRepo) RemoveMember(teamID, userID uuid.UUID) error {
 		Delete(&u)
 }
 
-func (r *teamRepo) ListMembers(teamID uuid.UUID, offset, limit int) ([]*dao.User, error) {
-	t := dao.Team{BaseModel: dao.BaseModel{ID: teamID}}
-	var members []*dao.User
-	if err := r.db.Model(&t).
-		Association("Members").
-		Find(&members); err != nil {
-		return nil, err
-	}
-	// 如果需要分页，可在查询前用 Raw SQL 或者在内存切片后截取
-	// 这里简单示例：按 slice 分页
-	start := offset
-	if start > len(members) {
-		return []*dao.User{}, nil
+func (r *teamRepo) ListMembers(teamID uuid.UUID, page pagination.Page) (*pagination.Result[*dao.User], error) {
+	q := r.db.Model(&dao.User{}).
+		Joins("JOIN team_members ON team_members.user_id = users.id").
+		Where("team_members.team_id = ?", teamID)
+	return pagination.Keyset[*dao.User](q, page, "users", pagination.OldestFirst)
+}
+
+func (r *teamRepo) IsMember(teamID, userID uuid.UUID) (bool, error) {
+	var count int64
+	if err := r.db.Model(&dao.Team{}).
+		Where("id = ? AND owner_id = ?", teamID, userID).
+		Count(&count).Error; err != nil || count > 0 {
+		return count > 0, err
 	}
-	end := start + limit
-	if end > len(members) {
-		end = len(members)
+	if err := r.db.Table("team_members").
+		Where("team_id = ? AND user_id = ?", teamID, userID).
+		Count(&count).Error; err != nil {
+		return false, err
 	}
-	return members[start:end], nil
+	return count > 0, nil
+}
+
+// userTeamIDs 用户创建或加入的团队 ID 子查询
+func userTeamIDs(db *gorm.DB, userID uuid.UUID) *gorm.DB {
+	return db.Raw(
+		"SELECT id FROM teams WHERE owner_id = ? AND deleted_at IS NULL UNION SELECT team_id FROM team_members WHERE user_id = ?",
+		userID, userID,
+	)
 }
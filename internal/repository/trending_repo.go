@@ -0,0 +1,134 @@
+package repository
+
+import (
+	"errors"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"onepenny-server/model/dao"
+	"time"
+)
+
+// 参与热度计算的互动类型
+const (
+	EngagementView        = "view"
+	EngagementLike        = "like"
+	EngagementComment     = "comment"
+	EngagementApplication = "application"
+)
+
+// TrendingScore 一个悬赏令的热度得分
+type TrendingScore struct {
+	ID    uuid.UUID
+	Score float64
+}
+
+// TrendingRankQuery 直接按数据库计算热度的条件
+type TrendingRankQuery struct {
+	ViewerID   uuid.UUID
+	Since      time.Time
+	HalfLife   time.Duration      // 互动的权重每经过该时长减半
+	Weights    map[string]float64 // 各互动类型的权重
+	Categories []string           // 为空时不限
+	Limit      int
+}
+
+// TrendingRepo 定义热度榜所需的查询。上榜的悬赏令须对查看者公开列出，且仍在招募或进行中
+type TrendingRepo interface {
+	// GetCategory 返回悬赏令的分类，不存在时返回 ErrBountyNotFound
+	GetCategory(bountyID uuid.UUID) (string, error)
+	// FilterTrendable 返回 ids 中可以上榜的部分，顺序不定
+	FilterTrendable(viewerID uuid.UUID, ids []uuid.UUID) ([]uuid.UUID, error)
+	// GetBounties 按 ID 批量读取悬赏令，顺序不定
+	GetBounties(ids []uuid.UUID) ([]*dao.Bounty, error)
+	// Rank 按时间衰减后的加权互动数计算热度，得分高的在前
+	Rank(q *TrendingRankQuery) ([]TrendingScore, error)
+}
+
+type trendingRepo struct {
+	db *gorm.DB
+}
+
+// NewTrendingRepo 构造函数
+func NewTrendingRepo(db *gorm.DB) TrendingRepo {
+	return &trendingRepo{db: db}
+}
+
+func (r *trendingRepo) GetCategory(bountyID uuid.UUID) (string, error) {
+	var b dao.Bounty
+	if err := r.db.Select("id, category").First(&b, "id = ?", bountyID).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return "", ErrBountyNotFound
+		}
+		return "", err
+	}
+	return b.Category, nil
+}
+
+// trendable 可以上榜的悬赏令
+func (r *trendingRepo) trendable(q *gorm.DB, viewerID uuid.UUID) *gorm.DB {
+	return q.
+		Scopes(visibleTo(r.db, viewerID, false)).
+		Where("bounties.status IN ?", []dao.BountyStatus{dao.BountyStatusCreated, dao.BountyStatusInProgress})
+}
+
+func (r *trendingRepo) FilterTrendable(viewerID uuid.UUID, ids []uuid.UUID) ([]uuid.UUID, error) {
+	var out []uuid.UUID
+	if len(ids) == 0 {
+		return out, nil
+	}
+	err := r.trendable(r.db.Model(&dao.Bounty{}), viewerID).
+		Where("bounties.id IN ?", ids).
+		Pluck("bounties.id", &out).Error
+	return out, err
+}
+
+func (r *trendingRepo) GetBounties(ids []uuid.UUID) ([]*dao.Bounty, error) {
+	var list []*dao.Bounty
+	if len(ids) == 0 {
+		return list, nil
+	}
+	err := r.db.
+		Preload("Contributions", "status <> ?", dao.ContributionStatusRefunded).
+		Where("id IN ?", ids).
+		Find(&list).Error
+	return list, err
+}
+
+func (r *trendingRepo) Rank(q *TrendingRankQuery) ([]TrendingScore, error) {
+	events := r.db.Raw(`
+		SELECT bounty_id, ? AS kind, created_at FROM bounty_views WHERE created_at >= ? AND deleted_at IS NULL
+		UNION ALL
+		SELECT likeable_id, ?, created_at FROM likes WHERE likeable_type = 'bounty' AND created_at >= ? AND deleted_at IS NULL
+		UNION ALL
+		SELECT bounty_id, ?, created_at FROM comments WHERE created_at >= ? AND deleted_at IS NULL
+		UNION ALL
+		SELECT bounty_id, ?, created_at FROM applications WHERE created_at >= ? AND deleted_at IS NULL`,
+		EngagementView, q.Since,
+		EngagementLike, q.Since,
+		EngagementComment, q.Since,
+		EngagementApplication, q.Since,
+	)
+	score := gorm.Expr(
+		"SUM(CASE e.kind WHEN ? THEN ? WHEN ? THEN ? WHEN ? THEN ? WHEN ? THEN ? ELSE 0 END"+
+			" * POWER(0.5, EXTRACT(EPOCH FROM (NOW() - e.created_at)) / ?)) AS score",
+		EngagementView, q.Weights[EngagementView],
+		EngagementLike, q.Weights[EngagementLike],
+		EngagementComment, q.Weights[EngagementComment],
+		EngagementApplication, q.Weights[EngagementApplication],
+		q.HalfLife.Seconds(),
+	)
+
+	tx := r.trendable(r.db.Table("(?) AS e", events), q.ViewerID).
+		Joins("JOIN bounties ON bounties.id = e.bounty_id AND bounties.deleted_at IS NULL")
+	if len(q.Categories) > 0 {
+		tx = tx.Where("bounties.category IN ?", q.Categories)
+	}
+	var scores []TrendingScore
+	err := tx.
+		Select("e.bounty_id AS id, ?", score).
+		Group("e.bounty_id").
+		Order("score DESC, e.bounty_id").
+		Limit(q.Limit).
+		Scan(&scores).Error
+	return scores, err
+}
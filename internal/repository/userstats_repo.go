@@ -4,6 +4,7 @@ import (
 	"errors"
 	"github.com/google/uuid"
 	"gorm.io/gorm"
+	"onepenny-server/internal/pagination"
 	"onepenny-server/model/dao"
 	"time"
 )
@@ -12,11 +13,12 @@ var ErrNoViewHistory = errors.New("view history not enabled")
 
 // UserStatsRepo 定义获取用户统计数据所需的所有 DB 操作
 type UserStatsRepo interface {
-	ListBountiesByUserAndStatus(userID uuid.UUID, status string, offset, limit int) ([]dao.Bounty, error)
-	ListApplicationsForBounty(ownerID, bountyID uuid.UUID, offset, limit int) ([]dao.Application, error)
+	ListBountiesByUserAndStatus(userID uuid.UUID, status string, page pagination.Page) (*pagination.Result[dao.Bounty], error)
+	ListApplicationsForBounty(ownerID, bountyID uuid.UUID, page pagination.Page) (*pagination.Result[dao.Application], error)
 	SumEarnedByUser(userID uuid.UUID) (float64, error)
-	ListLikedBounties(userID uuid.UUID, offset, limit int) ([]dao.Bounty, error)
-	ListViewedBounties(userID uuid.UUID, offset, limit int) ([]dao.Bounty, error)
+	SumContributedByUser(userID uuid.UUID) (map[string]float64, error)
+	ListLikedBounties(userID uuid.UUID, page pagination.Page) (*pagination.Result[dao.Bounty], error)
+	ListViewedBounties(userID uuid.UUID, page pagination.Page) (*pagination.Result[dao.Bounty], error)
 
 	CountApplicationsByUser(userID uuid.UUID) (int64, error)
 	CountCommentsByUser(userID uuid.UUID) (int64, error)
@@ -55,7 +57,7 @@ func (r *userStatsRepo) AvgCompletionTimeByUser(userID uuid.UUID) (time.Duration
 	var seconds float64
 	err := r.db.
 		Model(&dao.Bounty{}).
-		Where("owner_id = ? AND status = ?", userID, dao.BountyStatusCompleted).
+		Where("user_id = ? AND status = ?", userID, dao.BountyStatusSettled).
 		Select("AVG(EXTRACT(EPOCH FROM (updated_at - created_at)))").
 		Scan(&seconds).Error
 	if err != nil {
@@ -73,7 +75,7 @@ func (r *userStatsRepo) CountTasksByCategory(userID uuid.UUID) (map[string]int64
 	var rows []row
 	err := r.db.
 		Model(&dao.Bounty{}).
-		Where("owner_id = ?", userID).
+		Where("user_id = ?", userID).
 		Select("category, COUNT(*) AS count").
 		Group("category").
 		Scan(&rows).Error
@@ -96,7 +98,7 @@ func (r *userStatsRepo) CountTasksByDifficulty(userID uuid.UUID) (map[string]int
 	var rows []row
 	err := r.db.
 		Model(&dao.Bounty{}).
-		Where("owner_id = ?", userID).
+		Where("user_id = ?", userID).
 		Select("difficulty_level AS difficulty, COUNT(*) AS count").
 		Group("difficulty_level").
 		Scan(&rows).Error
@@ -114,62 +116,113 @@ func NewUserStatsRepo(db *gorm.DB) UserStatsRepo {
 	return &userStatsRepo{db: db}
 }
 
-func (r *userStatsRepo) ListBountiesByUserAndStatus(userID uuid.UUID, status string, offset, limit int) ([]dao.Bounty, error) {
-	var list []dao.Bounty
-	err := r.db.
-		Where("owner_id = ? AND status = ?", userID, status).
-		Offset(offset).Limit(limit).
-		Find(&list).Error
-	return list, err
+func (r *userStatsRepo) ListBountiesByUserAndStatus(userID uuid.UUID, status string, page pagination.Page) (*pagination.Result[dao.Bounty], error) {
+	q := r.db.Model(&dao.Bounty{}).Where("user_id = ? AND status = ?", userID, status)
+	return pagination.Keyset[dao.Bounty](q, page, "bounties", pagination.NewestFirst)
 }
 
-func (r *userStatsRepo) ListApplicationsForBounty(ownerID, bountyID uuid.UUID, offset, limit int) ([]dao.Application, error) {
+func (r *userStatsRepo) ListApplicationsForBounty(ownerID, bountyID uuid.UUID, page pagination.Page) (*pagination.Result[dao.Application], error) {
 	var bounty dao.Bounty
 	if err := r.db.
-		Where("id = ? AND owner_id = ?", bountyID, ownerID).
+		Where("id = ? AND user_id = ?", bountyID, ownerID).
 		First(&bounty).Error; err != nil {
 		return nil, err
 	}
 
-	var apps []dao.Application
-	err := r.db.
-		Where("bounty_id = ?", bountyID).
-		Offset(offset).Limit(limit).
-		Find(&apps).Error
-	return apps, err
+	q := r.db.Model(&dao.Application{}).Where("bounty_id = ?", bountyID)
+	return pagination.Keyset[dao.Application](q, page, "applications", pagination.OldestFirst)
 }
 
+// SumEarnedByUser 以账本为准，累计托管赏金发放到该用户钱包的金额（含里程碑的部分结算）
 func (r *userStatsRepo) SumEarnedByUser(userID uuid.UUID) (float64, error) {
-	// 假设已结算的悬赏状态是 "Settled" 且 receiver_id 存放实际领赏者
 	var total float64
 	err := r.db.
-		Model(&dao.Bounty{}).
-		Where("receiver_id = ? AND status = ?", userID, dao.BountyStatusCompleted).
-		Select("COALESCE(SUM(reward),0)").Scan(&total).Error
+		Model(&dao.LedgerEntry{}).
+		Joins("JOIN wallets ON wallets.id = ledger_entries.wallet_id").
+		Joins("JOIN ledger_transactions ON ledger_transactions.id = ledger_entries.transaction_id").
+		Where("wallets.owner_type = ? AND wallets.owner_id = ?", dao.WalletOwnerUser, userID).
+		Where("ledger_transactions.type = ? AND ledger_entries.amount > 0", dao.LedgerTxEscrowRelease).
+		Select("COALESCE(SUM(ledger_entries.amount),0)").Scan(&total).Error
 	return total, err
 }
 
-func (r *userStatsRepo) ListLikedBounties(userID uuid.UUID, offset, limit int) ([]dao.Bounty, error) {
-	// 通过 likes 表 join bounties
-	var list []dao.Bounty
+// SumContributedByUser 按币种累计用户为他人悬赏令追加的金额，已退回的不计入
+func (r *userStatsRepo) SumContributedByUser(userID uuid.UUID) (map[string]float64, error) {
+	type row struct {
+		Currency string
+		Total    float64
+	}
+	var rows []row
 	err := r.db.
+		Model(&dao.Contribution{}).
+		Where("user_id = ? AND status <> ?", userID, dao.ContributionStatusRefunded).
+		Select("currency, SUM(amount) AS total").
+		Group("currency").
+		Scan(&rows).Error
+	if err != nil {
+		return nil, err
+	}
+	m := make(map[string]float64, len(rows))
+	for _, r := range rows {
+		m[r.Currency] = r.Total
+	}
+	return m, nil
+}
+
+// ListLikedBounties 按点赞时间倒序列出；排序键不是悬赏令自身的创建时间，以偏移翻页
+func (r *userStatsRepo) ListLikedBounties(userID uuid.UUID, page pagination.Page) (*pagination.Result[dao.Bounty], error) {
+	skip, err := page.Skip()
+	if err != nil {
+		return nil, err
+	}
+	// 通过 likes 表 join bounties
+	q := r.db.
 		Model(&dao.Like{}).
-		Select("bounties.*").
 		Joins("join bounties on bounties.id = likes.bounty_id").
-		Where("likes.user_id = ?", userID).
-		Offset(offset).Limit(limit).
-		Scan(&list).Error
-	return list, err
-}
+		Where("likes.user_id = ?", userID)
+	total, err := pagination.Total(q, page)
+	if err != nil {
+		return nil, err
+	}
 
-func (r *userStatsRepo) ListViewedBounties(userID uuid.UUID, offset, limit int) ([]dao.Bounty, error) {
 	var list []dao.Bounty
-	err := r.db.
-		Model(&dao.BountyView{}).
+	if err := q.
 		Select("bounties.*").
+		Order("likes.created_at DESC, likes.id DESC").
+		Offset(skip).Limit(page.Limit()).
+		Scan(&list).Error; err != nil {
+		return nil, err
+	}
+	res := pagination.Slice(list, page)
+	res.Total = total
+	return res, nil
+}
+
+// ListViewedBounties 按最近一次浏览时间倒序列出，多次浏览的悬赏令只出现一次，以偏移翻页
+func (r *userStatsRepo) ListViewedBounties(userID uuid.UUID, page pagination.Page) (*pagination.Result[dao.Bounty], error) {
+	skip, err := page.Skip()
+	if err != nil {
+		return nil, err
+	}
+	q := r.db.
+		Model(&dao.BountyView{}).
 		Joins("JOIN bounties ON bounties.id = bounty_views.bounty_id").
-		Where("bounty_views.user_id = ?", userID).
-		Offset(offset).Limit(limit).
-		Scan(&list).Error
-	return list, err
+		Where("bounty_views.user_id = ?", userID)
+	total, err := pagination.Total(q.Session(&gorm.Session{}).Distinct("bounty_views.bounty_id"), page)
+	if err != nil {
+		return nil, err
+	}
+
+	var list []dao.Bounty
+	if err := q.
+		Select("bounties.*").
+		Group("bounties.id").
+		Order("MAX(bounty_views.viewed_at) DESC, bounties.id DESC").
+		Offset(skip).Limit(page.Limit()).
+		Scan(&list).Error; err != nil {
+		return nil, err
+	}
+	res := pagination.Slice(list, page)
+	res.Total = total
+	return res, nil
 }
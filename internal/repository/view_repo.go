@@ -0,0 +1,98 @@
+package repository
+
+import (
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"onepenny-server/model/dao"
+	"time"
+)
+
+// 浏览统计的分桶粒度
+const (
+	ViewIntervalHour = "hour"
+	ViewIntervalDay  = "day"
+)
+
+// ViewBucket 一个时间桶内的浏览数与独立访客数
+type ViewBucket struct {
+	Start         time.Time
+	Views         int64
+	UniqueViewers int64
+}
+
+// ViewStats 悬赏令在一段时间内的浏览统计；Buckets 只含有浏览的桶，按时间升序
+type ViewStats struct {
+	Views         int64
+	UniqueViewers int64
+	Buckets       []ViewBucket
+}
+
+// ViewRepo 定义浏览记录的持久化接口
+type ViewRepo interface {
+	// HasViewedSince 用户在 since 之后是否浏览过悬赏令，供没有 Redis 时去重
+	HasViewedSince(bountyID, userID uuid.UUID, since time.Time) (bool, error)
+	// Record 批量写入浏览记录，并在同一事务中累加各悬赏令的浏览数
+	Record(views []*dao.BountyView) error
+	// Stats 按 interval（UTC）分桶统计悬赏令在 [from, to) 内的浏览
+	Stats(bountyID uuid.UUID, from, to time.Time, interval string) (*ViewStats, error)
+}
+
+type viewRepo struct {
+	db *gorm.DB
+}
+
+// NewViewRepo 构造函数
+func NewViewRepo(db *gorm.DB) ViewRepo {
+	return &viewRepo{db: db}
+}
+
+func (r *viewRepo) HasViewedSince(bountyID, userID uuid.UUID, since time.Time) (bool, error) {
+	var n int64
+	err := r.db.Model(&dao.BountyView{}).
+		Where("bounty_id = ? AND user_id = ? AND viewed_at >= ?", bountyID, userID, since).
+		Limit(1).
+		Count(&n).Error
+	return n > 0, err
+}
+
+func (r *viewRepo) Record(views []*dao.BountyView) error {
+	if len(views) == 0 {
+		return nil
+	}
+	counts := map[uuid.UUID]int64{}
+	for _, v := range views {
+		counts[v.BountyID]++
+	}
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.CreateInBatches(views, 500).Error; err != nil {
+			return err
+		}
+		for id, n := range counts {
+			if err := tx.Model(&dao.Bounty{}).
+				Where("id = ?", id).
+				UpdateColumn("view_count", gorm.Expr("view_count + ?", n)).Error; err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+func (r *viewRepo) Stats(bountyID uuid.UUID, from, to time.Time, interval string) (*ViewStats, error) {
+	q := r.db.Model(&dao.BountyView{}).
+		Where("bounty_id = ? AND viewed_at >= ? AND viewed_at < ?", bountyID, from, to)
+
+	stats := &ViewStats{}
+	if err := q.Session(&gorm.Session{}).
+		Select("COUNT(*) AS views, COUNT(DISTINCT user_id) AS unique_viewers").
+		Scan(stats).Error; err != nil {
+		return nil, err
+	}
+	bucket := "date_trunc(?, viewed_at AT TIME ZONE 'UTC')"
+	err := q.Session(&gorm.Session{}).
+		Select(bucket+" AS start, COUNT(*) AS views, COUNT(DISTINCT user_id) AS unique_viewers", interval).
+		Group("start").
+		Order("start").
+		Scan(&stats.Buckets).Error
+	return stats, err
+}
@@ -0,0 +1,93 @@
+package repository
+
+import (
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"onepenny-server/internal/pagination"
+	"onepenny-server/model/dao"
+)
+
+// WatchRepo 定义悬赏令关注的持久化接口
+type WatchRepo interface {
+	// Watch 关注悬赏令，已关注时不做修改；返回是否新增了关注
+	Watch(bountyID, userID uuid.UUID) (bool, error)
+	// Unwatch 取消关注，未关注时不报错
+	Unwatch(bountyID, userID uuid.UUID) error
+	IsWatching(bountyID, userID uuid.UUID) (bool, error)
+	// ListWatching 按关注时间倒序列出用户关注的悬赏令，只含对其仍可见的
+	ListWatching(userID uuid.UUID, page pagination.Page) (*pagination.Result[*dao.BountyWatch], error)
+	// ListWatcherIDs 列出悬赏令的全部关注者
+	ListWatcherIDs(bountyID uuid.UUID) ([]uuid.UUID, error)
+}
+
+type watchRepo struct {
+	db *gorm.DB
+}
+
+// NewWatchRepo 构造函数
+func NewWatchRepo(db *gorm.DB) WatchRepo {
+	return &watchRepo{db: db}
+}
+
+func (r *watchRepo) Watch(bountyID, userID uuid.UUID) (bool, error) {
+	created := false
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		res := tx.Clauses(clause.OnConflict{DoNothing: true}).
+			Create(&dao.BountyWatch{BountyID: bountyID, UserID: userID})
+		if res.Error != nil || res.RowsAffected == 0 {
+			return res.Error
+		}
+		created = true
+		return recountWatchers(tx, bountyID)
+	})
+	return created, err
+}
+
+func (r *watchRepo) Unwatch(bountyID, userID uuid.UUID) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		// 硬删除，以便之后可以重新关注
+		res := tx.Unscoped().
+			Where("bounty_id = ? AND user_id = ?", bountyID, userID).
+			Delete(&dao.BountyWatch{})
+		if res.Error != nil || res.RowsAffected == 0 {
+			return res.Error
+		}
+		return recountWatchers(tx, bountyID)
+	})
+}
+
+// recountWatchers 按关注记录重新统计悬赏令的关注人数
+func recountWatchers(tx *gorm.DB, bountyID uuid.UUID) error {
+	count := tx.Model(&dao.BountyWatch{}).Select("COUNT(*)").Where("bounty_id = ?", bountyID)
+	return tx.Model(&dao.Bounty{}).
+		Where("id = ?", bountyID).
+		UpdateColumn("watcher_count", count).Error
+}
+
+func (r *watchRepo) IsWatching(bountyID, userID uuid.UUID) (bool, error) {
+	var n int64
+	err := r.db.Model(&dao.BountyWatch{}).
+		Where("bounty_id = ? AND user_id = ?", bountyID, userID).
+		Count(&n).Error
+	return n > 0, err
+}
+
+func (r *watchRepo) ListWatching(userID uuid.UUID, page pagination.Page) (*pagination.Result[*dao.BountyWatch], error) {
+	visible := r.db.Model(&dao.Bounty{}).
+		Scopes(visibleTo(r.db, userID, true)).
+		Select("bounties.id")
+	q := r.db.Model(&dao.BountyWatch{}).
+		Where("user_id = ? AND bounty_id IN (?)", userID, visible)
+	return pagination.Keyset[*dao.BountyWatch](q, page, "bounty_watches", pagination.NewestFirst, func(db *gorm.DB) *gorm.DB {
+		return db.Preload("Bounty.Contributions", "status <> ?", dao.ContributionStatusRefunded)
+	})
+}
+
+func (r *watchRepo) ListWatcherIDs(bountyID uuid.UUID) ([]uuid.UUID, error) {
+	var ids []uuid.UUID
+	err := r.db.Model(&dao.BountyWatch{}).
+		Where("bounty_id = ?", bountyID).
+		Pluck("user_id", &ids).Error
+	return ids, err
+}
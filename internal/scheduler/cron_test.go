@@ -0,0 +1,77 @@
+package scheduler
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestCronScheduleNext(t *testing.T) {
+	at := func(s string) time.Time {
+		t.Helper()
+		v, err := time.Parse("2006-01-02 15:04", s)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return v
+	}
+	cases := []struct {
+		name string
+		expr string
+		from string
+		want string
+	}{
+		{"daily macro", "@daily", "2024-03-10 12:30", "2024-03-11 00:00"},
+		{"hourly macro", "@hourly", "2024-03-10 12:30", "2024-03-10 13:00"},
+		{"weekly macro", "@weekly", "2024-03-10 12:30", "2024-03-17 00:00"}, // 2024-03-10 为周日
+		{"macro is case-insensitive", "@Monthly", "2024-03-10 12:30", "2024-04-01 00:00"},
+		{"strictly after", "30 12 * * *", "2024-03-10 12:30", "2024-03-11 12:30"},
+		{"minute step", "*/15 * * * *", "2024-03-10 12:31", "2024-03-10 12:45"},
+		{"step from value", "5/20 * * * *", "2024-03-10 12:46", "2024-03-10 13:05"},
+		{"range with step", "0 9-17/4 * * *", "2024-03-10 13:00", "2024-03-10 17:00"},
+		{"range", "0 9 * * 1-5", "2024-03-09 10:00", "2024-03-11 09:00"},
+		{"list", "0 8,20 * * *", "2024-03-10 08:00", "2024-03-10 20:00"},
+		{"sunday as 7", "0 0 * * 7", "2024-03-11 00:00", "2024-03-17 00:00"},
+		{"sunday as 0", "0 0 * * 0", "2024-03-11 00:00", "2024-03-17 00:00"},
+		{"dom and dow union", "0 0 13 * 5", "2024-03-09 00:00", "2024-03-13 00:00"},
+		{"dom and dow union picks weekday", "0 0 13 * 5", "2024-03-13 00:00", "2024-03-15 00:00"},
+		{"stepped dow intersects dom", "0 0 1 * */2", "2024-01-01 00:00", "2024-02-01 00:00"},
+		{"stepped dom intersects dow", "0 0 */10 * 1", "2024-03-01 00:00", "2024-03-11 00:00"},
+		{"month rollover", "0 0 1 * *", "2024-01-31 23:59", "2024-02-01 00:00"},
+		{"year rollover", "0 0 1 1 *", "2024-12-31 23:59", "2025-01-01 00:00"},
+		{"skips short months", "0 0 31 * *", "2024-04-01 00:00", "2024-05-31 00:00"},
+		{"leap day", "0 0 29 2 *", "2024-03-01 00:00", "2028-02-29 00:00"},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			s, err := ParseCron(tc.expr)
+			if err != nil {
+				t.Fatalf("ParseCron(%q): %v", tc.expr, err)
+			}
+			if got, want := s.Next(at(tc.from)), at(tc.want); !got.Equal(want) {
+				t.Errorf("Next(%s) = %s, want %s", tc.from, got.Format("2006-01-02 15:04"), tc.want)
+			}
+		})
+	}
+}
+
+func TestParseCronInvalid(t *testing.T) {
+	for _, expr := range []string{
+		"",
+		"* * * *",
+		"* * * * * *",
+		"60 * * * *",
+		"* 24 * * *",
+		"* * 0 * *",
+		"* * * 13 *",
+		"* * * * 8",
+		"5-1 * * * *",
+		"*/0 * * * *",
+		"a * * * *",
+		"@sometimes",
+	} {
+		if _, err := ParseCron(expr); !errors.Is(err, ErrInvalidCron) {
+			t.Errorf("ParseCron(%q) error = %v, want ErrInvalidCron", expr, err)
+		}
+	}
+}
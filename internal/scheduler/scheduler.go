@@ -0,0 +1,208 @@
+package scheduler
+
+import (
+	"context"
+	"fmt"
+	"github.com/go-redis/redis/v8"
+	"github.com/google/uuid"
+	"log"
+	"onepenny-server/internal/repository"
+	"onepenny-server/model/dao"
+	"os"
+	"sync"
+	"time"
+)
+
+// leaderKey 多实例部署时，只有持有该键的实例执行后台任务
+const leaderKey = "onepenny:scheduler:leader"
+
+// renewScript 仅当锁仍归自己所有时续期，避免误续他人的租约
+var renewScript = redis.NewScript(`
+if redis.call("GET", KEYS[1]) == ARGV[1] then
+	return redis.call("PEXPIRE", KEYS[1], ARGV[2])
+end
+return 0`)
+
+// releaseScript 仅当锁仍归自己所有时释放
+var releaseScript = redis.NewScript(`
+if redis.call("GET", KEYS[1]) == ARGV[1] then
+	return redis.call("DEL", KEYS[1])
+end
+return 0`)
+
+// Job 一个按固定间隔执行的后台任务，Run 返回本次处理的记录数
+type Job struct {
+	Name     string
+	Interval time.Duration
+	Run      func(ctx context.Context) (int, error)
+}
+
+// JobStatus 任务的当前状态，供管理接口展示
+type JobStatus struct {
+	Name     string
+	Interval time.Duration
+	LastRun  *dao.JobRun // 本实例最近一次执行记录，未执行过为 nil
+}
+
+// Status 调度器的当前状态
+type Status struct {
+	Instance string
+	IsLeader bool
+	Leader   string // 当前持有锁的实例，Redis 不可用时为空
+	Jobs     []JobStatus
+}
+
+// Scheduler 进程内的后台任务调度器，通过 Redis 租约选主，保证多实例下每个任务同一时刻只在一处执行
+type Scheduler struct {
+	rdb      *redis.Client
+	runRepo  repository.JobRunRepo
+	leaseTTL time.Duration
+	instance string
+
+	mu       sync.RWMutex
+	jobs     []Job
+	lastRuns map[string]*dao.JobRun
+	leader   bool
+}
+
+// New 构造调度器；rdb 为 nil 时视为单实例部署，本实例始终为 leader
+func New(rdb *redis.Client, runRepo repository.JobRunRepo, leaseTTL time.Duration) *Scheduler {
+	host, _ := os.Hostname()
+	return &Scheduler{
+		rdb:      rdb,
+		runRepo:  runRepo,
+		leaseTTL: leaseTTL,
+		instance: fmt.Sprintf("%s-%d-%s", host, os.Getpid(), uuid.NewString()[:8]),
+		lastRuns: map[string]*dao.JobRun{},
+		leader:   rdb == nil,
+	}
+}
+
+// Register 注册任务，须在 Start 之前调用
+func (s *Scheduler) Register(job Job) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.jobs = append(s.jobs, job)
+}
+
+// Start 启动选主循环与各任务的定时器，ctx 取消时停止并释放 leader 锁
+func (s *Scheduler) Start(ctx context.Context) {
+	if s.rdb != nil {
+		s.campaign(ctx)
+		go s.electLoop(ctx)
+	}
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	for _, job := range s.jobs {
+		go s.loop(ctx, job)
+	}
+}
+
+// Status 返回调度器与各任务的当前状态
+func (s *Scheduler) Status(ctx context.Context) Status {
+	s.mu.RLock()
+	st := Status{Instance: s.instance, IsLeader: s.leader}
+	for _, job := range s.jobs {
+		st.Jobs = append(st.Jobs, JobStatus{Name: job.Name, Interval: job.Interval, LastRun: s.lastRuns[job.Name]})
+	}
+	s.mu.RUnlock()
+
+	if s.rdb == nil {
+		st.Leader = s.instance
+	} else if v, err := s.rdb.Get(ctx, leaderKey).Result(); err == nil {
+		st.Leader = v
+	}
+	return st
+}
+
+// electLoop 每隔租约的三分之一尝试续期或抢占
+func (s *Scheduler) electLoop(ctx context.Context) {
+	ticker := time.NewTicker(s.leaseTTL / 3)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			s.resign()
+			return
+		case <-ticker.C:
+			s.campaign(ctx)
+		}
+	}
+}
+
+// campaign 尝试获取或续期 leader 租约
+func (s *Scheduler) campaign(ctx context.Context) {
+	ok, err := s.rdb.SetNX(ctx, leaderKey, s.instance, s.leaseTTL).Result()
+	if err == nil && !ok {
+		var n int64
+		n, err = renewScript.Run(ctx, s.rdb, []string{leaderKey}, s.instance, s.leaseTTL.Milliseconds()).Int64()
+		ok = n == 1
+	}
+	if err != nil {
+		// Redis 不可用时无法确认租约，保守地放弃执行
+		log.Printf("调度器选主失败: %v", err)
+		ok = false
+	}
+
+	s.mu.Lock()
+	if ok != s.leader {
+		log.Printf("调度器实例 %s leader 状态变更: %v", s.instance, ok)
+	}
+	s.leader = ok
+	s.mu.Unlock()
+}
+
+// resign 进程退出前主动释放租约，便于其他实例尽快接管
+func (s *Scheduler) resign() {
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+	defer cancel()
+	_ = releaseScript.Run(ctx, s.rdb, []string{leaderKey}, s.instance).Err()
+}
+
+func (s *Scheduler) loop(ctx context.Context, job Job) {
+	ticker := time.NewTicker(job.Interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			s.mu.RLock()
+			leader := s.leader
+			s.mu.RUnlock()
+			if leader {
+				s.runOnce(ctx, job)
+			}
+		}
+	}
+}
+
+// runOnce 执行一次任务并记录结果，任务 panic 不会影响调度器
+func (s *Scheduler) runOnce(ctx context.Context, job Job) {
+	run := &dao.JobRun{Job: job.Name, Instance: s.instance, StartedAt: time.Now()}
+	func() {
+		defer func() {
+			if r := recover(); r != nil {
+				run.Error = fmt.Sprintf("panic: %v", r)
+			}
+		}()
+		n, err := job.Run(ctx)
+		run.Affected = n
+		if err != nil {
+			run.Error = err.Error()
+		}
+	}()
+	run.FinishedAt = time.Now()
+	run.Status = dao.JobRunStatusSucceeded
+	if run.Error != "" {
+		run.Status = dao.JobRunStatusFailed
+		log.Printf("后台任务 %s 执行失败: %s", job.Name, run.Error)
+	}
+
+	if err := s.runRepo.Create(run); err != nil {
+		log.Printf("记录后台任务 %s 执行结果失败: %v", job.Name, err)
+	}
+	s.mu.Lock()
+	s.lastRuns[job.Name] = run
+	s.mu.Unlock()
+}
@@ -3,6 +3,7 @@ package service
 import (
 	"github.com/google/uuid"
 	"github.com/lib/pq"
+	"onepenny-server/internal/pagination"
 	"onepenny-server/internal/repository"
 	"onepenny-server/model/dao"
 	"time"
@@ -28,9 +29,10 @@ type RejectApplicationInput struct {
 // ApplicationService 定义业务接口
 type ApplicationService interface {
 	SubmitApplication(input *SubmitApplicationInput) (*dao.Application, error)
-	GetApplication(id uuid.UUID) (*dao.Application, error)
-	ListByBounty(bountyID uuid.UUID, page, size int) ([]*dao.Application, error)
-	ListByUser(userID uuid.UUID, page, size int) ([]*dao.Application, error)
+	// GetApplication 与 ListByBounty 仅在 viewerID 能查看所属悬赏令时返回
+	GetApplication(id, viewerID uuid.UUID) (*dao.Application, error)
+	ListByBounty(bountyID, viewerID uuid.UUID, page pagination.Page) (*pagination.Result[*dao.Application], error)
+	ListByUser(userID uuid.UUID, page pagination.Page) (*pagination.Result[*dao.Application], error)
 	UpdateApplication(id uuid.UUID, input *UpdateApplicationInput) (*dao.Application, error)
 	DeleteApplication(id uuid.UUID) error
 	ApproveApplication(input *ApproveApplicationInput) (*ApplicationDTO, error)
@@ -38,16 +40,23 @@ type ApplicationService interface {
 }
 
 type applicationService struct {
-	repo repository.ApplicationRepo
+	repo       repository.ApplicationRepo
+	bountyRepo repository.BountyRepo
+	feedSvc    FeedService
+	trendSvc   TrendingService
+	watchSvc   WatchService
 }
 
 // NewApplicationService 构造函数
-func NewApplicationService(repo repository.ApplicationRepo) ApplicationService {
-	return &applicationService{repo: repo}
+func NewApplicationService(repo repository.ApplicationRepo, bountyRepo repository.BountyRepo, feedSvc FeedService, trendSvc TrendingService, watchSvc WatchService) ApplicationService {
+	return &applicationService{repo: repo, bountyRepo: bountyRepo, feedSvc: feedSvc, trendSvc: trendSvc, watchSvc: watchSvc}
 }
 
 // SubmitApplication 提交新申请
 func (s *applicationService) SubmitApplication(input *SubmitApplicationInput) (*dao.Application, error) {
+	if err := ensureBountyVisible(s.bountyRepo, input.BountyID, input.UserID); err != nil {
+		return nil, err
+	}
 	app := &dao.Application{
 		BountyID:       input.BountyID,
 		UserID:         input.UserID,
@@ -58,28 +67,34 @@ func (s *applicationService) SubmitApplication(input *SubmitApplicationInput) (*
 	if err := s.repo.Create(app); err != nil {
 		return nil, err
 	}
+	s.feedSvc.RecordSignal(app.UserID, app.BountyID, FeedSignalApply)
+	s.trendSvc.RecordEngagement(app.BountyID, EngagementApplication)
 	return app, nil
 }
 
 // GetApplication 根据 ID 获取某条申请
-func (s *applicationService) GetApplication(id uuid.UUID) (*dao.Application, error) {
-	return s.repo.GetByID(id)
+func (s *applicationService) GetApplication(id, viewerID uuid.UUID) (*dao.Application, error) {
+	app, err := s.repo.GetByID(id)
+	if err != nil {
+		return nil, err
+	}
+	if err := ensureBountyVisible(s.bountyRepo, app.BountyID, viewerID); err != nil {
+		return nil, ErrApplicationNotFound
+	}
+	return app, nil
 }
 
 // ListByBounty 分页获取指定赏金的申请列表
-func (s *applicationService) ListByBounty(bountyID uuid.UUID, page, size int) ([]*dao.Application, error) {
-	if page < 1 {
-		page = 1
+func (s *applicationService) ListByBounty(bountyID, viewerID uuid.UUID, page pagination.Page) (*pagination.Result[*dao.Application], error) {
+	if err := ensureBountyVisible(s.bountyRepo, bountyID, viewerID); err != nil {
+		return nil, err
 	}
-	return s.repo.ListByBounty(bountyID, (page-1)*size, size)
+	return s.repo.ListByBounty(bountyID, page)
 }
 
 // ListByUser 分页获取指定用户的申请列表
-func (s *applicationService) ListByUser(userID uuid.UUID, page, size int) ([]*dao.Application, error) {
-	if page < 1 {
-		page = 1
-	}
-	return s.repo.ListByUser(userID, (page-1)*size, size)
+func (s *applicationService) ListByUser(userID uuid.UUID, page pagination.Page) (*pagination.Result[*dao.Application], error) {
+	return s.repo.ListByUser(userID, page)
 }
 
 // UpdateApplication 更新申请
@@ -145,6 +160,13 @@ func (s *applicationService) ApproveApplication(input *ApproveApplicationInput)
 	if err != nil {
 		return nil, err
 	}
+	s.feedSvc.RecordSignal(app.UserID, app.BountyID, FeedSignalAccept)
+	s.watchSvc.NotifyWatchers(&WatchEvent{
+		BountyID: app.BountyID,
+		ActorID:  &input.OwnerID,
+		Title:    "关注的悬赏令已有人承接",
+		Detail:   "发布者已批准申请，任务进行中",
+	})
 	return mapToDTO(app), nil
 }
 
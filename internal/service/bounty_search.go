@@ -0,0 +1,185 @@
+package service
+
+import (
+	"errors"
+	"html"
+	"strings"
+	"unicode"
+
+	"github.com/google/uuid"
+	"onepenny-server/internal/pagination"
+	"onepenny-server/internal/repository"
+	"onepenny-server/model/dao"
+)
+
+// ErrEmptySearchQuery 搜索词为空或不含可检索的字符
+var ErrEmptySearchQuery = errors.New("search query must contain letters or digits")
+
+const (
+	// maxSearchTerms 单次搜索最多使用的词数，避免超长输入生成过大的 tsquery
+	maxSearchTerms = 16
+	// snippetRunes 描述摘要的长度（字符数）
+	snippetRunes = 120
+)
+
+// BountySearchHit 一条搜索结果：悬赏令、相关度以及以 <mark> 标注命中词的标题与描述摘要。
+// 高亮文本已做 HTML 转义，可直接渲染。
+type BountySearchHit struct {
+	Bounty         *dao.Bounty
+	Rank           float64
+	TitleHighlight string
+	Snippet        string
+}
+
+// SearchBounties 全文检索 viewerID 可见的已发布悬赏令，按相关度排序
+func (s *bountyService) SearchBounties(viewerID uuid.UUID, q string, page pagination.Page) (*pagination.Result[*BountySearchHit], error) {
+	terms := searchTerms(q)
+	if len(terms) == 0 {
+		return nil, ErrEmptySearchQuery
+	}
+	results, err := s.repo.Search(viewerID, buildTSQuery(terms), page)
+	if err != nil {
+		return nil, err
+	}
+	return pagination.Map(results, func(r *repository.BountySearchResult) *BountySearchHit {
+		return &BountySearchHit{
+			Bounty:         r.Bounty,
+			Rank:           r.Rank,
+			TitleHighlight: highlight(r.Bounty.Title, terms, 0),
+			Snippet:        highlight(r.Bounty.Description, terms, snippetRunes),
+		}
+	}), nil
+}
+
+// searchTerm 查询中的一个检索词；prefix 表示按前缀匹配
+type searchTerm struct {
+	text   string
+	prefix bool
+}
+
+// searchTerms 按与数据库触发器 bounty_search_text 相同的规则切分查询：
+// 连续的字母数字作为一个词并按前缀匹配；连续的汉字切成相邻两字的组合，单个汉字单独成词
+func searchTerms(q string) []searchTerm {
+	var terms []searchTerm
+	seen := map[string]bool{}
+	add := func(t searchTerm) {
+		if !seen[t.text] && len(terms) < maxSearchTerms {
+			seen[t.text] = true
+			terms = append(terms, t)
+		}
+	}
+
+	runes := []rune(strings.ToLower(q))
+	for i := 0; i < len(runes); {
+		switch {
+		case isCJK(runes[i]):
+			j := i
+			for j < len(runes) && isCJK(runes[j]) {
+				j++
+			}
+			if j-i == 1 {
+				add(searchTerm{text: string(runes[i])})
+			}
+			for k := i; k+1 < j; k++ {
+				add(searchTerm{text: string(runes[k : k+2])})
+			}
+			i = j
+		case unicode.IsLetter(runes[i]) || unicode.IsDigit(runes[i]):
+			j := i
+			for j < len(runes) && !isCJK(runes[j]) && (unicode.IsLetter(runes[j]) || unicode.IsDigit(runes[j])) {
+				j++
+			}
+			add(searchTerm{text: string(runes[i:j]), prefix: true})
+			i = j
+		default:
+			i++
+		}
+	}
+	return terms
+}
+
+// buildTSQuery 将检索词以 AND 连接为 to_tsquery 的输入；检索词只含字母数字与汉字，无需转义
+func buildTSQuery(terms []searchTerm) string {
+	parts := make([]string, len(terms))
+	for i, t := range terms {
+		parts[i] = t.text
+		if t.prefix {
+			parts[i] += ":*"
+		}
+	}
+	return strings.Join(parts, " & ")
+}
+
+// isCJK 与触发器中的汉字范围保持一致
+func isCJK(r rune) bool {
+	return (r >= 0x3400 && r <= 0x4dbf) || (r >= 0x4e00 && r <= 0x9fff) || (r >= 0xf900 && r <= 0xfaff)
+}
+
+// highlight 以 <mark> 标注 text 中命中的检索词并做 HTML 转义；
+// maxRunes 大于 0 时截取第一个命中位置附近的片段作为摘要
+func highlight(text string, terms []searchTerm, maxRunes int) string {
+	runes := []rune(text)
+	lower := []rune(strings.ToLower(text))
+	if len(lower) != len(runes) {
+		// 极少数字符小写后长度变化，退化为逐字转换
+		lower = make([]rune, len(runes))
+		for i, r := range runes {
+			lower[i] = unicode.ToLower(r)
+		}
+	}
+
+	marked := make([]bool, len(runes))
+	first := -1
+	for _, t := range terms {
+		tr := []rune(t.text)
+		for i := 0; i+len(tr) <= len(lower); i++ {
+			if string(lower[i:i+len(tr)]) != t.text {
+				continue
+			}
+			// 前缀词只在词首命中，与 tsquery 的前缀匹配一致
+			if t.prefix && i > 0 && !isCJK(lower[i-1]) && (unicode.IsLetter(lower[i-1]) || unicode.IsDigit(lower[i-1])) {
+				continue
+			}
+			for k := i; k < i+len(tr); k++ {
+				marked[k] = true
+			}
+			if first < 0 || i < first {
+				first = i
+			}
+		}
+	}
+
+	start, end := 0, len(runes)
+	if maxRunes > 0 && len(runes) > maxRunes {
+		if first > maxRunes/3 {
+			start = first - maxRunes/3
+		}
+		end = start + maxRunes
+		if end > len(runes) {
+			end = len(runes)
+			start = end - maxRunes
+		}
+	}
+
+	var sb strings.Builder
+	if start > 0 {
+		sb.WriteString("…")
+	}
+	for i := start; i < end; {
+		j := i
+		for j < end && marked[j] == marked[i] {
+			j++
+		}
+		seg := html.EscapeString(string(runes[i:j]))
+		if marked[i] {
+			sb.WriteString("<mark>" + seg + "</mark>")
+		} else {
+			sb.WriteString(seg)
+		}
+		i = j
+	}
+	if end < len(runes) {
+		sb.WriteString("…")
+	}
+	return sb.String()
+}
@@ -0,0 +1,427 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+	"log"
+	"onepenny-server/internal/pagination"
+	"onepenny-server/internal/repository"
+	"onepenny-server/internal/scheduler"
+	"onepenny-server/model/dao"
+	"strconv"
+	"strings"
+	"time"
+)
+
+var (
+	// ErrSeriesNotFound 对外暴露的“周期悬赏未找到”错误
+	ErrSeriesNotFound = repository.ErrSeriesNotFound
+	// ErrInvalidCron cron 表达式格式错误
+	ErrInvalidCron = scheduler.ErrInvalidCron
+	// ErrNotSeriesOwner 只有发布者可以查看或管理周期悬赏
+	ErrNotSeriesOwner = errors.New("only the owner can manage this bounty series")
+	// ErrSeriesEnded 周期悬赏已结束，不能再修改或恢复
+	ErrSeriesEnded = errors.New("bounty series has ended")
+	// ErrInvalidTimezone 无法识别的时区
+	ErrInvalidTimezone = errors.New("invalid timezone")
+	// ErrInvalidSeriesLimit 截止时长与期数上限不能为负
+	ErrInvalidSeriesLimit = errors.New("deadline_after_hours and max_occurrences must not be negative")
+	// ErrSeriesNoRuns 按当前计划与结束条件不会再生成任何一期
+	ErrSeriesNoRuns = errors.New("schedule has no future runs before end date")
+)
+
+// spawnBatchSize 每次后台任务最多处理的到期系列数
+const spawnBatchSize = 100
+
+// BountySeriesService 定义周期悬赏相关业务接口
+type BountySeriesService interface {
+	CreateSeries(input *CreateSeriesInput) (*dao.BountySeries, error)
+	GetSeries(id, userID uuid.UUID) (*dao.BountySeries, error)
+	ListSeries(userID uuid.UUID, page pagination.Page) (*pagination.Result[*dao.BountySeries], error)
+	// UpdateSeries 整体修改系列的内容与计划，只影响之后生成的期数
+	UpdateSeries(id, userID uuid.UUID, input *UpdateSeriesInput) (*dao.BountySeries, error)
+	PauseSeries(id, userID uuid.UUID) (*dao.BountySeries, error)
+	// ResumeSeries 恢复暂停的系列，从当前时间起重新计算下一期，暂停期间错过的不补发
+	ResumeSeries(id, userID uuid.UUID) (*dao.BountySeries, error)
+	// EndSeries 手动结束系列，已生成的悬赏令不受影响
+	EndSeries(id, userID uuid.UUID) (*dao.BountySeries, error)
+	ListOccurrences(id, userID uuid.UUID, page pagination.Page) (*pagination.Result[*dao.Bounty], error)
+
+	// SpawnDue 为所有到期的系列生成新一期悬赏令，返回成功生成的数量，供后台任务调用
+	SpawnDue() (int, error)
+}
+
+type bountySeriesService struct {
+	repo     repository.BountySeriesRepo
+	tagSvc   TagService
+	catSvc   CategoryService
+	savedSvc SavedSearchService
+	watchSvc WatchService
+	notifSvc NotificationService
+}
+
+// NewBountySeriesService 构造函数
+func NewBountySeriesService(repo repository.BountySeriesRepo, tagSvc TagService, catSvc CategoryService, savedSvc SavedSearchService, watchSvc WatchService, notifSvc NotificationService) BountySeriesService {
+	return &bountySeriesService{repo: repo, tagSvc: tagSvc, catSvc: catSvc, savedSvc: savedSvc, watchSvc: watchSvc, notifSvc: notifSvc}
+}
+
+// CreateSeriesInput 新建周期悬赏所需字段
+type CreateSeriesInput struct {
+	UserID      uuid.UUID
+	Title       string
+	Description string
+	Reward      float64
+	Currency    string
+	Category    string
+	Tags        []string
+	Priority    string
+
+	Cron               string
+	Timezone           string     // 为空时为 UTC
+	StartAt            *time.Time // 第一期不早于该时间，为空时从现在开始
+	DeadlineAfterHours int
+	EndAt              *time.Time
+	MaxOccurrences     int
+}
+
+// UpdateSeriesInput 可更新的字段
+type UpdateSeriesInput struct {
+	Title       *string
+	Description *string
+	Reward      *float64
+	Currency    *string
+	Category    *string
+	Tags        *[]string
+	Priority    *string
+
+	Cron               *string
+	Timezone           *string
+	DeadlineAfterHours *int
+	EndAt              *time.Time
+	MaxOccurrences     *int
+}
+
+// CreateSeries 校验计划并计算第一期的生成时间
+func (s *bountySeriesService) CreateSeries(input *CreateSeriesInput) (*dao.BountySeries, error) {
+	if input.Reward <= 0 {
+		return nil, ErrInvalidReward
+	}
+	if input.DeadlineAfterHours < 0 || input.MaxOccurrences < 0 {
+		return nil, ErrInvalidSeriesLimit
+	}
+	if err := s.catSvc.Validate(input.Category); err != nil {
+		return nil, err
+	}
+	tags, err := s.tagSvc.Normalize(input.Tags)
+	if err != nil {
+		return nil, err
+	}
+	tz := input.Timezone
+	if tz == "" {
+		tz = "UTC"
+	}
+	series := &dao.BountySeries{
+		UserID:             input.UserID,
+		Title:              input.Title,
+		Description:        input.Description,
+		Reward:             input.Reward,
+		Currency:           strings.ToUpper(input.Currency),
+		Category:           input.Category,
+		Tags:               pq.StringArray(tags),
+		Priority:           input.Priority,
+		Cron:               input.Cron,
+		Timezone:           tz,
+		DeadlineAfterHours: input.DeadlineAfterHours,
+		EndAt:              input.EndAt,
+		MaxOccurrences:     input.MaxOccurrences,
+		Status:             dao.BountySeriesStatusActive,
+	}
+
+	from := time.Now()
+	if input.StartAt != nil && input.StartAt.After(from) {
+		// Next 取严格晚于的时间，回退一分钟使恰好落在 StartAt 的计划也能命中
+		from = input.StartAt.Add(-time.Minute)
+	}
+	next, err := nextRun(series, from)
+	if err != nil {
+		return nil, err
+	}
+	if next == nil {
+		return nil, ErrSeriesNoRuns
+	}
+	series.NextRunAt = next
+
+	if err := s.repo.Create(series); err != nil {
+		return nil, err
+	}
+	return series, nil
+}
+
+// GetSeries 获取系列，仅发布者可见
+func (s *bountySeriesService) GetSeries(id, userID uuid.UUID) (*dao.BountySeries, error) {
+	series, err := s.repo.GetByID(id)
+	if err != nil {
+		return nil, err
+	}
+	if series.UserID != userID {
+		return nil, ErrNotSeriesOwner
+	}
+	return series, nil
+}
+
+// ListSeries 分页列出用户创建的系列
+func (s *bountySeriesService) ListSeries(userID uuid.UUID, page pagination.Page) (*pagination.Result[*dao.BountySeries], error) {
+	return s.repo.ListByUser(userID, page)
+}
+
+func (s *bountySeriesService) UpdateSeries(id, userID uuid.UUID, input *UpdateSeriesInput) (*dao.BountySeries, error) {
+	series, err := s.GetSeries(id, userID)
+	if err != nil {
+		return nil, err
+	}
+	if series.Status == dao.BountySeriesStatusEnded {
+		return nil, ErrSeriesEnded
+	}
+
+	if input.Title != nil {
+		series.Title = *input.Title
+	}
+	if input.Description != nil {
+		series.Description = *input.Description
+	}
+	if input.Reward != nil {
+		if *input.Reward <= 0 {
+			return nil, ErrInvalidReward
+		}
+		series.Reward = *input.Reward
+	}
+	if input.Currency != nil {
+		series.Currency = strings.ToUpper(*input.Currency)
+	}
+	if input.Category != nil {
+		if err := s.catSvc.Validate(*input.Category); err != nil {
+			return nil, err
+		}
+		series.Category = *input.Category
+	}
+	if input.Tags != nil {
+		tags, err := s.tagSvc.Normalize(*input.Tags)
+		if err != nil {
+			return nil, err
+		}
+		series.Tags = pq.StringArray(tags)
+	}
+	if input.Priority != nil {
+		series.Priority = *input.Priority
+	}
+	if input.Cron != nil {
+		series.Cron = *input.Cron
+	}
+	if input.Timezone != nil {
+		series.Timezone = *input.Timezone
+	}
+	if input.DeadlineAfterHours != nil {
+		if *input.DeadlineAfterHours < 0 {
+			return nil, ErrInvalidSeriesLimit
+		}
+		series.DeadlineAfterHours = *input.DeadlineAfterHours
+	}
+	if input.EndAt != nil {
+		series.EndAt = input.EndAt
+	}
+	if input.MaxOccurrences != nil {
+		if *input.MaxOccurrences < 0 {
+			return nil, ErrInvalidSeriesLimit
+		}
+		series.MaxOccurrences = *input.MaxOccurrences
+	}
+
+	// 计划或结束条件可能已改变，进行中的系列从现在起重新计算下一期
+	next, err := nextRun(series, time.Now())
+	if err != nil {
+		return nil, err
+	}
+	if series.Status == dao.BountySeriesStatusActive {
+		if next == nil {
+			return nil, ErrSeriesNoRuns
+		}
+		series.NextRunAt = next
+	}
+
+	if err := s.repo.Update(series); err != nil {
+		return nil, err
+	}
+	return series, nil
+}
+
+func (s *bountySeriesService) PauseSeries(id, userID uuid.UUID) (*dao.BountySeries, error) {
+	series, err := s.GetSeries(id, userID)
+	if err != nil {
+		return nil, err
+	}
+	if series.Status == dao.BountySeriesStatusEnded {
+		return nil, ErrSeriesEnded
+	}
+	series.Status = dao.BountySeriesStatusPaused
+	series.NextRunAt = nil
+	if err := s.repo.Update(series); err != nil {
+		return nil, err
+	}
+	return series, nil
+}
+
+func (s *bountySeriesService) ResumeSeries(id, userID uuid.UUID) (*dao.BountySeries, error) {
+	series, err := s.GetSeries(id, userID)
+	if err != nil {
+		return nil, err
+	}
+	if series.Status == dao.BountySeriesStatusEnded {
+		return nil, ErrSeriesEnded
+	}
+	if series.Status == dao.BountySeriesStatusActive {
+		return series, nil
+	}
+	next, err := nextRun(series, time.Now())
+	if err != nil {
+		return nil, err
+	}
+	if next == nil {
+		return nil, ErrSeriesNoRuns
+	}
+	series.Status = dao.BountySeriesStatusActive
+	series.NextRunAt = next
+	if err := s.repo.Update(series); err != nil {
+		return nil, err
+	}
+	return series, nil
+}
+
+func (s *bountySeriesService) EndSeries(id, userID uuid.UUID) (*dao.BountySeries, error) {
+	series, err := s.GetSeries(id, userID)
+	if err != nil {
+		return nil, err
+	}
+	series.Status = dao.BountySeriesStatusEnded
+	series.NextRunAt = nil
+	if err := s.repo.Update(series); err != nil {
+		return nil, err
+	}
+	return series, nil
+}
+
+// ListOccurrences 分页列出系列已生成的悬赏令
+func (s *bountySeriesService) ListOccurrences(id, userID uuid.UUID, page pagination.Page) (*pagination.Result[*dao.Bounty], error) {
+	if _, err := s.GetSeries(id, userID); err != nil {
+		return nil, err
+	}
+	return s.repo.ListOccurrences(id, page)
+}
+
+func (s *bountySeriesService) SpawnDue() (int, error) {
+	due, err := s.repo.ListDue(time.Now(), spawnBatchSize)
+	if err != nil {
+		return 0, err
+	}
+	n := 0
+	for _, series := range due {
+		if s.spawn(series) {
+			n++
+		}
+	}
+	return n, nil
+}
+
+// spawn 生成系列的一期悬赏令；托管失败时通知发布者，其余错误只记录日志
+func (s *bountySeriesService) spawn(series *dao.BountySeries) bool {
+	runAt := *series.NextRunAt
+	// 按计划时间推算下一期，后台任务停摆期间错过的期数不补发
+	next, err := nextRun(series, maxTime(runAt, time.Now()))
+	if err != nil {
+		log.Printf("bounty series %s: %v", series.ID, err)
+		return false
+	}
+
+	var deadline *time.Time
+	if series.DeadlineAfterHours > 0 {
+		dl := runAt.Add(time.Duration(series.DeadlineAfterHours) * time.Hour)
+		deadline = &dl
+	} else if next != nil {
+		dl := *next
+		deadline = &dl
+	}
+
+	vars := map[string]string{
+		"n":    strconv.Itoa(series.OccurrenceCount + 1),
+		"date": runAt.Format("2006-01-02"),
+	}
+	var ignored []string
+	b := &dao.Bounty{
+		Title:       renderPlaceholders(series.Title, vars, &ignored),
+		Description: renderPlaceholders(series.Description, vars, &ignored),
+		Reward:      series.Reward,
+		Currency:    series.Currency,
+		UserID:      series.UserID,
+		Deadline:    deadline,
+		Category:    series.Category,
+		Tags:        series.Tags,
+		Priority:    series.Priority,
+		Status:      dao.BountyStatusCreated,
+	}
+
+	_, err = s.repo.Spawn(&repository.SpawnOccurrenceInput{
+		SeriesID:  series.ID,
+		RunAt:     runAt,
+		Bounty:    b,
+		NextRunAt: next,
+	})
+	switch {
+	case err == nil:
+		_ = s.tagSvc.Recount(b.Tags)
+		s.watchSvc.AutoWatch(b.ID, b.UserID)
+		s.tagSvc.NotifyFollowers(b)
+		s.savedSvc.MatchBounty(b)
+		return true
+	case errors.Is(err, repository.ErrSeriesNotDue):
+	case errors.Is(err, ErrInsufficientBalance):
+		_, _ = s.notifSvc.SendNotification(&SendNotificationInput{
+			UserID:      series.UserID,
+			Type:        dao.NotificationTypeSystem,
+			Title:       "周期悬赏本期未发布",
+			Description: fmt.Sprintf("钱包余额不足以托管 %.2f %s，「%s」本期已跳过", series.Reward, series.Currency, series.Title),
+			RelatedID:   &series.ID,
+			RelatedType: "bounty_series",
+		})
+	default:
+		log.Printf("bounty series %s: spawn failed: %v", series.ID, err)
+	}
+	return false
+}
+
+// nextRun 按系列的 cron 与时区计算 after 之后的下一期，超过结束时间返回 nil
+func nextRun(series *dao.BountySeries, after time.Time) (*time.Time, error) {
+	sched, err := scheduler.ParseCron(series.Cron)
+	if err != nil {
+		return nil, err
+	}
+	loc, err := time.LoadLocation(series.Timezone)
+	if err != nil {
+		return nil, ErrInvalidTimezone
+	}
+	next := sched.Next(after.In(loc))
+	if next.IsZero() || (series.EndAt != nil && next.After(*series.EndAt)) {
+		return nil, nil
+	}
+	if series.MaxOccurrences > 0 && series.OccurrenceCount >= series.MaxOccurrences {
+		return nil, nil
+	}
+	return &next, nil
+}
+
+func maxTime(a, b time.Time) time.Time {
+	if a.After(b) {
+		return a
+	}
+	return b
+}
@@ -1,121 +1,718 @@
 package service
 
 import (
+	"errors"
+	"fmt"
 	"github.com/google/uuid"
 	"github.com/lib/pq"
+	"math"
+	"onepenny-server/internal/pagination"
 	"onepenny-server/internal/repository"
 	"onepenny-server/model/dao"
+	"onepenny-server/util"
+	"strings"
 	"time"
 )
 
 var (
 	// ErrBountyNotFound Service 层返回的“未找到”错误
 	ErrBountyNotFound = repository.ErrBountyNotFound
+	// ErrInvalidBountyStatus 传入了未定义的悬赏令状态
+	ErrInvalidBountyStatus = errors.New("invalid bounty status")
+	// ErrIllegalBountyTransition 状态机拒绝的状态流转
+	ErrIllegalBountyTransition = dao.ErrIllegalBountyTransition
+	// ErrMilestoneSumMismatch 里程碑金额之和与悬赏令赏金不一致
+	ErrMilestoneSumMismatch = errors.New("里程碑金额之和必须等于悬赏令赏金")
+	// ErrInvalidMilestone 里程碑缺少标题或金额不为正
+	ErrInvalidMilestone = errors.New("里程碑必须包含标题且金额大于 0")
+	// ErrNotDraft 只有草稿可以发布或设置定时发布
+	ErrNotDraft = repository.ErrNotDraft
+	// ErrBountyIncomplete 悬赏令缺少发布所需的字段
+	ErrBountyIncomplete = errors.New("悬赏令信息不完整，无法发布")
+	// ErrInvalidVisibility 传入了未定义的可见范围
+	ErrInvalidVisibility = errors.New("invalid bounty visibility")
+	// ErrVisibilityTeamRequired 团队可见的悬赏令必须指定团队
+	ErrVisibilityTeamRequired = errors.New("团队可见的悬赏令必须指定团队")
+	// ErrNotTeamMember 只能设为自己所在团队可见
+	ErrNotTeamMember = errors.New("只能设为自己所在团队可见")
+	// ErrInvalidCoordinates 坐标须同时提供经纬度且在有效范围内，远程悬赏令不能设置坐标
+	ErrInvalidCoordinates = errors.New("坐标须同时提供经纬度且在有效范围内，远程悬赏令不能设置坐标")
+	// ErrInvalidRadius 搜索半径超出允许范围
+	ErrInvalidRadius = errors.New("radius_km must be between 0 and 1000")
+	// ErrInvalidSort 未定义的排序方式
+	ErrInvalidSort = errors.New("sort must be one of newest, reward, deadline, popular; order must be asc or desc")
+	// ErrInvalidRange 赏金或截止时间区间的下限大于上限
+	ErrInvalidRange = errors.New("range lower bound must not exceed upper bound")
 )
 
+const (
+	// publishBatchSize 每次后台任务最多发布的草稿数
+	publishBatchSize = 100
+	// defaultRadiusKm 按距离搜索时未指定半径的默认值
+	defaultRadiusKm = 10
+	// maxRadiusKm 按距离搜索允许的最大半径
+	maxRadiusKm = 1000
+)
+
+// GeoPoint 经纬度坐标（WGS84）
+type GeoPoint = repository.GeoPoint
+
+// BountyFilter 悬赏令列表的筛选与排序条件，零值表示不限
+type BountyFilter struct {
+	Location string    // "remote" 只列出远程悬赏令，其他值按线下地址模糊匹配
+	Near     *GeoPoint // 只列出该坐标附近的线下悬赏令，未指定排序时按距离排序
+	RadiusKm float64   // 配合 Near 使用，为 0 时取默认值
+
+	Statuses     []string
+	Categories   []string
+	Tags         []string
+	AllTags      bool // true 时须包含全部标签，否则包含任一即可
+	Currency     string
+	Priorities   []string
+	MinReward    *float64
+	MaxReward    *float64
+	DeadlineFrom *time.Time
+	DeadlineTo   *time.Time
+	PublisherID  *uuid.UUID
+	Unassigned   bool // 只列出尚无接收者的悬赏令
+
+	Sort  string // newest（默认）、reward、deadline、popular
+	Order string // asc 或 desc，为空时截止时间升序、其余降序
+}
+
+// BountyFacets 各维度的取值及悬赏令数量，每个维度统计时忽略该维度自身的筛选条件
+type BountyFacets = repository.BountyFacets
+
 // BountyService 定义业务层接口
 type BountyService interface {
 	CreateBounty(input *CreateBountyInput) (*dao.Bounty, error)
-	GetBounty(id uuid.UUID) (*dao.Bounty, error)
-	ListBounties(page, size int) ([]*dao.Bounty, error)
+	// GetBounty 获取悬赏令；草稿及 viewerID 无权查看的悬赏令视为不存在
+	GetBounty(id, viewerID uuid.UUID) (*dao.Bounty, error)
+	// ListBounties 按条件分页列出 viewerID 可见的已发布悬赏令，不含草稿与仅凭链接可见的
+	ListBounties(viewerID uuid.UUID, filter *BountyFilter, page pagination.Page) (*pagination.Result[*dao.Bounty], error)
+	// FacetBounties 按状态、分类与标签统计符合筛选条件的悬赏令数量，供前端构建筛选栏
+	FacetBounties(viewerID uuid.UUID, filter *BountyFilter) (*BountyFacets, error)
+	// SearchBounties 全文检索标题、描述、标签与分类，支持中文与前缀匹配，按相关度排序并返回高亮片段
+	SearchBounties(viewerID uuid.UUID, q string, page pagination.Page) (*pagination.Result[*BountySearchHit], error)
+	// UpdateBounty 发布者修改悬赏令字段，其他用户返回 ErrNotBountyOwner
 	UpdateBounty(id uuid.UUID, input *UpdateBountyInput) (*dao.Bounty, error)
-	DeleteBounty(id uuid.UUID) error
+	DeleteBounty(id, actorID uuid.UUID) error
+	ListDrafts(userID uuid.UUID, page pagination.Page) (*pagination.Result[*dao.Bounty], error)
+	// PublishBounty 发布者校验并发布草稿
+	PublishBounty(id, ownerID uuid.UUID) (*dao.Bounty, error)
+	// PublishDue 发布到达定时发布时间的草稿，失败时通知发布者并取消定时
+	PublishDue() (int, error)
+	// CancelBounty 发布者取消悬赏令，按当前状态决定直接取消、支付违约金或征求接收者同意
+	CancelBounty(input *CancelBountyInput) (*dao.Bounty, error)
+	// RespondCancellation 接收者同意或拒绝发布者的取消申请
+	RespondCancellation(bountyID, receiverID uuid.UUID, accept bool) (*dao.Bounty, error)
+	ListTimeline(bountyID, viewerID uuid.UUID, page pagination.Page) (*pagination.Result[*dao.BountyEvent], error)
 
 	RequestSettlement(bountyID, receiverID uuid.UUID) (*dao.Bounty, error)
 	ConfirmSettlement(bountyID, ownerID uuid.UUID) (*dao.Bounty, error)
+	// AutoConfirmSettlements 发布者超过 timeout 未确认结算时，自动结算给接收者
+	AutoConfirmSettlements(timeout time.Duration) (int, error)
+	// ExpireUnassigned 超过截止时间仍无人承接的悬赏令自动过期并退款
+	ExpireUnassigned() (int, error)
+	// FlagOverdue 标记超过截止时间仍在进行中的悬赏令，并提醒双方
+	FlagOverdue() (int, error)
+
+	ListMilestones(bountyID, viewerID uuid.UUID) ([]dao.Milestone, error)
+	RequestMilestoneSettlement(bountyID, milestoneID, receiverID uuid.UUID) (*dao.Bounty, error)
+	ConfirmMilestoneSettlement(bountyID, milestoneID, ownerID uuid.UUID) (*dao.Bounty, error)
 }
 
 type bountyService struct {
-	repo repository.BountyRepo
+	repo      repository.BountyRepo
+	eventRepo repository.BountyEventRepo
+	teamRepo  repository.TeamRepo
+	tagSvc    TagService
+	catSvc    CategoryService
+	savedSvc  SavedSearchService
+	watchSvc  WatchService
+	notifSvc  NotificationService
 }
 
 // NewBountyService 构造函数
-func NewBountyService(repo repository.BountyRepo) BountyService {
-	return &bountyService{repo: repo}
+func NewBountyService(repo repository.BountyRepo, eventRepo repository.BountyEventRepo, teamRepo repository.TeamRepo, tagSvc TagService, catSvc CategoryService, savedSvc SavedSearchService, watchSvc WatchService, notifSvc NotificationService) BountyService {
+	return &bountyService{repo: repo, eventRepo: eventRepo, teamRepo: teamRepo, tagSvc: tagSvc, catSvc: catSvc, savedSvc: savedSvc, watchSvc: watchSvc, notifSvc: notifSvc}
+}
+
+// announce 悬赏令发布后在后台通知关注其标签的用户，并与保存的搜索比对，不阻塞发布请求；
+// 两者失败都只记录日志
+func (s *bountyService) announce(b *dao.Bounty) {
+	// 复制一份，调用方之后修改返回的悬赏令不影响后台比对
+	snapshot := *b
+	go func() {
+		s.tagSvc.NotifyFollowers(&snapshot)
+		s.savedSvc.MatchBounty(&snapshot)
+	}()
 }
 
 // CreateBounty 新建赏金任务
 func (s *bountyService) CreateBounty(input *CreateBountyInput) (*dao.Bounty, error) {
+	if err := s.catSvc.Validate(input.Category); err != nil {
+		return nil, err
+	}
+	tags, err := s.tagSvc.Normalize(input.Tags)
+	if err != nil {
+		return nil, err
+	}
 	b := &dao.Bounty{
 		Title:       input.Title,
 		Description: input.Description,
 		Reward:      input.Reward,
-		Currency:    input.Currency,
+		Currency:    strings.ToUpper(input.Currency),
 		UserID:      input.CreatorID,
 		Deadline:    input.Deadline,
 		Category:    input.Category,
-		Tags:        pq.StringArray(input.Tags),
+		Tags:        pq.StringArray(tags),
 		Priority:    input.Priority,
+		TemplateID:  input.TemplateID,
+		PublishAt:   input.PublishAt,
+
+		Attachments:   pq.StringArray(input.Attachments),
+		Communication: input.Communication,
 
 		Status: dao.BountyStatusCreated,
 	}
+	if err := s.applyVisibility(b, input.Visibility, input.TeamID, input.AllowedUserIDs); err != nil {
+		return nil, err
+	}
+	if err := applyLocation(b, input.Location, input.Latitude, input.Longitude); err != nil {
+		return nil, err
+	}
+	// 草稿或定时在未来发布的悬赏令先保存为草稿，发布时再校验与托管
+	if input.Draft || (input.PublishAt != nil && input.PublishAt.After(time.Now())) {
+		b.Status = dao.BountyStatusDraft
+	}
+	if input.Milestones != nil {
+		ms, err := buildMilestones(input.Milestones, b.Reward)
+		if err != nil {
+			return nil, err
+		}
+		b.Milestones = ms
+	}
+	if b.Status != dao.BountyStatusDraft {
+		if err := validateForPublish(b); err != nil {
+			return nil, err
+		}
+		now := time.Now()
+		b.PublishAt = &now
+	}
 
 	if err := s.repo.Create(b); err != nil {
 		return nil, err
 	}
+	_ = s.tagSvc.Recount(b.Tags)
+	s.watchSvc.AutoWatch(b.ID, b.UserID)
+	if b.Status != dao.BountyStatusDraft {
+		s.announce(b)
+	}
 	return b, nil
 }
 
 // GetBounty 根据 ID 获取赏金任务
-func (s *bountyService) GetBounty(id uuid.UUID) (*dao.Bounty, error) {
+func (s *bountyService) GetBounty(id, viewerID uuid.UUID) (*dao.Bounty, error) {
+	if err := ensureBountyVisible(s.repo, id, viewerID); err != nil {
+		return nil, err
+	}
 	return s.repo.GetByID(id)
 }
 
 // ListBounties 分页列出赏金任务
-func (s *bountyService) ListBounties(page, size int) ([]*dao.Bounty, error) {
-	if page < 1 {
-		page = 1
+func (s *bountyService) ListBounties(viewerID uuid.UUID, filter *BountyFilter, page pagination.Page) (*pagination.Result[*dao.Bounty], error) {
+	f, err := toRepoFilter(filter, s.catSvc, s.tagSvc)
+	if err != nil {
+		return nil, err
+	}
+	return s.repo.List(viewerID, f, page)
+}
+
+func (s *bountyService) FacetBounties(viewerID uuid.UUID, filter *BountyFilter) (*BountyFacets, error) {
+	f, err := toRepoFilter(filter, s.catSvc, s.tagSvc)
+	if err != nil {
+		return nil, err
 	}
-	offset := (page - 1) * size
-	return s.repo.List(offset, size)
+	return s.repo.Facets(viewerID, f)
 }
 
-// UpdateBounty 更新赏金任务的可变字段
+// toRepoFilter 校验筛选条件并转换为持久层条件，分类展开为子孙分类，标签按别名等归并为规范写法
+func toRepoFilter(filter *BountyFilter, catSvc CategoryService, tagSvc TagService) (*repository.BountyFilter, error) {
+	f := &repository.BountyFilter{}
+	if filter == nil {
+		return f, nil
+	}
+	f.Location = strings.TrimSpace(filter.Location)
+	if filter.Near != nil {
+		if !util.ValidCoordinates(filter.Near.Lat, filter.Near.Lng) {
+			return nil, ErrInvalidCoordinates
+		}
+		if filter.RadiusKm < 0 || filter.RadiusKm > maxRadiusKm {
+			return nil, ErrInvalidRadius
+		}
+		f.Near = filter.Near
+		f.RadiusKm = filter.RadiusKm
+		if f.RadiusKm == 0 {
+			f.RadiusKm = defaultRadiusKm
+		}
+	}
+
+	for _, st := range filter.Statuses {
+		status := dao.BountyStatus(st)
+		if !status.IsValid() {
+			return nil, ErrInvalidBountyStatus
+		}
+		f.Statuses = append(f.Statuses, status)
+	}
+	// 按父分类筛选时包含其全部子孙分类
+	categories, err := catSvc.Expand(filter.Categories)
+	if err != nil {
+		return nil, err
+	}
+	f.Categories = categories
+	tags, err := tagSvc.Canonical(filter.Tags)
+	if err != nil {
+		return nil, err
+	}
+	f.Tags = tags
+	f.AllTags = filter.AllTags
+	f.Currency = strings.ToUpper(filter.Currency)
+	f.Priorities = filter.Priorities
+
+	if filter.MinReward != nil && filter.MaxReward != nil && *filter.MinReward > *filter.MaxReward {
+		return nil, ErrInvalidRange
+	}
+	f.MinReward, f.MaxReward = filter.MinReward, filter.MaxReward
+	if filter.DeadlineFrom != nil && filter.DeadlineTo != nil && filter.DeadlineFrom.After(*filter.DeadlineTo) {
+		return nil, ErrInvalidRange
+	}
+	f.DeadlineFrom, f.DeadlineTo = filter.DeadlineFrom, filter.DeadlineTo
+	f.PublisherID = filter.PublisherID
+	f.Unassigned = filter.Unassigned
+
+	switch filter.Sort {
+	case "", repository.BountySortNewest, repository.BountySortReward,
+		repository.BountySortDeadline, repository.BountySortPopular:
+	default:
+		return nil, ErrInvalidSort
+	}
+	switch filter.Order {
+	case "", "asc", "desc":
+	default:
+		return nil, ErrInvalidSort
+	}
+	f.Sort, f.Order = filter.Sort, filter.Order
+	return f, nil
+}
+
+// applyLocation 设置位置与坐标：远程悬赏令不带坐标，线下悬赏令的坐标可选但须同时提供经纬度
+func applyLocation(b *dao.Bounty, location string, lat, lng *float64) error {
+	location = strings.TrimSpace(location)
+	if strings.EqualFold(location, repository.LocationRemote) {
+		location = repository.LocationRemote
+	}
+	if (lat == nil) != (lng == nil) {
+		return ErrInvalidCoordinates
+	}
+	if lat != nil && (location == repository.LocationRemote || !util.ValidCoordinates(*lat, *lng)) {
+		return ErrInvalidCoordinates
+	}
+	b.Location = location
+	b.Latitude, b.Longitude = lat, lng
+	return nil
+}
+
+// ensureBountyVisible 悬赏令对 viewerID 不可见时返回 ErrBountyNotFound，
+// 评论、点赞、申请等旁路接口均需先经此校验，避免泄露隐藏的悬赏令
+func ensureBountyVisible(repo repository.BountyRepo, bountyID, viewerID uuid.UUID) error {
+	ok, err := repo.CanView(bountyID, viewerID)
+	if err != nil {
+		return err
+	}
+	if !ok {
+		return ErrBountyNotFound
+	}
+	return nil
+}
+
+// applyVisibility 校验并设置可见范围；团队可见时发布者必须是该团队成员，
+// 白名单仅在仅邀请可见时保留
+func (s *bountyService) applyVisibility(b *dao.Bounty, visibility string, teamID *uuid.UUID, allowed []uuid.UUID) error {
+	v := dao.BountyVisibility(visibility)
+	if v == "" {
+		v = dao.BountyVisibilityPublic
+	}
+	if !v.IsValid() {
+		return ErrInvalidVisibility
+	}
+	b.Visibility = v
+
+	b.TeamID = nil
+	if v == dao.BountyVisibilityTeam {
+		if teamID == nil {
+			return ErrVisibilityTeamRequired
+		}
+		ok, err := s.teamRepo.IsMember(*teamID, b.UserID)
+		if err != nil {
+			return err
+		}
+		if !ok {
+			return ErrNotTeamMember
+		}
+		b.TeamID = teamID
+	}
+
+	b.AllowedUsers = []dao.BountyAllowedUser{}
+	if v == dao.BountyVisibilityInviteOnly {
+		seen := map[uuid.UUID]bool{}
+		for _, uid := range allowed {
+			if seen[uid] || uid == b.UserID {
+				continue
+			}
+			seen[uid] = true
+			b.AllowedUsers = append(b.AllowedUsers, dao.BountyAllowedUser{UserID: uid})
+		}
+	}
+	return nil
+}
+
+// UpdateBounty 更新赏金任务的可变字段，仅发布者可操作
 func (s *bountyService) UpdateBounty(id uuid.UUID, input *UpdateBountyInput) (*dao.Bounty, error) {
-	b, err := s.repo.GetByID(id)
+	b, err := s.GetBounty(id, input.ActorID)
 	if err != nil {
 		return nil, err
 	}
+	// 赏金、币种变更会动用发布者钱包中的托管资金，其余字段也只允许发布者修改
+	if b.UserID != input.ActorID {
+		return nil, repository.ErrNotBountyOwner
+	}
 
-	// 仅更新非 nil 字段
+	// 仅更新非 nil 字段，并记录变更内容写入时间线
+	changes := map[string]interface{}{}
 	if input.Title != nil {
 		b.Title = *input.Title
+		changes["title"] = b.Title
 	}
 	if input.Description != nil {
 		b.Description = *input.Description
+		changes["description"] = b.Description
 	}
 	if input.Reward != nil {
 		b.Reward = *input.Reward
+		changes["reward"] = b.Reward
 	}
 	if input.Currency != nil {
-		b.Currency = *input.Currency
+		b.Currency = strings.ToUpper(*input.Currency)
+		changes["currency"] = b.Currency
 	}
 	if input.Deadline != nil {
 		b.Deadline = input.Deadline
-	}
-	if input.Status != nil {
-		b.Status = dao.BountyStatus(*input.Status)
+		b.OverdueAt = nil // 截止时间变更后重新判断是否逾期
+		changes["deadline"] = b.Deadline
 	}
 	if input.Category != nil {
+		if err := s.catSvc.Validate(*input.Category); err != nil {
+			return nil, err
+		}
 		b.Category = *input.Category
+		changes["category"] = b.Category
 	}
+	// 标签变更后，新旧标签的使用次数都需重新统计
+	var retagged []string
 	if input.Tags != nil {
-		b.Tags = pq.StringArray(*input.Tags)
+		tags, err := s.tagSvc.Normalize(*input.Tags)
+		if err != nil {
+			return nil, err
+		}
+		retagged = append(append(retagged, b.Tags...), tags...)
+		b.Tags = pq.StringArray(tags)
+		changes["tags"] = b.Tags
 	}
 	if input.Priority != nil {
 		b.Priority = *input.Priority
+		changes["priority"] = b.Priority
+	}
+	if input.Attachments != nil {
+		b.Attachments = pq.StringArray(*input.Attachments)
+		changes["attachments"] = len(b.Attachments)
+	}
+	if input.Communication != nil {
+		b.Communication = *input.Communication
+		changes["communication"] = b.Communication
+	}
+	if input.Location != nil || input.Latitude != nil || input.Longitude != nil {
+		// 地址变更而未提供新坐标时，旧坐标随之失效
+		location, lat, lng := b.Location, input.Latitude, input.Longitude
+		if input.Location != nil {
+			location = *input.Location
+		}
+		if lat == nil && lng == nil && input.Location == nil {
+			lat, lng = b.Latitude, b.Longitude
+		}
+		if err := applyLocation(b, location, lat, lng); err != nil {
+			return nil, err
+		}
+		changes["location"] = b.Location
+	}
+	if input.PublishAt != nil {
+		if b.Status != dao.BountyStatusDraft {
+			return nil, ErrNotDraft
+		}
+		if input.PublishAt.IsZero() {
+			b.PublishAt = nil // 零值表示取消定时发布
+		} else {
+			b.PublishAt = input.PublishAt
+		}
+		changes["publish_at"] = b.PublishAt
+	}
+
+	// 里程碑整体替换；仅调整赏金时，原有里程碑之和也必须与新赏金一致
+	var milestones []dao.Milestone
+	if input.Milestones != nil {
+		if milestones, err = buildMilestones(*input.Milestones, b.Reward); err != nil {
+			return nil, err
+		}
+		changes["milestones"] = len(milestones)
+	} else if input.Reward != nil && len(b.Milestones) > 0 && !amountsMatch(b.Milestones, b.Reward) {
+		return nil, ErrMilestoneSumMismatch
+	}
+
+	// 可见范围整体设置：未传入的部分沿用原值
+	allowedUsers := b.AllowedUsers
+	b.AllowedUsers = nil
+	if input.Visibility != nil || input.TeamID != nil || input.AllowedUserIDs != nil {
+		visibility := string(b.Visibility)
+		if input.Visibility != nil {
+			visibility = *input.Visibility
+		}
+		teamID := b.TeamID
+		if input.TeamID != nil {
+			teamID = input.TeamID
+		}
+		var allowed []uuid.UUID
+		if input.AllowedUserIDs != nil {
+			allowed = *input.AllowedUserIDs
+		} else {
+			for _, au := range allowedUsers {
+				allowed = append(allowed, au.UserID)
+			}
+		}
+		if err := s.applyVisibility(b, visibility, teamID, allowed); err != nil {
+			return nil, err
+		}
+		changes["visibility"] = b.Visibility
+		if input.AllowedUserIDs != nil {
+			changes["allowed_users"] = len(b.AllowedUsers)
+		}
+	}
+	if len(changes) == 0 {
+		b.AllowedUsers = allowedUsers
+		return b, nil
 	}
 
-	if err := s.repo.Update(b); err != nil {
+	if err := s.repo.Update(b, milestones, &dao.BountyEvent{
+		ActorID: &input.ActorID,
+		Type:    dao.BountyEventUpdated,
+		Payload: changes,
+	}); err != nil {
 		return nil, err
 	}
+	_ = s.tagSvc.Recount(retagged)
+	if _, ok := changes["deadline"]; ok {
+		detail := "截止时间已取消"
+		if b.Deadline != nil {
+			detail = "截止时间改为 " + b.Deadline.Format(time.RFC3339)
+		}
+		s.watchSvc.NotifyWatchers(&WatchEvent{
+			BountyID: b.ID,
+			ActorID:  &input.ActorID,
+			Title:    "关注的悬赏令截止时间变更",
+			Detail:   detail,
+		})
+	}
+	if b.AllowedUsers == nil {
+		b.AllowedUsers = allowedUsers
+	}
 	return b, nil
 }
 
-// DeleteBounty 删除（软删除）赏金任务
-func (s *bountyService) DeleteBounty(id uuid.UUID) error {
-	// 可在这里加权限校验、关联清理等逻辑
-	return s.repo.Delete(id)
+// DeleteBounty 删除（软删除）赏金任务，仅限发布方删除草稿或已结束的悬赏令
+func (s *bountyService) DeleteBounty(id, actorID uuid.UUID) error {
+	b, err := s.repo.GetByID(id)
+	if err != nil {
+		return err
+	}
+	if b.UserID != actorID {
+		return repository.ErrNotBountyOwner
+	}
+	if err := s.repo.Delete(id); err != nil {
+		return err
+	}
+	_ = s.tagSvc.Recount(b.Tags)
+	return nil
+}
+
+// ListDrafts 分页列出用户自己的草稿
+func (s *bountyService) ListDrafts(userID uuid.UUID, page pagination.Page) (*pagination.Result[*dao.Bounty], error) {
+	return s.repo.ListDrafts(userID, page)
+}
+
+func (s *bountyService) PublishBounty(id, ownerID uuid.UUID) (*dao.Bounty, error) {
+	b, err := s.GetBounty(id, ownerID)
+	if err != nil {
+		return nil, err
+	}
+	if b.UserID != ownerID {
+		return nil, repository.ErrNotBountyOwner
+	}
+	if b.Status != dao.BountyStatusDraft {
+		return nil, ErrNotDraft
+	}
+	if err := validateForPublish(b); err != nil {
+		return nil, err
+	}
+	published, err := s.repo.Publish(id, &ownerID)
+	if err != nil {
+		return nil, err
+	}
+	s.announce(published)
+	return published, nil
+}
+
+func (s *bountyService) PublishDue() (int, error) {
+	due, err := s.repo.ListDueDrafts(time.Now(), publishBatchSize)
+	if err != nil {
+		return 0, err
+	}
+	n := 0
+	for _, b := range due {
+		err := validateForPublish(b)
+		if err == nil {
+			var published *dao.Bounty
+			if published, err = s.repo.Publish(b.ID, nil); err == nil {
+				s.announce(published)
+			}
+		}
+		if errors.Is(err, ErrNotDraft) {
+			continue // 期间已被手动发布
+		}
+		if err != nil {
+			// 校验或托管失败：取消定时，等待发布者修改后重新发布
+			if cerr := s.repo.ClearPublishAt(b.ID); cerr != nil {
+				return n, cerr
+			}
+			_, _ = s.notifSvc.SendNotification(&SendNotificationInput{
+				UserID:      b.UserID,
+				Type:        dao.NotificationTypeSystem,
+				Title:       "定时发布失败",
+				Description: "悬赏令未能按时发布（" + err.Error() + "），已转为普通草稿：" + b.Title,
+				RelatedID:   &b.ID,
+				RelatedType: "bounty",
+			})
+			continue
+		}
+		n++
+	}
+	return n, nil
+}
+
+// CancelBountyInput 取消悬赏令所需字段
+type CancelBountyInput struct {
+	BountyID uuid.UUID
+	OwnerID  uuid.UUID
+	Reason   string
+	KillFee  float64 // 进行中时支付给接收者的违约金，0 表示征求接收者同意
+}
+
+// CancelBounty 发布者取消悬赏令；进行中且未支付违约金时通知接收者确认
+func (s *bountyService) CancelBounty(input *CancelBountyInput) (*dao.Bounty, error) {
+	b, applicants, err := s.repo.Cancel(&repository.CancelBountyInput{
+		BountyID: input.BountyID,
+		OwnerID:  input.OwnerID,
+		Reason:   input.Reason,
+		KillFee:  input.KillFee,
+	})
+	if err != nil {
+		return nil, err
+	}
+	if b.Status != dao.BountyStatusCancelled {
+		_, _ = s.notifSvc.SendNotification(&SendNotificationInput{
+			UserID:      *b.ReceiverID,
+			ActorID:     &input.OwnerID,
+			Type:        dao.NotificationTypeSystem,
+			Title:       "发布者申请取消悬赏令",
+			Description: "取消原因：" + input.Reason + "。同意后赏金将全额退回发布者：" + b.Title,
+			RelatedID:   &b.ID,
+			RelatedType: "bounty",
+		})
+		return b, nil
+	}
+	s.notifyCancelled(b, &input.OwnerID, applicants)
+	return b, nil
+}
+
+// RespondCancellation 接收者处理取消申请，并通知发布者结果
+func (s *bountyService) RespondCancellation(bountyID, receiverID uuid.UUID, accept bool) (*dao.Bounty, error) {
+	b, applicants, err := s.repo.RespondCancellation(bountyID, receiverID, accept)
+	if err != nil {
+		return nil, err
+	}
+	if accept {
+		s.notifyCancelled(b, &receiverID, applicants)
+		return b, nil
+	}
+	_, _ = s.notifSvc.SendNotification(&SendNotificationInput{
+		UserID:      b.UserID,
+		ActorID:     &receiverID,
+		Type:        dao.NotificationTypeSystem,
+		Title:       "接收者拒绝取消",
+		Description: "接收者拒绝了取消申请，悬赏令继续进行：" + b.Title,
+		RelatedID:   &b.ID,
+		RelatedType: "bounty",
+	})
+	return b, nil
+}
+
+// notifyCancelled 悬赏令取消后通知除操作者外的相关用户：发布者、接收者与全部申请人
+func (s *bountyService) notifyCancelled(b *dao.Bounty, actorID *uuid.UUID, applicants []uuid.UUID) {
+	recipients := map[uuid.UUID]bool{}
+	for _, uid := range applicants {
+		recipients[uid] = true
+	}
+	if b.ReceiverID != nil {
+		recipients[*b.ReceiverID] = true
+	}
+	recipients[b.UserID] = true
+	delete(recipients, *actorID)
+	notified := make([]uuid.UUID, 0, len(recipients))
+	for uid := range recipients {
+		notified = append(notified, uid)
+		_, _ = s.notifSvc.SendNotification(&SendNotificationInput{
+			UserID:      uid,
+			ActorID:     actorID,
+			Type:        dao.NotificationTypeSystem,
+			Title:       "悬赏令已取消",
+			Description: "悬赏令已被取消，相关申请已自动关闭：" + b.Title,
+			RelatedID:   &b.ID,
+			RelatedType: "bounty",
+		})
+	}
+	s.watchSvc.NotifyWatchers(&WatchEvent{
+		BountyID: b.ID,
+		ActorID:  actorID,
+		Title:    "关注的悬赏令已取消",
+		Detail:   "悬赏令已被取消",
+		Exclude:  notified,
+	})
+}
+
+// ListTimeline 按时间顺序分页列出悬赏令的事件历史
+func (s *bountyService) ListTimeline(bountyID, viewerID uuid.UUID, page pagination.Page) (*pagination.Result[*dao.BountyEvent], error) {
+	if err := ensureBountyVisible(s.repo, bountyID, viewerID); err != nil {
+		return nil, err
+	}
+	return s.eventRepo.ListByBounty(bountyID, page)
 }
 
 // CreateBountyInput 新建赏金任务所需字段
@@ -128,27 +725,267 @@ type CreateBountyInput struct {
 	Deadline    *time.Time
 	Category    string
 	Tags        []string
-	Priority    string // "low","normal","high"
+	Priority    string           // "low","normal","high"
+	Milestones  []MilestoneInput // 可选，按顺序排列，金额之和必须等于 Reward
+	TemplateID  *uuid.UUID       // 通过模板发布时记录来源
+	Draft       bool             // 保存为草稿，稍后手动发布
+	PublishAt   *time.Time       // 定时发布；晚于当前时间时先保存为草稿
+
+	Visibility     string      // public/unlisted/team/invite_only，为空时为 public
+	TeamID         *uuid.UUID  // 团队可见时必填
+	AllowedUserIDs []uuid.UUID // 仅邀请可见时的白名单
+
+	Attachments   []string
+	Location      string   // "remote" 或线下地址
+	Latitude      *float64 // 线下悬赏令的坐标，可选，须与 Longitude 同时提供
+	Longitude     *float64
+	Communication string
 	// … 如有更多，可继续添加
 }
 
+// MilestoneInput 单个里程碑的字段
+type MilestoneInput struct {
+	Title       string
+	Deliverable string
+	Amount      float64
+	DueDate     *time.Time
+}
+
 // UpdateBountyInput 可更新的字段
 type UpdateBountyInput struct {
+	ActorID     uuid.UUID // 操作者，写入事件历史
 	Title       *string
 	Description *string
 	Reward      *float64
 	Currency    *string
 	Deadline    *time.Time
-	Status      *string
 	Category    *string
 	Tags        *[]string
 	Priority    *string
+	Milestones  *[]MilestoneInput // 非 nil 时整体替换，空切片表示取消分阶段
+	PublishAt   *time.Time        // 仅草稿可设置，零值表示取消定时发布
+
+	Visibility     *string
+	TeamID         *uuid.UUID
+	AllowedUserIDs *[]uuid.UUID // 非 nil 时整体替换白名单
+
+	Attachments   *[]string
+	Location      *string  // 修改地址而未同时提供坐标时清除原坐标
+	Latitude      *float64 // 须与 Longitude 同时提供
+	Longitude     *float64
+	Communication *string
 }
 
 func (s *bountyService) RequestSettlement(bountyID, receiverID uuid.UUID) (*dao.Bounty, error) {
-	return s.repo.RequestSettlement(bountyID, receiverID)
+	b, err := s.repo.RequestSettlement(bountyID, receiverID)
+	if err != nil {
+		return nil, err
+	}
+	s.watchSvc.NotifyWatchers(&WatchEvent{
+		BountyID: b.ID,
+		ActorID:  &receiverID,
+		Title:    "关注的悬赏令待结算",
+		Detail:   "接收者已完成任务并发起结算",
+	})
+	return b, nil
 }
 
 func (s *bountyService) ConfirmSettlement(bountyID, ownerID uuid.UUID) (*dao.Bounty, error) {
-	return s.repo.ConfirmSettlement(bountyID, ownerID)
+	b, err := s.repo.ConfirmSettlement(bountyID, ownerID)
+	if err != nil {
+		return nil, err
+	}
+	s.watchSvc.NotifyWatchers(&WatchEvent{
+		BountyID: b.ID,
+		ActorID:  &ownerID,
+		Title:    "关注的悬赏令已结算",
+		Detail:   "发布者已确认结算，赏金已发放给接收者",
+	})
+	return b, nil
+}
+
+// AutoConfirmSettlements 发布者超过 timeout 未确认结算时，自动结算给接收者并通知双方
+func (s *bountyService) AutoConfirmSettlements(timeout time.Duration) (int, error) {
+	done, err := s.repo.AutoConfirmSettlements(time.Now().Add(-timeout))
+	for _, b := range done {
+		recipients := []uuid.UUID{b.UserID}
+		if b.ReceiverID != nil {
+			recipients = append(recipients, *b.ReceiverID)
+		}
+		for _, uid := range recipients {
+			_, _ = s.notifSvc.SendNotification(&SendNotificationInput{
+				UserID:      uid,
+				Type:        dao.NotificationTypeSystem,
+				Title:       "悬赏令已自动结算",
+				Description: "发布者超时未确认结算，系统已将赏金发放给接收者：" + b.Title,
+				RelatedID:   &b.ID,
+				RelatedType: "bounty",
+			})
+		}
+		s.watchSvc.NotifyWatchers(&WatchEvent{
+			BountyID: b.ID,
+			Title:    "关注的悬赏令已结算",
+			Detail:   "发布者超时未确认，系统已自动结算",
+			Exclude:  recipients,
+		})
+	}
+	return len(done), err
+}
+
+// ExpireUnassigned 超过截止时间仍无人承接的悬赏令自动过期，托管赏金退回并通知发布者
+func (s *bountyService) ExpireUnassigned() (int, error) {
+	done, err := s.repo.ExpireUnassigned(time.Now())
+	for _, b := range done {
+		_, _ = s.notifSvc.SendNotification(&SendNotificationInput{
+			UserID:      b.UserID,
+			Type:        dao.NotificationTypeSystem,
+			Title:       "悬赏令已过期",
+			Description: "悬赏令超过截止时间仍无人承接，赏金已退回钱包：" + b.Title,
+			RelatedID:   &b.ID,
+			RelatedType: "bounty",
+		})
+		s.watchSvc.NotifyWatchers(&WatchEvent{
+			BountyID: b.ID,
+			Title:    "关注的悬赏令已过期",
+			Detail:   "悬赏令超过截止时间仍无人承接，已过期",
+			Exclude:  []uuid.UUID{b.UserID},
+		})
+	}
+	return len(done), err
+}
+
+// FlagOverdue 标记超过截止时间仍在进行中的悬赏令，并提醒发布者与接收者
+func (s *bountyService) FlagOverdue() (int, error) {
+	done, err := s.repo.FlagOverdue(time.Now())
+	for _, b := range done {
+		recipients := []uuid.UUID{b.UserID}
+		if b.ReceiverID != nil {
+			recipients = append(recipients, *b.ReceiverID)
+		}
+		for _, uid := range recipients {
+			_, _ = s.notifSvc.SendNotification(&SendNotificationInput{
+				UserID:      uid,
+				Type:        dao.NotificationTypeSystem,
+				Title:       "悬赏令已逾期",
+				Description: "悬赏令已超过截止时间仍未完成：" + b.Title,
+				RelatedID:   &b.ID,
+				RelatedType: "bounty",
+			})
+		}
+	}
+	return len(done), err
+}
+
+// ListMilestones 按顺序列出悬赏令的里程碑
+func (s *bountyService) ListMilestones(bountyID, viewerID uuid.UUID) ([]dao.Milestone, error) {
+	b, err := s.GetBounty(bountyID, viewerID)
+	if err != nil {
+		return nil, err
+	}
+	return b.Milestones, nil
+}
+
+// RequestMilestoneSettlement 接收者就某个里程碑发起结算，并通知发布者
+func (s *bountyService) RequestMilestoneSettlement(bountyID, milestoneID, receiverID uuid.UUID) (*dao.Bounty, error) {
+	b, err := s.repo.RequestMilestoneSettlement(bountyID, milestoneID, receiverID)
+	if err != nil {
+		return nil, err
+	}
+	if m := findMilestone(b, milestoneID); m != nil {
+		_, _ = s.notifSvc.SendNotification(&SendNotificationInput{
+			UserID:      b.UserID,
+			ActorID:     &receiverID,
+			Type:        dao.NotificationTypeSystem,
+			Title:       "里程碑待确认",
+			Description: "接收者已完成里程碑「" + m.Title + "」并发起结算：" + b.Title,
+			RelatedID:   &b.ID,
+			RelatedType: "bounty",
+		})
+	}
+	return b, nil
+}
+
+// ConfirmMilestoneSettlement 发布者确认某个里程碑，发放该阶段赏金并通知接收者
+func (s *bountyService) ConfirmMilestoneSettlement(bountyID, milestoneID, ownerID uuid.UUID) (*dao.Bounty, error) {
+	b, err := s.repo.ConfirmMilestoneSettlement(bountyID, milestoneID, ownerID)
+	if err != nil {
+		return nil, err
+	}
+	if m := findMilestone(b, milestoneID); m != nil && b.ReceiverID != nil {
+		_, _ = s.notifSvc.SendNotification(&SendNotificationInput{
+			UserID:      *b.ReceiverID,
+			ActorID:     &ownerID,
+			Type:        dao.NotificationTypeSystem,
+			Title:       "里程碑已结算",
+			Description: "发布者已确认里程碑「" + m.Title + "」，该阶段赏金已发放：" + b.Title,
+			RelatedID:   &b.ID,
+			RelatedType: "bounty",
+		})
+		s.watchSvc.NotifyWatchers(&WatchEvent{
+			BountyID: b.ID,
+			ActorID:  &ownerID,
+			Title:    "关注的悬赏令里程碑已结算",
+			Detail:   "里程碑「" + m.Title + "」已结算",
+			Exclude:  []uuid.UUID{*b.ReceiverID},
+		})
+	}
+	return b, nil
+}
+
+// buildMilestones 校验里程碑输入并按顺序编号
+func buildMilestones(inputs []MilestoneInput, reward float64) ([]dao.Milestone, error) {
+	ms := make([]dao.Milestone, len(inputs))
+	for i, in := range inputs {
+		if strings.TrimSpace(in.Title) == "" || in.Amount <= 0 {
+			return nil, ErrInvalidMilestone
+		}
+		ms[i] = dao.Milestone{
+			Seq:         i + 1,
+			Title:       in.Title,
+			Deliverable: in.Deliverable,
+			Amount:      in.Amount,
+			DueDate:     in.DueDate,
+			Status:      dao.MilestoneStatusPending,
+		}
+	}
+	if len(ms) > 0 && !amountsMatch(ms, reward) {
+		return nil, ErrMilestoneSumMismatch
+	}
+	return ms, nil
+}
+
+// validateForPublish 发布前校验草稿允许暂缺的字段
+func validateForPublish(b *dao.Bounty) error {
+	switch {
+	case strings.TrimSpace(b.Title) == "":
+		return fmt.Errorf("%w: 缺少标题", ErrBountyIncomplete)
+	case strings.TrimSpace(b.Description) == "":
+		return fmt.Errorf("%w: 缺少描述", ErrBountyIncomplete)
+	case b.Reward <= 0:
+		return fmt.Errorf("%w: 赏金必须大于 0", ErrBountyIncomplete)
+	case b.Currency == "":
+		return fmt.Errorf("%w: 缺少币种", ErrBountyIncomplete)
+	case b.Deadline != nil && !b.Deadline.After(time.Now()):
+		return fmt.Errorf("%w: 截止时间已过", ErrBountyIncomplete)
+	case len(b.Milestones) > 0 && !amountsMatch(b.Milestones, b.Reward):
+		return ErrMilestoneSumMismatch
+	}
+	return nil
+}
+
+func amountsMatch(ms []dao.Milestone, reward float64) bool {
+	var sum float64
+	for _, m := range ms {
+		sum += m.Amount
+	}
+	return math.Abs(sum-reward) < 1e-6
+}
+
+func findMilestone(b *dao.Bounty, id uuid.UUID) *dao.Milestone {
+	for i := range b.Milestones {
+		if b.Milestones[i].ID == id {
+			return &b.Milestones[i]
+		}
+	}
+	return nil
 }
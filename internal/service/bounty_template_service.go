@@ -0,0 +1,313 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+	"onepenny-server/internal/pagination"
+	"onepenny-server/internal/repository"
+	"onepenny-server/model/dao"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+)
+
+var (
+	// ErrTemplateNotFound 对外暴露的“模板未找到”错误
+	ErrTemplateNotFound = repository.ErrTemplateNotFound
+	// ErrTemplateForbidden 无权查看或修改该模板
+	ErrTemplateForbidden = errors.New("no permission for this bounty template")
+	// ErrTemplateVariableMissing 实例化时缺少占位符对应的变量
+	ErrTemplateVariableMissing = errors.New("missing template variables")
+	// ErrInvalidReward 实例化后的赏金必须大于 0
+	ErrInvalidReward = errors.New("reward must be greater than 0")
+)
+
+// placeholderPattern 匹配 {{name}} 形式的占位符，允许两侧空格
+var placeholderPattern = regexp.MustCompile(`\{\{\s*([A-Za-z0-9_]+)\s*\}\}`)
+
+// BountyTemplateService 定义悬赏令模板相关业务接口
+type BountyTemplateService interface {
+	CreateTemplate(input *BountyTemplateInput) (*dao.BountyTemplate, error)
+	GetTemplate(id, userID uuid.UUID) (*dao.BountyTemplate, error)
+	ListTemplates(userID uuid.UUID, teamID *uuid.UUID, page pagination.Page) (*pagination.Result[*dao.BountyTemplate], error)
+	UpdateTemplate(id, userID uuid.UUID, input *UpdateBountyTemplateInput) (*dao.BountyTemplate, error)
+	DeleteTemplate(id, userID uuid.UUID) error
+	// Instantiate 用模板与覆盖字段发布一个真实的悬赏令
+	Instantiate(id uuid.UUID, input *InstantiateTemplateInput) (*dao.Bounty, error)
+}
+
+type bountyTemplateService struct {
+	repo      repository.BountyTemplateRepo
+	teamRepo  repository.TeamRepo
+	tagSvc    TagService
+	catSvc    CategoryService
+	bountySvc BountyService
+}
+
+// NewBountyTemplateService 构造函数
+func NewBountyTemplateService(repo repository.BountyTemplateRepo, teamRepo repository.TeamRepo, tagSvc TagService, catSvc CategoryService, bountySvc BountyService) BountyTemplateService {
+	return &bountyTemplateService{repo: repo, teamRepo: teamRepo, tagSvc: tagSvc, catSvc: catSvc, bountySvc: bountySvc}
+}
+
+// BountyTemplateInput 新建模板所需字段
+type BountyTemplateInput struct {
+	UserID      uuid.UUID
+	TeamID      *uuid.UUID // 为空时为个人模板
+	Name        string
+	Title       string
+	Description string
+	Reward      float64
+	Currency    string
+	Category    string
+	Tags        []string
+	Priority    string
+}
+
+// UpdateBountyTemplateInput 可更新的模板字段，归属不可修改
+type UpdateBountyTemplateInput struct {
+	Name        *string
+	Title       *string
+	Description *string
+	Reward      *float64
+	Currency    *string
+	Category    *string
+	Tags        *[]string
+	Priority    *string
+}
+
+// InstantiateTemplateInput 实例化模板时的变量与覆盖字段，覆盖字段为空时使用模板默认值
+type InstantiateTemplateInput struct {
+	UserID    uuid.UUID
+	Variables map[string]string
+
+	Title       *string
+	Description *string
+	Reward      *float64
+	Currency    *string
+	Deadline    *time.Time
+	Category    *string
+	Tags        *[]string
+	Priority    *string
+}
+
+// CreateTemplate 创建模板；团队模板要求创建者是团队成员
+func (s *bountyTemplateService) CreateTemplate(input *BountyTemplateInput) (*dao.BountyTemplate, error) {
+	if input.Reward < 0 {
+		return nil, ErrInvalidAmount
+	}
+	if input.TeamID != nil {
+		if err := s.checkMember(*input.TeamID, input.UserID); err != nil {
+			return nil, err
+		}
+	}
+	if err := s.catSvc.Validate(input.Category); err != nil {
+		return nil, err
+	}
+	tags, err := s.tagSvc.Normalize(input.Tags)
+	if err != nil {
+		return nil, err
+	}
+	t := &dao.BountyTemplate{
+		UserID:      input.UserID,
+		TeamID:      input.TeamID,
+		Name:        input.Name,
+		Title:       input.Title,
+		Description: input.Description,
+		Reward:      input.Reward,
+		Currency:    strings.ToUpper(input.Currency),
+		Category:    input.Category,
+		Tags:        pq.StringArray(tags),
+		Priority:    input.Priority,
+	}
+	if err := s.repo.Create(t); err != nil {
+		return nil, err
+	}
+	return t, nil
+}
+
+// GetTemplate 获取模板：个人模板仅创建者可见，团队模板团队成员可见
+func (s *bountyTemplateService) GetTemplate(id, userID uuid.UUID) (*dao.BountyTemplate, error) {
+	t, err := s.repo.GetByID(id)
+	if err != nil {
+		return nil, err
+	}
+	if t.TeamID == nil {
+		if t.UserID != userID {
+			return nil, ErrTemplateForbidden
+		}
+		return t, nil
+	}
+	if err := s.checkMember(*t.TeamID, userID); err != nil {
+		return nil, err
+	}
+	return t, nil
+}
+
+// ListTemplates 分页列出用户可用的模板
+func (s *bountyTemplateService) ListTemplates(userID uuid.UUID, teamID *uuid.UUID, page pagination.Page) (*pagination.Result[*dao.BountyTemplate], error) {
+	return s.repo.ListAccessible(userID, teamID, page)
+}
+
+// UpdateTemplate 修改模板：仅创建者或所属团队的创建者可操作
+func (s *bountyTemplateService) UpdateTemplate(id, userID uuid.UUID, input *UpdateBountyTemplateInput) (*dao.BountyTemplate, error) {
+	t, err := s.manageable(id, userID)
+	if err != nil {
+		return nil, err
+	}
+	if input.Name != nil {
+		t.Name = *input.Name
+	}
+	if input.Title != nil {
+		t.Title = *input.Title
+	}
+	if input.Description != nil {
+		t.Description = *input.Description
+	}
+	if input.Reward != nil {
+		if *input.Reward < 0 {
+			return nil, ErrInvalidAmount
+		}
+		t.Reward = *input.Reward
+	}
+	if input.Currency != nil {
+		t.Currency = strings.ToUpper(*input.Currency)
+	}
+	if input.Category != nil {
+		if err := s.catSvc.Validate(*input.Category); err != nil {
+			return nil, err
+		}
+		t.Category = *input.Category
+	}
+	if input.Tags != nil {
+		tags, err := s.tagSvc.Normalize(*input.Tags)
+		if err != nil {
+			return nil, err
+		}
+		t.Tags = pq.StringArray(tags)
+	}
+	if input.Priority != nil {
+		t.Priority = *input.Priority
+	}
+	if err := s.repo.Update(t); err != nil {
+		return nil, err
+	}
+	return t, nil
+}
+
+// DeleteTemplate 删除模板，权限同修改
+func (s *bountyTemplateService) DeleteTemplate(id, userID uuid.UUID) error {
+	if _, err := s.manageable(id, userID); err != nil {
+		return err
+	}
+	return s.repo.Delete(id)
+}
+
+// Instantiate 合并覆盖字段、替换占位符后，以当前用户身份发布悬赏令（同样需要托管赏金）
+func (s *bountyTemplateService) Instantiate(id uuid.UUID, input *InstantiateTemplateInput) (*dao.Bounty, error) {
+	t, err := s.GetTemplate(id, input.UserID)
+	if err != nil {
+		return nil, err
+	}
+
+	title, description := t.Title, t.Description
+	reward, currency, category, priority := t.Reward, t.Currency, t.Category, t.Priority
+	tags := []string(t.Tags)
+	if input.Title != nil {
+		title = *input.Title
+	}
+	if input.Description != nil {
+		description = *input.Description
+	}
+	if input.Reward != nil {
+		reward = *input.Reward
+	}
+	if input.Currency != nil {
+		currency = *input.Currency
+	}
+	if input.Category != nil {
+		category = *input.Category
+	}
+	if input.Tags != nil {
+		tags = *input.Tags
+	}
+	if input.Priority != nil {
+		priority = *input.Priority
+	}
+	if reward <= 0 {
+		return nil, ErrInvalidReward
+	}
+
+	var missing []string
+	title = renderPlaceholders(title, input.Variables, &missing)
+	description = renderPlaceholders(description, input.Variables, &missing)
+	if len(missing) > 0 {
+		sort.Strings(missing)
+		return nil, fmt.Errorf("%w: %s", ErrTemplateVariableMissing, strings.Join(missing, ", "))
+	}
+
+	return s.bountySvc.CreateBounty(&CreateBountyInput{
+		Title:       title,
+		Description: description,
+		Reward:      reward,
+		Currency:    currency,
+		CreatorID:   input.UserID,
+		Deadline:    input.Deadline,
+		Category:    category,
+		Tags:        tags,
+		Priority:    priority,
+		TemplateID:  &t.ID,
+	})
+}
+
+// manageable 取出模板并校验修改权限
+func (s *bountyTemplateService) manageable(id, userID uuid.UUID) (*dao.BountyTemplate, error) {
+	t, err := s.repo.GetByID(id)
+	if err != nil {
+		return nil, err
+	}
+	if t.UserID == userID {
+		return t, nil
+	}
+	if t.TeamID != nil {
+		team, err := s.teamRepo.GetByID(*t.TeamID)
+		if err != nil && !errors.Is(err, repository.ErrTeamNotFound) {
+			return nil, err
+		}
+		if team != nil && team.OwnerID == userID {
+			return t, nil
+		}
+	}
+	return nil, ErrTemplateForbidden
+}
+
+// checkMember 校验用户属于团队
+func (s *bountyTemplateService) checkMember(teamID, userID uuid.UUID) error {
+	ok, err := s.teamRepo.IsMember(teamID, userID)
+	if err != nil {
+		return err
+	}
+	if !ok {
+		return ErrTemplateForbidden
+	}
+	return nil
+}
+
+// renderPlaceholders 用 vars 替换文本中的占位符，未提供的变量名追加到 missing（去重）
+func renderPlaceholders(text string, vars map[string]string, missing *[]string) string {
+	return placeholderPattern.ReplaceAllStringFunc(text, func(m string) string {
+		name := placeholderPattern.FindStringSubmatch(m)[1]
+		if v, ok := vars[name]; ok {
+			return v
+		}
+		for _, n := range *missing {
+			if n == name {
+				return m
+			}
+		}
+		*missing = append(*missing, name)
+		return m
+	})
+}
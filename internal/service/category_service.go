@@ -0,0 +1,177 @@
+package service
+
+import (
+	"errors"
+	"github.com/google/uuid"
+	"onepenny-server/internal/repository"
+	"onepenny-server/model/dao"
+	"regexp"
+	"strings"
+)
+
+var (
+	// ErrCategoryNotFound 找不到对应的分类
+	ErrCategoryNotFound = repository.ErrCategoryNotFound
+	// ErrCategoryCycle 不能将分类移动到自身或其子孙分类之下
+	ErrCategoryCycle = repository.ErrCategoryCycle
+	// ErrCategoryHasChildren 仍有子分类的分类不能删除
+	ErrCategoryHasChildren = repository.ErrCategoryHasChildren
+	// ErrCategoryInUse 仍被使用的分类不能删除
+	ErrCategoryInUse = repository.ErrCategoryInUse
+	// ErrCategoryExists Slug 已被其他分类使用
+	ErrCategoryExists = errors.New("category slug already exists")
+	// ErrInvalidCategorySlug Slug 只能包含小写字母、数字与连字符
+	ErrInvalidCategorySlug = errors.New("category slug must be 1-64 lowercase letters, digits or single hyphens")
+	// ErrCategoryNameRequired 分类至少需要一种语言的显示名称
+	ErrCategoryNameRequired = errors.New("category requires at least one display name")
+	// ErrUnknownCategory 悬赏令使用了分类树中不存在的分类
+	ErrUnknownCategory = errors.New("unknown category")
+)
+
+var categorySlugPattern = regexp.MustCompile(`^[a-z0-9]+(-[a-z0-9]+)*$`)
+
+// CategoryNode 分类树中的一个节点
+type CategoryNode struct {
+	*dao.Category
+	Children []*CategoryNode
+}
+
+// CategoryInput 新建或修改分类所需字段；修改时 Slug 不可变更
+type CategoryInput struct {
+	Slug      string
+	ParentID  *uuid.UUID
+	Names     map[string]string
+	Icon      string
+	SortOrder int
+}
+
+// CategoryService 分类树的管理与校验
+type CategoryService interface {
+	// Tree 返回完整的分类树，同级按排序值升序
+	Tree() ([]*CategoryNode, error)
+	GetCategory(slug string) (*dao.Category, error)
+	CreateCategory(input *CategoryInput) (*dao.Category, error)
+	UpdateCategory(id uuid.UUID, input *CategoryInput) (*dao.Category, error)
+	DeleteCategory(id uuid.UUID) error
+
+	// Validate 校验悬赏令的分类存在于分类树中，空值表示未分类
+	Validate(slug string) error
+	// Expand 将筛选用的分类展开为自身及全部子孙分类
+	Expand(slugs []string) ([]string, error)
+}
+
+type categoryService struct {
+	repo repository.CategoryRepo
+}
+
+// NewCategoryService 构造函数
+func NewCategoryService(repo repository.CategoryRepo) CategoryService {
+	return &categoryService{repo: repo}
+}
+
+func (s *categoryService) Tree() ([]*CategoryNode, error) {
+	list, err := s.repo.List()
+	if err != nil {
+		return nil, err
+	}
+	// List 保证父分类在前，逐个挂到父节点下即可保持同级顺序
+	nodes := make(map[uuid.UUID]*CategoryNode, len(list))
+	roots := make([]*CategoryNode, 0)
+	for _, c := range list {
+		n := &CategoryNode{Category: c, Children: []*CategoryNode{}}
+		nodes[c.ID] = n
+		if c.ParentID == nil {
+			roots = append(roots, n)
+		} else if p := nodes[*c.ParentID]; p != nil {
+			p.Children = append(p.Children, n)
+		}
+	}
+	return roots, nil
+}
+
+func (s *categoryService) GetCategory(slug string) (*dao.Category, error) {
+	return s.repo.GetBySlug(slug)
+}
+
+// cleanNames 去掉空白的名称，语言代码统一为小写
+func cleanNames(names map[string]string) (map[string]string, error) {
+	out := make(map[string]string, len(names))
+	for locale, name := range names {
+		locale = strings.ToLower(strings.TrimSpace(locale))
+		name = strings.TrimSpace(name)
+		if locale != "" && name != "" {
+			out[locale] = name
+		}
+	}
+	if len(out) == 0 {
+		return nil, ErrCategoryNameRequired
+	}
+	return out, nil
+}
+
+func (s *categoryService) CreateCategory(input *CategoryInput) (*dao.Category, error) {
+	slug := strings.TrimSpace(input.Slug)
+	if len(slug) > 64 || !categorySlugPattern.MatchString(slug) {
+		return nil, ErrInvalidCategorySlug
+	}
+	names, err := cleanNames(input.Names)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := s.repo.GetBySlug(slug); err == nil {
+		return nil, ErrCategoryExists
+	} else if !errors.Is(err, repository.ErrCategoryNotFound) {
+		return nil, err
+	}
+	c := &dao.Category{
+		Slug:      slug,
+		ParentID:  input.ParentID,
+		Names:     names,
+		Icon:      input.Icon,
+		SortOrder: input.SortOrder,
+	}
+	if err := s.repo.Create(c); err != nil {
+		return nil, err
+	}
+	return c, nil
+}
+
+func (s *categoryService) UpdateCategory(id uuid.UUID, input *CategoryInput) (*dao.Category, error) {
+	c, err := s.repo.GetByID(id)
+	if err != nil {
+		return nil, err
+	}
+	names, err := cleanNames(input.Names)
+	if err != nil {
+		return nil, err
+	}
+	c.ParentID = input.ParentID
+	c.Names = names
+	c.Icon = input.Icon
+	c.SortOrder = input.SortOrder
+	if err := s.repo.Update(c); err != nil {
+		return nil, err
+	}
+	return c, nil
+}
+
+func (s *categoryService) DeleteCategory(id uuid.UUID) error {
+	return s.repo.Delete(id)
+}
+
+func (s *categoryService) Validate(slug string) error {
+	if slug == "" {
+		return nil
+	}
+	if _, err := s.repo.GetBySlug(slug); err != nil {
+		if errors.Is(err, repository.ErrCategoryNotFound) {
+			return ErrUnknownCategory
+		}
+		return err
+	}
+	return nil
+}
+
+func (s *categoryService) Expand(slugs []string) ([]string, error) {
+	return s.repo.ExpandSlugs(slugs)
+}
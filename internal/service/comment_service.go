@@ -4,6 +4,7 @@ import (
 	"errors"
 	"github.com/google/uuid"
 	"github.com/lib/pq"
+	"onepenny-server/internal/pagination"
 	"onepenny-server/internal/repository"
 	"onepenny-server/model/dao"
 	"time"
@@ -20,20 +21,24 @@ var (
 type CommentService interface {
 	AddComment(input *AddCommentInput) (*dao.Comment, error)
 	GetComment(id uuid.UUID) (*dao.Comment, error)
-	ListCommentsByBounty(bountyID uuid.UUID, page, size int) ([]*dao.Comment, error)
-	ListReplies(parentID uuid.UUID, page, size int) ([]*dao.Comment, error)
-	ListCommentsByUser(userID uuid.UUID, page, size int) ([]*dao.Comment, error)
+	// ListCommentsByBounty 与 ListReplies 仅在 viewerID 能查看所属悬赏令时返回，否则视为悬赏令不存在
+	ListCommentsByBounty(bountyID, viewerID uuid.UUID, page pagination.Page) (*pagination.Result[*dao.Comment], error)
+	ListReplies(parentID, viewerID uuid.UUID, page pagination.Page) (*pagination.Result[*dao.Comment], error)
+	ListCommentsByUser(userID uuid.UUID, page pagination.Page) (*pagination.Result[*dao.Comment], error)
 	UpdateComment(id uuid.UUID, input *UpdateCommentInput) (*dao.Comment, error)
 	DeleteComment(id uuid.UUID) error
 }
 
 type commentService struct {
-	repo repository.CommentRepo
+	repo       repository.CommentRepo
+	bountyRepo repository.BountyRepo
+	trendSvc   TrendingService
+	watchSvc   WatchService
 }
 
 // NewCommentService 构造函数
-func NewCommentService(repo repository.CommentRepo) CommentService {
-	return &commentService{repo: repo}
+func NewCommentService(repo repository.CommentRepo, bountyRepo repository.BountyRepo, trendSvc TrendingService, watchSvc WatchService) CommentService {
+	return &commentService{repo: repo, bountyRepo: bountyRepo, trendSvc: trendSvc, watchSvc: watchSvc}
 }
 
 // AddCommentInput 发布评论或回复所需字段
@@ -53,6 +58,9 @@ type UpdateCommentInput struct {
 
 // AddComment 发布一条新评论或回复
 func (s *commentService) AddComment(input *AddCommentInput) (*dao.Comment, error) {
+	if err := ensureBountyVisible(s.bountyRepo, input.BountyID, input.UserID); err != nil {
+		return nil, err
+	}
 	// 如果是回复，则确保父评论存在且属于同一 Bounty
 	if input.ParentID != nil {
 		parent, err := s.repo.GetByID(*input.ParentID)
@@ -75,6 +83,14 @@ func (s *commentService) AddComment(input *AddCommentInput) (*dao.Comment, error
 	if err := s.repo.Create(c); err != nil {
 		return nil, err
 	}
+	s.trendSvc.RecordEngagement(c.BountyID, EngagementComment)
+	s.watchSvc.NotifyWatchers(&WatchEvent{
+		BountyID: c.BountyID,
+		ActorID:  &c.UserID,
+		Title:    "关注的悬赏令有新评论",
+		Detail:   truncateRunes(c.Content, 60),
+	})
+	s.watchSvc.AutoWatch(c.BountyID, c.UserID)
 	return c, nil
 }
 
@@ -84,30 +100,28 @@ func (s *commentService) GetComment(id uuid.UUID) (*dao.Comment, error) {
 }
 
 // ListCommentsByBounty 列出某赏金的顶级评论，page 从 1 开始
-func (s *commentService) ListCommentsByBounty(bountyID uuid.UUID, page, size int) ([]*dao.Comment, error) {
-	if page < 1 {
-		page = 1
+func (s *commentService) ListCommentsByBounty(bountyID, viewerID uuid.UUID, page pagination.Page) (*pagination.Result[*dao.Comment], error) {
+	if err := ensureBountyVisible(s.bountyRepo, bountyID, viewerID); err != nil {
+		return nil, err
 	}
-	offset := (page - 1) * size
-	return s.repo.ListByBounty(bountyID, offset, size)
+	return s.repo.ListByBounty(bountyID, page)
 }
 
 // ListReplies 列出某评论的回复
-func (s *commentService) ListReplies(parentID uuid.UUID, page, size int) ([]*dao.Comment, error) {
-	if page < 1 {
-		page = 1
+func (s *commentService) ListReplies(parentID, viewerID uuid.UUID, page pagination.Page) (*pagination.Result[*dao.Comment], error) {
+	parent, err := s.repo.GetByID(parentID)
+	if err != nil {
+		return nil, err
+	}
+	if err := ensureBountyVisible(s.bountyRepo, parent.BountyID, viewerID); err != nil {
+		return nil, err
 	}
-	offset := (page - 1) * size
-	return s.repo.ListReplies(parentID, offset, size)
+	return s.repo.ListReplies(parentID, page)
 }
 
 // ListCommentsByUser 列出某用户发布的所有评论
-func (s *commentService) ListCommentsByUser(userID uuid.UUID, page, size int) ([]*dao.Comment, error) {
-	if page < 1 {
-		page = 1
-	}
-	offset := (page - 1) * size
-	return s.repo.ListByUser(userID, offset, size)
+func (s *commentService) ListCommentsByUser(userID uuid.UUID, page pagination.Page) (*pagination.Result[*dao.Comment], error) {
+	return s.repo.ListByUser(userID, page)
 }
 
 // UpdateComment 更新评论内容或附件
@@ -0,0 +1,83 @@
+package service
+
+import (
+	"fmt"
+	"github.com/google/uuid"
+	"onepenny-server/internal/pagination"
+	"onepenny-server/internal/repository"
+	"onepenny-server/model/dao"
+	"strings"
+)
+
+// ContributionService 定义众筹追加赏金相关业务接口
+type ContributionService interface {
+	Contribute(input *ContributeInput) (*dao.Contribution, error)
+	ListByBounty(bountyID, viewerID uuid.UUID, page pagination.Page) (*pagination.Result[*dao.Contribution], error)
+}
+
+type contributionService struct {
+	repo       repository.ContributionRepo
+	bountyRepo repository.BountyRepo
+	notifSvc   NotificationService
+}
+
+// NewContributionService 构造函数
+func NewContributionService(repo repository.ContributionRepo, bountyRepo repository.BountyRepo, notifSvc NotificationService) ContributionService {
+	return &contributionService{repo: repo, bountyRepo: bountyRepo, notifSvc: notifSvc}
+}
+
+// ContributeInput 追加赏金所需字段
+type ContributeInput struct {
+	BountyID uuid.UUID
+	UserID   uuid.UUID
+	Currency string // 为空时使用悬赏令的币种
+	Amount   float64
+	Message  string
+}
+
+// Contribute 第三方用户为悬赏令追加赏金，并通知发布者
+func (s *contributionService) Contribute(input *ContributeInput) (*dao.Contribution, error) {
+	if input.Amount <= 0 {
+		return nil, ErrInvalidAmount
+	}
+	if err := ensureBountyVisible(s.bountyRepo, input.BountyID, input.UserID); err != nil {
+		return nil, err
+	}
+	b, err := s.bountyRepo.GetByID(input.BountyID)
+	if err != nil {
+		return nil, err
+	}
+	currency := strings.ToUpper(input.Currency)
+	if currency == "" {
+		currency = b.Currency
+	}
+
+	ct, err := s.repo.Create(&repository.CreateContributionInput{
+		BountyID: input.BountyID,
+		UserID:   input.UserID,
+		Currency: currency,
+		Amount:   input.Amount,
+		Message:  input.Message,
+	})
+	if err != nil {
+		return nil, err
+	}
+	_, _ = s.notifSvc.SendNotification(&SendNotificationInput{
+		UserID:      b.UserID,
+		ActorID:     &input.UserID,
+		Type:        dao.NotificationTypeSystem,
+		Title:       "悬赏令获得追加赏金",
+		Description: fmt.Sprintf("有用户为悬赏令追加了 %.2f %s：%s", ct.Amount, ct.Currency, b.Title),
+		RelatedID:   &b.ID,
+		RelatedType: "bounty",
+	})
+	return ct, nil
+}
+
+// ListByBounty 分页列出悬赏令的出资人及出资记录
+func (s *contributionService) ListByBounty(bountyID, viewerID uuid.UUID, page pagination.Page) (*pagination.Result[*dao.Contribution], error) {
+	if err := ensureBountyVisible(s.bountyRepo, bountyID, viewerID); err != nil {
+		return nil, err
+	}
+	return s.repo.ListByBounty(bountyID, page)
+}
@@ -0,0 +1,176 @@
+package service
+
+import (
+	"github.com/google/uuid"
+	"onepenny-server/internal/pagination"
+	"onepenny-server/internal/repository"
+	"onepenny-server/model/dao"
+)
+
+var (
+	// ErrDisputeNotFound 对外的“未找到”错误
+	ErrDisputeNotFound = repository.ErrDisputeNotFound
+	// ErrNotDisputeParty 非争议当事人
+	ErrNotDisputeParty = repository.ErrNotDisputeParty
+)
+
+// DisputeService 定义结算争议与仲裁相关业务接口
+type DisputeService interface {
+	OpenDispute(input *OpenDisputeInput) (*dao.Dispute, error)
+	GetDispute(id, viewerID uuid.UUID) (*dao.Dispute, error)
+	ListByBounty(bountyID, viewerID uuid.UUID, page pagination.Page) (*pagination.Result[*dao.Dispute], error)
+	AddEvidence(id, userID uuid.UUID, urls []string) (*dao.Dispute, error)
+	WithdrawDispute(id, userID uuid.UUID) (*dao.Dispute, error)
+
+	// 以下供仲裁员使用，权限由路由层校验
+	GetDisputeForArbitration(id uuid.UUID) (*dao.Dispute, error)
+	ListByStatus(status string, page pagination.Page) (*pagination.Result[*dao.Dispute], error)
+	ResolveDispute(input *ResolveDisputeInput) (*dao.Dispute, error)
+}
+
+type disputeService struct {
+	repo     repository.DisputeRepo
+	watchSvc WatchService
+	notifSvc NotificationService
+}
+
+// NewDisputeService 构造函数
+func NewDisputeService(repo repository.DisputeRepo, watchSvc WatchService, notifSvc NotificationService) DisputeService {
+	return &disputeService{repo: repo, watchSvc: watchSvc, notifSvc: notifSvc}
+}
+
+// OpenDisputeInput 发起争议所需字段
+type OpenDisputeInput struct {
+	BountyID     uuid.UUID
+	InitiatorID  uuid.UUID
+	Reason       string
+	EvidenceURLs []string
+}
+
+// ResolveDisputeInput 仲裁裁决所需字段
+type ResolveDisputeInput struct {
+	DisputeID    uuid.UUID
+	ArbitratorID uuid.UUID
+	Ruling       string // full_payout, partial_payout, refund
+	PayoutAmount float64
+	Note         string
+}
+
+// OpenDispute 发布者或接收者发起争议，并通知另一方
+func (s *disputeService) OpenDispute(input *OpenDisputeInput) (*dao.Dispute, error) {
+	d, err := s.repo.Open(&repository.OpenDisputeInput{
+		BountyID:     input.BountyID,
+		InitiatorID:  input.InitiatorID,
+		Reason:       input.Reason,
+		EvidenceURLs: input.EvidenceURLs,
+	})
+	if err != nil {
+		return nil, err
+	}
+	s.notify(d, &d.InitiatorID, "悬赏令结算发生争议", input.Reason, d.RespondentID)
+	s.watchSvc.NotifyWatchers(&WatchEvent{
+		BountyID: d.BountyID,
+		ActorID:  &d.InitiatorID,
+		Title:    "关注的悬赏令进入争议",
+		Detail:   "结算发生争议，等待仲裁",
+		Exclude:  []uuid.UUID{d.RespondentID},
+	})
+	return d, nil
+}
+
+// GetDispute 当事人查看争议详情
+func (s *disputeService) GetDispute(id, viewerID uuid.UUID) (*dao.Dispute, error) {
+	d, err := s.repo.GetByID(id)
+	if err != nil {
+		return nil, err
+	}
+	if d.InitiatorID != viewerID && d.RespondentID != viewerID {
+		return nil, ErrNotDisputeParty
+	}
+	return d, nil
+}
+
+// ListByBounty 分页列出当前用户在某悬赏令下参与的争议
+func (s *disputeService) ListByBounty(bountyID, viewerID uuid.UUID, page pagination.Page) (*pagination.Result[*dao.Dispute], error) {
+	return s.repo.ListByBounty(bountyID, viewerID, page)
+}
+
+// AddEvidence 当事人补充证据，并通知另一方
+func (s *disputeService) AddEvidence(id, userID uuid.UUID, urls []string) (*dao.Dispute, error) {
+	d, err := s.repo.AddEvidence(id, userID, urls)
+	if err != nil {
+		return nil, err
+	}
+	other := d.RespondentID
+	if userID == d.RespondentID {
+		other = d.InitiatorID
+	}
+	s.notify(d, &userID, "争议有新的证据", "对方补充了争议证据", other)
+	return d, nil
+}
+
+// WithdrawDispute 发起方撤回争议，并通知另一方
+func (s *disputeService) WithdrawDispute(id, userID uuid.UUID) (*dao.Dispute, error) {
+	d, err := s.repo.Withdraw(id, userID)
+	if err != nil {
+		return nil, err
+	}
+	s.notify(d, &userID, "争议已撤回", "发起方撤回了争议，悬赏令恢复原状态", d.RespondentID)
+	return d, nil
+}
+
+// GetDisputeForArbitration 仲裁员查看争议详情
+func (s *disputeService) GetDisputeForArbitration(id uuid.UUID) (*dao.Dispute, error) {
+	return s.repo.GetByID(id)
+}
+
+// ListByStatus 仲裁队列，status 为空时默认列出待裁决的争议
+func (s *disputeService) ListByStatus(status string, page pagination.Page) (*pagination.Result[*dao.Dispute], error) {
+	if status == "" {
+		status = string(dao.DisputeStatusOpen)
+	}
+	return s.repo.ListByStatus(dao.DisputeStatus(status), page)
+}
+
+// ResolveDispute 仲裁员裁决争议，并通知双方
+func (s *disputeService) ResolveDispute(input *ResolveDisputeInput) (*dao.Dispute, error) {
+	d, err := s.repo.Resolve(&repository.ResolveDisputeInput{
+		DisputeID:    input.DisputeID,
+		ArbitratorID: input.ArbitratorID,
+		Ruling:       dao.DisputeRuling(input.Ruling),
+		PayoutAmount: input.PayoutAmount,
+		Note:         input.Note,
+	})
+	if err != nil {
+		return nil, err
+	}
+	s.notify(d, &input.ArbitratorID, "争议已裁决", input.Note, d.InitiatorID, d.RespondentID)
+	s.watchSvc.NotifyWatchers(&WatchEvent{
+		BountyID: d.BountyID,
+		ActorID:  &input.ArbitratorID,
+		Title:    "关注的悬赏令已结算",
+		Detail:   "争议已裁决，悬赏令已按裁决结算",
+		Exclude:  []uuid.UUID{d.InitiatorID, d.RespondentID},
+	})
+	return d, nil
+}
+
+// notify 向争议相关用户发送站内通知，通知失败不影响主流程
+func (s *disputeService) notify(d *dao.Dispute, actorID *uuid.UUID, title, desc string, userIDs ...uuid.UUID) {
+	for _, uid := range userIDs {
+		_, _ = s.notifSvc.SendNotification(&SendNotificationInput{
+			UserID:      uid,
+			ActorID:     actorID,
+			Type:        dao.NotificationTypeDispute,
+			Title:       title,
+			Description: desc,
+			RelatedID:   &d.ID,
+			RelatedType: "dispute",
+			Metadata: map[string]interface{}{
+				"bounty_id": d.BountyID,
+				"status":    d.Status,
+				"ruling":    d.Ruling,
+			},
+		})
+	}
+}
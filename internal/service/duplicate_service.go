@@ -0,0 +1,174 @@
+package service
+
+import (
+	"errors"
+	"github.com/google/uuid"
+	"onepenny-server/internal/repository"
+	"onepenny-server/model/dao"
+	"sort"
+	"strings"
+	"time"
+)
+
+var (
+	// ErrDuplicateBounty 与发布者自己近期未完成的悬赏令高度相似，需确认后再发布
+	ErrDuplicateBounty = errors.New("bounty looks like a duplicate of your recent bounty; set force=true to post anyway")
+	// ErrInvalidDuplicateThreshold 报告的相似度阈值须在 [0.3, 1] 内
+	ErrInvalidDuplicateThreshold = errors.New("threshold must be between 0.3 and 1")
+	// ErrInvalidDuplicateWindow 报告的时间范围须为 1~90 天
+	ErrInvalidDuplicateWindow = errors.New("days must be between 1 and 90")
+)
+
+const (
+	// duplicateWindow 发布时只与该时间段内创建的悬赏令比对
+	duplicateWindow = 30 * 24 * time.Hour
+	// duplicateMinScore 相似度不低于该值视为疑似重复
+	duplicateMinScore = 0.5
+	// duplicateMaxMatches 发布时最多返回的相似悬赏令数
+	duplicateMaxMatches = 5
+	// duplicateMinReportScore 报告允许的最低相似度阈值，更低的阈值无法利用标题三元组索引
+	duplicateMinReportScore = 0.3
+	// duplicateMaxPairs 生成报告时最多取的相似悬赏令对数
+	duplicateMaxPairs = 2000
+	// duplicateMaxDays 报告的最大时间范围（天）
+	duplicateMaxDays = 90
+)
+
+// DuplicateCheck 发布前的查重结果：Own 为发布者自己的疑似重复，会阻止发布；Others 为他人的相似悬赏令，仅作提示
+type DuplicateCheck struct {
+	Own    []*repository.SimilarBounty
+	Others []*repository.SimilarBounty
+}
+
+// DuplicateCluster 报告中的一组疑似重复悬赏令：经相似对连通而成，Score 为组内最高的相似度
+type DuplicateCluster struct {
+	Bounties []*dao.Bounty
+	Score    float64
+}
+
+// DuplicateService 基于 pg_trgm 三元组相似度检测重复发布的悬赏令
+type DuplicateService interface {
+	// Check 将待发布的标题与描述和近期未完成的悬赏令比对：自己的全部参与，他人的只比对可见且公开列出的
+	Check(authorID uuid.UUID, title, description string) (*DuplicateCheck, error)
+	// Report 供管理员查看最近 days 天内创建、尚未完成的疑似重复悬赏令分组，按组内最高相似度降序；
+	// threshold 为 0 时使用默认阈值 0.5，其余取值须在 [0.3, 1] 内
+	Report(days int, threshold float64) ([]*DuplicateCluster, error)
+}
+
+type duplicateService struct {
+	repo repository.DuplicateRepo
+}
+
+// NewDuplicateService 构造函数
+func NewDuplicateService(repo repository.DuplicateRepo) DuplicateService {
+	return &duplicateService{repo: repo}
+}
+
+func (s *duplicateService) Check(authorID uuid.UUID, title, description string) (*DuplicateCheck, error) {
+	res := &DuplicateCheck{}
+	if strings.TrimSpace(title) == "" {
+		return res, nil
+	}
+	list, err := s.repo.FindSimilar(&repository.SimilarQuery{
+		ViewerID:    authorID,
+		Title:       title,
+		Description: description,
+		Since:       time.Now().Add(-duplicateWindow),
+		MinScore:    duplicateMinScore,
+		Limit:       duplicateMaxMatches,
+	})
+	if err != nil {
+		return nil, err
+	}
+	for _, sb := range list {
+		if sb.Bounty.UserID == authorID {
+			res.Own = append(res.Own, sb)
+		} else {
+			res.Others = append(res.Others, sb)
+		}
+	}
+	return res, nil
+}
+
+func (s *duplicateService) Report(days int, threshold float64) ([]*DuplicateCluster, error) {
+	if days < 1 || days > duplicateMaxDays {
+		return nil, ErrInvalidDuplicateWindow
+	}
+	if threshold == 0 {
+		threshold = duplicateMinScore
+	}
+	if threshold < duplicateMinReportScore || threshold > 1 {
+		return nil, ErrInvalidDuplicateThreshold
+	}
+	since := time.Now().AddDate(0, 0, -days)
+	pairs, err := s.repo.ListPairs(since, threshold, duplicateMaxPairs)
+	if err != nil {
+		return nil, err
+	}
+	if len(pairs) == 0 {
+		return []*DuplicateCluster{}, nil
+	}
+
+	// 并查集：相似关系可传递地把悬赏令连成一组
+	parent := map[uuid.UUID]uuid.UUID{}
+	var find func(id uuid.UUID) uuid.UUID
+	find = func(id uuid.UUID) uuid.UUID {
+		p, ok := parent[id]
+		if !ok || p == id {
+			parent[id] = id
+			return id
+		}
+		root := find(p)
+		parent[id] = root
+		return root
+	}
+	for _, p := range pairs {
+		if a, b := find(p.AID), find(p.BID); a != b {
+			parent[b] = a
+		}
+	}
+
+	ids := make([]uuid.UUID, 0, len(parent))
+	for id := range parent {
+		ids = append(ids, id)
+	}
+	list, err := s.repo.ListBounties(ids)
+	if err != nil {
+		return nil, err
+	}
+
+	byRoot := map[uuid.UUID]*DuplicateCluster{}
+	for _, b := range list {
+		root := find(b.ID)
+		cl, ok := byRoot[root]
+		if !ok {
+			cl = &DuplicateCluster{}
+			byRoot[root] = cl
+		}
+		cl.Bounties = append(cl.Bounties, b)
+	}
+	for _, p := range pairs {
+		if cl, ok := byRoot[find(p.AID)]; ok && p.Score > cl.Score {
+			cl.Score = p.Score
+		}
+	}
+
+	clusters := make([]*DuplicateCluster, 0, len(byRoot))
+	for _, cl := range byRoot {
+		// 悬赏令可能已在加载前被删除，只剩一条的组不再算重复
+		if len(cl.Bounties) < 2 {
+			continue
+		}
+		sort.Slice(cl.Bounties, func(i, j int) bool {
+			return cl.Bounties[i].CreatedAt.Before(cl.Bounties[j].CreatedAt)
+		})
+		clusters = append(clusters, cl)
+	}
+	sort.Slice(clusters, func(i, j int) bool {
+		if clusters[i].Score != clusters[j].Score {
+			return clusters[i].Score > clusters[j].Score
+		}
+		return len(clusters[i].Bounties) > len(clusters[j].Bounties)
+	})
+	return clusters, nil
+}
@@ -0,0 +1,419 @@
+package service
+
+import (
+	"context"
+	"github.com/go-redis/redis/v8"
+	"github.com/google/uuid"
+	"log"
+	"onepenny-server/internal/pagination"
+	"onepenny-server/internal/repository"
+	"onepenny-server/model/dao"
+	"sort"
+	"strconv"
+	"time"
+)
+
+// 推荐信号的来源；FeedSignalUnlike 用于撤销点赞带来的兴趣
+const (
+	FeedSignalView   = repository.FeedSignalView
+	FeedSignalLike   = repository.FeedSignalLike
+	FeedSignalUnlike = "unlike"
+	FeedSignalApply  = repository.FeedSignalApply
+	FeedSignalAccept = repository.FeedSignalAccept
+)
+
+// feedSignalWeights 各类信号对兴趣的贡献：承接与申请比点赞、浏览更能说明偏好
+var feedSignalWeights = map[string]float64{
+	FeedSignalView:   1,
+	FeedSignalLike:   3,
+	FeedSignalUnlike: -3,
+	FeedSignalApply:  4,
+	FeedSignalAccept: 6,
+}
+
+const (
+	// feedProfileWindow 兴趣画像只统计该时间段内的信号
+	feedProfileWindow = 180 * 24 * time.Hour
+	// feedProfileTTL 兴趣画像的缓存时间，期间由新信号增量更新
+	feedProfileTTL = 24 * time.Hour
+	// feedScoresTTL 推荐分数的缓存时间，过期后整体重建，以纳入兴趣变化后新命中的旧悬赏令
+	feedScoresTTL = time.Hour
+	// feedMaxCandidates 每个用户最多缓存的候选悬赏令数
+	feedMaxCandidates = 500
+	// feedMaxInterests 查询候选时最多使用的分类与标签数，按兴趣从高到低选取
+	feedMaxInterests = 50
+	// feedFreshnessHalfLife 新鲜度加分减半所需的时间
+	feedFreshnessHalfLife = 7 * 24 * time.Hour
+)
+
+// RecommendedBounty 推荐结果及其得分
+type RecommendedBounty struct {
+	Bounty *dao.Bounty
+	Score  float64
+}
+
+// FeedService 基于用户浏览、点赞、申请与承接过的分类和标签推荐可承接的悬赏令。
+// 兴趣画像与推荐分数按用户缓存在 Redis 中：新信号到来时增量调整，新发布的悬赏令在读取时增量补入
+type FeedService interface {
+	// Recommended 按得分从高到低分页列出推荐给用户的悬赏令，不含自己发布或已申请过的
+	Recommended(userID uuid.UUID, page pagination.Page) (*pagination.Result[*RecommendedBounty], error)
+	// RecordSignal 用户与悬赏令发生交互后调整其兴趣画像与已缓存的推荐分数；失败只记录日志
+	RecordSignal(userID, bountyID uuid.UUID, kind string)
+}
+
+type feedService struct {
+	repo repository.FeedRepo
+	rdb  *redis.Client
+}
+
+// NewFeedService 构造函数；rdb 为 nil 时不缓存，每次请求都重新计算
+func NewFeedService(repo repository.FeedRepo, rdb *redis.Client) FeedService {
+	return &feedService{repo: repo, rdb: rdb}
+}
+
+func feedProfileKey(userID uuid.UUID) string { return "onepenny:feed:profile:" + userID.String() }
+func feedScoresKey(userID uuid.UUID) string  { return "onepenny:feed:scores:" + userID.String() }
+func feedBuiltKey(userID uuid.UUID) string   { return "onepenny:feed:built:" + userID.String() }
+
+// interestFields 悬赏令在兴趣画像中对应的字段："c:" 前缀为分类，"t:" 前缀为标签
+func interestFields(b *dao.Bounty) []string {
+	var fields []string
+	if b.Category != "" {
+		fields = append(fields, "c:"+b.Category)
+	}
+	for _, t := range b.Tags {
+		fields = append(fields, "t:"+t)
+	}
+	return fields
+}
+
+// scoreBounty 兴趣得分加上按发布时间衰减的新鲜度（0~1），后者只用于兴趣相同的悬赏令之间排序
+func scoreBounty(profile map[string]float64, b *dao.Bounty, now time.Time) float64 {
+	var score float64
+	for _, f := range interestFields(b) {
+		score += profile[f]
+	}
+	published := b.CreatedAt
+	if b.PublishAt != nil {
+		published = *b.PublishAt
+	}
+	age := now.Sub(published)
+	if age < 0 {
+		age = 0
+	}
+	return score + 1/(1+float64(age)/float64(feedFreshnessHalfLife))
+}
+
+func (s *feedService) Recommended(userID uuid.UUID, page pagination.Page) (*pagination.Result[*RecommendedBounty], error) {
+	if s.rdb == nil {
+		return s.recommendUncached(userID, page)
+	}
+	ctx := context.Background()
+	profile, err := s.profile(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.refreshScores(ctx, userID, profile); err != nil {
+		return nil, err
+	}
+
+	// 取出的悬赏令可能已被承接或关闭：移出缓存后重新取本页，至多重试几次
+	key := feedScoresKey(userID)
+	skip, err := page.Skip()
+	if err != nil {
+		return nil, err
+	}
+	start := int64(skip)
+	for attempt := 0; ; attempt++ {
+		zs, err := s.rdb.ZRevRangeWithScores(ctx, key, start, start+int64(page.Limit())-1).Result()
+		if err != nil {
+			return nil, err
+		}
+		ids := make([]uuid.UUID, 0, len(zs))
+		for _, z := range zs {
+			if id, err := uuid.Parse(z.Member.(string)); err == nil {
+				ids = append(ids, id)
+			}
+		}
+		open, err := s.repo.FilterRecommendable(userID, ids)
+		if err != nil {
+			return nil, err
+		}
+		byID := make(map[uuid.UUID]*dao.Bounty, len(open))
+		for _, b := range open {
+			byID[b.ID] = b
+		}
+		items := make([]*RecommendedBounty, 0, len(zs))
+		var stale []interface{}
+		for _, z := range zs {
+			id, _ := uuid.Parse(z.Member.(string))
+			if b := byID[id]; b != nil {
+				items = append(items, &RecommendedBounty{Bounty: b, Score: z.Score})
+			} else {
+				stale = append(stale, z.Member)
+			}
+		}
+		if len(stale) == 0 || attempt == 2 {
+			return pagination.Slice(items, page), nil
+		}
+		if err := s.rdb.ZRem(ctx, key, stale...).Err(); err != nil {
+			return nil, err
+		}
+	}
+}
+
+// recommendUncached 未配置 Redis 时直接按数据库计算
+func (s *feedService) recommendUncached(userID uuid.UUID, page pagination.Page) (*pagination.Result[*RecommendedBounty], error) {
+	profile, err := s.buildProfile(userID)
+	if err != nil {
+		return nil, err
+	}
+	scored, err := s.scoreCandidates(userID, profile, nil)
+	if err != nil {
+		return nil, err
+	}
+	sort.SliceStable(scored, func(i, j int) bool { return scored[i].Score > scored[j].Score })
+	skip, err := page.Skip()
+	if err != nil {
+		return nil, err
+	}
+	if skip > len(scored) {
+		skip = len(scored)
+	}
+	end := skip + page.Limit()
+	if end > len(scored) {
+		end = len(scored)
+	}
+	ids := make([]uuid.UUID, 0, end-skip)
+	for _, r := range scored[skip:end] {
+		ids = append(ids, r.Bounty.ID)
+	}
+	open, err := s.repo.FilterRecommendable(userID, ids)
+	if err != nil {
+		return nil, err
+	}
+	byID := make(map[uuid.UUID]*dao.Bounty, len(open))
+	for _, b := range open {
+		byID[b.ID] = b
+	}
+	items := make([]*RecommendedBounty, 0, len(ids))
+	for _, r := range scored[skip:end] {
+		if b := byID[r.Bounty.ID]; b != nil {
+			items = append(items, &RecommendedBounty{Bounty: b, Score: r.Score})
+		}
+	}
+	return pagination.Slice(items, page), nil
+}
+
+// buildProfile 由数据库中的历史信号计算兴趣画像
+func (s *feedService) buildProfile(userID uuid.UUID) (map[string]float64, error) {
+	signals, err := s.repo.ListSignals(userID, time.Now().Add(-feedProfileWindow))
+	if err != nil {
+		return nil, err
+	}
+	profile := map[string]float64{}
+	for _, sig := range signals {
+		w := feedSignalWeights[sig.Kind]
+		for _, f := range interestFields(&dao.Bounty{Category: sig.Category, Tags: sig.Tags}) {
+			profile[f] += w
+		}
+	}
+	return profile, nil
+}
+
+// profile 读取缓存的兴趣画像，缺失时重建
+func (s *feedService) profile(ctx context.Context, userID uuid.UUID) (map[string]float64, error) {
+	key := feedProfileKey(userID)
+	raw, err := s.rdb.HGetAll(ctx, key).Result()
+	if err != nil {
+		return nil, err
+	}
+	if len(raw) > 0 {
+		profile := make(map[string]float64, len(raw))
+		for f, v := range raw {
+			if w, err := strconv.ParseFloat(v, 64); err == nil && f != "" {
+				profile[f] = w
+			}
+		}
+		return profile, nil
+	}
+
+	profile, err := s.buildProfile(userID)
+	if err != nil {
+		return nil, err
+	}
+	// 空画像也写入占位字段，避免每次请求都重建
+	values := map[string]interface{}{"": 0}
+	for f, w := range profile {
+		values[f] = w
+	}
+	_, err = s.rdb.TxPipelined(ctx, func(p redis.Pipeliner) error {
+		p.HSet(ctx, key, values)
+		p.Expire(ctx, key, feedProfileTTL)
+		return nil
+	})
+	return profile, err
+}
+
+// topInterests 按兴趣从高到低选出用于查询候选的分类与标签，忽略非正的兴趣
+func topInterests(profile map[string]float64) (categories, tags []string) {
+	fields := make([]string, 0, len(profile))
+	for f, w := range profile {
+		if w > 0 && len(f) > 2 {
+			fields = append(fields, f)
+		}
+	}
+	sort.Slice(fields, func(i, j int) bool {
+		if profile[fields[i]] != profile[fields[j]] {
+			return profile[fields[i]] > profile[fields[j]]
+		}
+		return fields[i] < fields[j]
+	})
+	if len(fields) > feedMaxInterests {
+		fields = fields[:feedMaxInterests]
+	}
+	for _, f := range fields {
+		switch f[:2] {
+		case "c:":
+			categories = append(categories, f[2:])
+		case "t:":
+			tags = append(tags, f[2:])
+		}
+	}
+	return categories, tags
+}
+
+// scoreCandidates 为候选悬赏令打分；画像为空时以最新的悬赏令冷启动
+func (s *feedService) scoreCandidates(userID uuid.UUID, profile map[string]float64, since *time.Time) ([]*RecommendedBounty, error) {
+	categories, tags := topInterests(profile)
+	list, err := s.repo.ListCandidates(&repository.FeedCandidateQuery{
+		ViewerID:   userID,
+		Categories: categories,
+		Tags:       tags,
+		Since:      since,
+		Limit:      feedMaxCandidates,
+	})
+	if err != nil {
+		return nil, err
+	}
+	now := time.Now()
+	scored := make([]*RecommendedBounty, 0, len(list))
+	for _, b := range list {
+		scored = append(scored, &RecommendedBounty{Bounty: b, Score: scoreBounty(profile, b, now)})
+	}
+	return scored, nil
+}
+
+// refreshScores 缓存缺失时整体重建推荐分数，否则只补入上次刷新后发布的悬赏令
+func (s *feedService) refreshScores(ctx context.Context, userID uuid.UUID, profile map[string]float64) error {
+	scoresKey, builtKey := feedScoresKey(userID), feedBuiltKey(userID)
+	var since *time.Time
+	if v, err := s.rdb.Get(ctx, builtKey).Int64(); err == nil {
+		t := time.Unix(0, v)
+		since = &t
+	} else if err != redis.Nil {
+		return err
+	}
+
+	now := time.Now()
+	scored, err := s.scoreCandidates(userID, profile, since)
+	if err != nil {
+		return err
+	}
+	members := make([]*redis.Z, 0, len(scored))
+	for _, r := range scored {
+		members = append(members, &redis.Z{Score: r.Score, Member: r.Bounty.ID.String()})
+	}
+	_, err = s.rdb.TxPipelined(ctx, func(p redis.Pipeliner) error {
+		if since == nil {
+			p.Del(ctx, scoresKey)
+		}
+		if len(members) > 0 {
+			p.ZAdd(ctx, scoresKey, members...)
+			p.ZRemRangeByRank(ctx, scoresKey, 0, -feedMaxCandidates-1)
+		}
+		if since == nil {
+			p.Set(ctx, builtKey, now.UnixNano(), feedScoresTTL)
+			p.Expire(ctx, scoresKey, feedScoresTTL)
+		} else {
+			p.Set(ctx, builtKey, now.UnixNano(), redis.KeepTTL)
+		}
+		return nil
+	})
+	return err
+}
+
+func (s *feedService) RecordSignal(userID, bountyID uuid.UUID, kind string) {
+	if s.rdb == nil {
+		return
+	}
+	if err := s.recordSignal(context.Background(), userID, bountyID, kind); err != nil {
+		log.Printf("feed: record %s signal of user %s on bounty %s: %v", kind, userID, bountyID, err)
+	}
+}
+
+func (s *feedService) recordSignal(ctx context.Context, userID, bountyID uuid.UUID, kind string) error {
+	w := feedSignalWeights[kind]
+	profileKey, scoresKey := feedProfileKey(userID), feedScoresKey(userID)
+
+	if kind == FeedSignalApply || kind == FeedSignalAccept {
+		if err := s.rdb.ZRem(ctx, scoresKey, bountyID.String()).Err(); err != nil {
+			return err
+		}
+	}
+
+	bounties, err := s.repo.GetTaxonomy([]uuid.UUID{bountyID})
+	if err != nil || len(bounties) == 0 {
+		return err
+	}
+	fields := interestFields(bounties[0])
+	if len(fields) == 0 {
+		return nil
+	}
+
+	// 画像未缓存时无需调整，下次读取会从数据库重建并包含本次信号
+	if n, err := s.rdb.Exists(ctx, profileKey).Result(); err != nil || n == 0 {
+		return err
+	}
+	if _, err := s.rdb.TxPipelined(ctx, func(p redis.Pipeliner) error {
+		for _, f := range fields {
+			p.HIncrByFloat(ctx, profileKey, f, w)
+		}
+		return nil
+	}); err != nil {
+		return err
+	}
+
+	// 已缓存的候选按命中的分类与标签数调整得分，与整体重建的结果一致
+	members, err := s.rdb.ZRange(ctx, scoresKey, 0, -1).Result()
+	if err != nil || len(members) == 0 {
+		return err
+	}
+	ids := make([]uuid.UUID, 0, len(members))
+	for _, m := range members {
+		if id, err := uuid.Parse(m); err == nil {
+			ids = append(ids, id)
+		}
+	}
+	cached, err := s.repo.GetTaxonomy(ids)
+	if err != nil {
+		return err
+	}
+	delta := make(map[string]float64, len(fields))
+	for _, f := range fields {
+		delta[f] += w
+	}
+	_, err = s.rdb.TxPipelined(ctx, func(p redis.Pipeliner) error {
+		for _, b := range cached {
+			var inc float64
+			for _, f := range interestFields(b) {
+				inc += delta[f]
+			}
+			if inc != 0 {
+				p.ZIncrBy(ctx, scoresKey, inc, b.ID.String())
+			}
+		}
+		return nil
+	})
+	return err
+}
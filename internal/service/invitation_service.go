@@ -3,6 +3,7 @@ package service
 import (
 	"errors"
 	"github.com/google/uuid"
+	"onepenny-server/internal/pagination"
 	"onepenny-server/internal/repository"
 	"onepenny-server/model/dao"
 	"time"
@@ -21,11 +22,13 @@ var (
 type InvitationService interface {
 	SendInvitation(input *SendInvitationInput) (*dao.Invitation, error)
 	GetInvitation(id uuid.UUID) (*dao.Invitation, error)
-	ListByInvitee(inviteeID uuid.UUID, page, size int) ([]*dao.Invitation, error)
-	ListByInviter(inviterID uuid.UUID, page, size int) ([]*dao.Invitation, error)
-	ListByTeam(teamID uuid.UUID, page, size int) ([]*dao.Invitation, error)
+	ListByInvitee(inviteeID uuid.UUID, page pagination.Page) (*pagination.Result[*dao.Invitation], error)
+	ListByInviter(inviterID uuid.UUID, page pagination.Page) (*pagination.Result[*dao.Invitation], error)
+	ListByTeam(teamID uuid.UUID, page pagination.Page) (*pagination.Result[*dao.Invitation], error)
 	RespondInvitation(input *RespondInvitationInput) (*dao.Invitation, error)
 	CancelInvitation(id uuid.UUID) error
+	// RejectExpired 将到期仍未回应的邀请自动标记为拒绝，返回处理条数
+	RejectExpired() (int, error)
 }
 
 type invitationService struct {
@@ -75,27 +78,18 @@ func (s *invitationService) GetInvitation(id uuid.UUID) (*dao.Invitation, error)
 }
 
 // ListByInvitee 分页获取针对某用户的所有邀请
-func (s *invitationService) ListByInvitee(inviteeID uuid.UUID, page, size int) ([]*dao.Invitation, error) {
-	if page < 1 {
-		page = 1
-	}
-	return s.repo.ListByInvitee(inviteeID, (page-1)*size, size)
+func (s *invitationService) ListByInvitee(inviteeID uuid.UUID, page pagination.Page) (*pagination.Result[*dao.Invitation], error) {
+	return s.repo.ListByInvitee(inviteeID, page)
 }
 
 // ListByInviter 分页获取某用户发出的所有邀请
-func (s *invitationService) ListByInviter(inviterID uuid.UUID, page, size int) ([]*dao.Invitation, error) {
-	if page < 1 {
-		page = 1
-	}
-	return s.repo.ListByInviter(inviterID, (page-1)*size, size)
+func (s *invitationService) ListByInviter(inviterID uuid.UUID, page pagination.Page) (*pagination.Result[*dao.Invitation], error) {
+	return s.repo.ListByInviter(inviterID, page)
 }
 
 // ListByTeam 分页获取针对某团队的所有邀请
-func (s *invitationService) ListByTeam(teamID uuid.UUID, page, size int) ([]*dao.Invitation, error) {
-	if page < 1 {
-		page = 1
-	}
-	return s.repo.ListByTeam(teamID, (page-1)*size, size)
+func (s *invitationService) ListByTeam(teamID uuid.UUID, page pagination.Page) (*pagination.Result[*dao.Invitation], error) {
+	return s.repo.ListByTeam(teamID, page)
 }
 
 // RespondInvitation 接受或拒绝一条邀请
@@ -135,3 +129,9 @@ func (s *invitationService) CancelInvitation(id uuid.UUID) error {
 	inv.Status = dao.InvitationStatusRejected
 	return s.repo.Update(inv)
 }
+
+// RejectExpired 将到期仍未回应的邀请自动标记为拒绝，由后台任务定期调用
+func (s *invitationService) RejectExpired() (int, error) {
+	n, err := s.repo.RejectExpired(time.Now())
+	return int(n), err
+}
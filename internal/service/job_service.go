@@ -0,0 +1,35 @@
+package service
+
+import (
+	"context"
+	"onepenny-server/internal/pagination"
+	"onepenny-server/internal/repository"
+	"onepenny-server/internal/scheduler"
+	"onepenny-server/model/dao"
+)
+
+// JobService 定义后台任务的观测接口，供管理员排查定时任务
+type JobService interface {
+	// Status 返回本实例的调度状态与各任务最近一次执行
+	Status(ctx context.Context) scheduler.Status
+	// ListRuns 分页列出执行记录，job 为空时列出全部任务
+	ListRuns(job string, page pagination.Page) (*pagination.Result[*dao.JobRun], error)
+}
+
+type jobService struct {
+	runRepo repository.JobRunRepo
+	sched   *scheduler.Scheduler
+}
+
+// NewJobService 构造函数
+func NewJobService(runRepo repository.JobRunRepo, sched *scheduler.Scheduler) JobService {
+	return &jobService{runRepo: runRepo, sched: sched}
+}
+
+func (s *jobService) Status(ctx context.Context) scheduler.Status {
+	return s.sched.Status(ctx)
+}
+
+func (s *jobService) ListRuns(job string, page pagination.Page) (*pagination.Result[*dao.JobRun], error) {
+	return s.runRepo.List(job, page)
+}
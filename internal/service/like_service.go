@@ -3,6 +3,7 @@ package service
 
 import (
 	"errors"
+	"onepenny-server/internal/pagination"
 	"onepenny-server/internal/repository"
 	"onepenny-server/model/dao"
 
@@ -22,21 +23,58 @@ type LikeService interface {
 	Unlike(userID, targetID uuid.UUID, targetType string) error
 	Toggle(userID, targetID uuid.UUID, targetType string) (liked bool, err error)
 	HasLiked(userID, targetID uuid.UUID, targetType string) (bool, error)
-	Count(targetID uuid.UUID, targetType string) (int64, error)
-	ListByTarget(targetID uuid.UUID, targetType string, page, size int) ([]*dao.Like, error)
-	ListByUser(userID uuid.UUID, page, size int) ([]*dao.Like, error)
+	// Count 统计点赞数；目标为 viewerID 无权查看的悬赏令或其评论时视为不存在
+	Count(viewerID, targetID uuid.UUID, targetType string) (int64, error)
+	ListByTarget(targetID uuid.UUID, targetType string, page pagination.Page) (*pagination.Result[*dao.Like], error)
+	ListByUser(userID uuid.UUID, page pagination.Page) (*pagination.Result[*dao.Like], error)
 }
 
 type likeService struct {
-	repo repository.LikeRepo
+	repo        repository.LikeRepo
+	bountyRepo  repository.BountyRepo
+	commentRepo repository.CommentRepo
+	feedSvc     FeedService
+	trendSvc    TrendingService
 }
 
 // NewLikeService 构造函数
-func NewLikeService(repo repository.LikeRepo) LikeService {
-	return &likeService{repo: repo}
+func NewLikeService(repo repository.LikeRepo, bountyRepo repository.BountyRepo, commentRepo repository.CommentRepo, feedSvc FeedService, trendSvc TrendingService) LikeService {
+	return &likeService{repo: repo, bountyRepo: bountyRepo, commentRepo: commentRepo, feedSvc: feedSvc, trendSvc: trendSvc}
+}
+
+// recordBountyLike 点赞或取消点赞悬赏令时调整用户的推荐兴趣与悬赏令的热度
+func (s *likeService) recordBountyLike(userID, targetID uuid.UUID, targetType string, liked bool) {
+	if targetType != "bounty" {
+		return
+	}
+	if liked {
+		s.feedSvc.RecordSignal(userID, targetID, FeedSignalLike)
+		s.trendSvc.RecordEngagement(targetID, EngagementLike)
+	} else {
+		s.feedSvc.RecordSignal(userID, targetID, FeedSignalUnlike)
+		s.trendSvc.RecordEngagement(targetID, EngagementUnlike)
+	}
+}
+
+// ensureTargetVisible 点赞目标为悬赏令或评论时，校验 viewerID 能否查看所属悬赏令
+func (s *likeService) ensureTargetVisible(viewerID, targetID uuid.UUID, targetType string) error {
+	switch targetType {
+	case "bounty":
+		return ensureBountyVisible(s.bountyRepo, targetID, viewerID)
+	case "comment":
+		c, err := s.commentRepo.GetByID(targetID)
+		if err != nil {
+			return err
+		}
+		return ensureBountyVisible(s.bountyRepo, c.BountyID, viewerID)
+	}
+	return nil
 }
 
 func (s *likeService) Like(userID, targetID uuid.UUID, targetType string) error {
+	if err := s.ensureTargetVisible(userID, targetID, targetType); err != nil {
+		return err
+	}
 	exists, err := s.repo.Exists(userID, targetID, targetType)
 	if err != nil {
 		return err
@@ -44,14 +82,21 @@ func (s *likeService) Like(userID, targetID uuid.UUID, targetType string) error
 	if exists {
 		return ErrAlreadyLiked
 	}
-	return s.repo.Create(&dao.Like{
+	if err := s.repo.Create(&dao.Like{
 		UserID:       userID,
 		LikeableID:   targetID,
 		LikeableType: targetType,
-	})
+	}); err != nil {
+		return err
+	}
+	s.recordBountyLike(userID, targetID, targetType, true)
+	return nil
 }
 
 func (s *likeService) Unlike(userID, targetID uuid.UUID, targetType string) error {
+	if err := s.ensureTargetVisible(userID, targetID, targetType); err != nil {
+		return err
+	}
 	exists, err := s.repo.Exists(userID, targetID, targetType)
 	if err != nil {
 		return err
@@ -59,10 +104,17 @@ func (s *likeService) Unlike(userID, targetID uuid.UUID, targetType string) erro
 	if !exists {
 		return ErrNotLikedYet
 	}
-	return s.repo.Delete(userID, targetID, targetType)
+	if err := s.repo.Delete(userID, targetID, targetType); err != nil {
+		return err
+	}
+	s.recordBountyLike(userID, targetID, targetType, false)
+	return nil
 }
 
 func (s *likeService) Toggle(userID, targetID uuid.UUID, targetType string) (bool, error) {
+	if err := s.ensureTargetVisible(userID, targetID, targetType); err != nil {
+		return false, err
+	}
 	exists, err := s.repo.Exists(userID, targetID, targetType)
 	if err != nil {
 		return false, err
@@ -71,6 +123,7 @@ func (s *likeService) Toggle(userID, targetID uuid.UUID, targetType string) (boo
 		if err := s.repo.Delete(userID, targetID, targetType); err != nil {
 			return false, err
 		}
+		s.recordBountyLike(userID, targetID, targetType, false)
 		return false, nil
 	}
 	if err := s.repo.Create(&dao.Like{
@@ -80,6 +133,7 @@ func (s *likeService) Toggle(userID, targetID uuid.UUID, targetType string) (boo
 	}); err != nil {
 		return false, err
 	}
+	s.recordBountyLike(userID, targetID, targetType, true)
 	return true, nil
 }
 
@@ -87,20 +141,17 @@ func (s *likeService) HasLiked(userID, targetID uuid.UUID, targetType string) (b
 	return s.repo.Exists(userID, targetID, targetType)
 }
 
-func (s *likeService) Count(targetID uuid.UUID, targetType string) (int64, error) {
+func (s *likeService) Count(viewerID, targetID uuid.UUID, targetType string) (int64, error) {
+	if err := s.ensureTargetVisible(viewerID, targetID, targetType); err != nil {
+		return 0, err
+	}
 	return s.repo.CountByTarget(targetID, targetType)
 }
 
-func (s *likeService) ListByTarget(targetID uuid.UUID, targetType string, page, size int) ([]*dao.Like, error) {
-	if page < 1 {
-		page = 1
-	}
-	return s.repo.ListByTarget(targetID, targetType, (page-1)*size, size)
+func (s *likeService) ListByTarget(targetID uuid.UUID, targetType string, page pagination.Page) (*pagination.Result[*dao.Like], error) {
+	return s.repo.ListByTarget(targetID, targetType, page)
 }
 
-func (s *likeService) ListByUser(userID uuid.UUID, page, size int) ([]*dao.Like, error) {
-	if page < 1 {
-		page = 1
-	}
-	return s.repo.ListByUser(userID, (page-1)*size, size)
+func (s *likeService) ListByUser(userID uuid.UUID, page pagination.Page) (*pagination.Result[*dao.Like], error) {
+	return s.repo.ListByUser(userID, page)
 }
@@ -1,6 +1,7 @@
 package service
 
 import (
+	"onepenny-server/internal/pagination"
 	"onepenny-server/internal/repository"
 	"onepenny-server/model/dao"
 	"time"
@@ -20,7 +21,7 @@ type NotificationService interface {
 	// GetNotification 根据 ID 获取通知
 	GetNotification(id uuid.UUID) (*dao.Notification, error)
 	// ListNotifications 列出某用户的通知，按时间倒序，支持分页
-	ListNotifications(userID uuid.UUID, page, size int) ([]*dao.Notification, error)
+	ListNotifications(userID uuid.UUID, page pagination.Page) (*pagination.Result[*dao.Notification], error)
 	// CountUnread 统计某用户的未读通知数量
 	CountUnread(userID uuid.UUID) (int64, error)
 	// MarkAsRead 标记某条通知为已读
@@ -29,6 +30,8 @@ type NotificationService interface {
 	MarkAllAsRead(userID uuid.UUID) error
 	// DeleteNotification 删除（软删）某条通知
 	DeleteNotification(id uuid.UUID) error
+	// PurgeExpired 清理已过期的通知，返回清理条数
+	PurgeExpired() (int, error)
 }
 
 type notificationService struct {
@@ -77,12 +80,8 @@ func (s *notificationService) GetNotification(id uuid.UUID) (*dao.Notification,
 }
 
 // ListNotifications 列出某用户的通知，page 从 1 开始
-func (s *notificationService) ListNotifications(userID uuid.UUID, page, size int) ([]*dao.Notification, error) {
-	if page < 1 {
-		page = 1
-	}
-	offset := (page - 1) * size
-	return s.repo.ListByUser(userID, offset, size)
+func (s *notificationService) ListNotifications(userID uuid.UUID, page pagination.Page) (*pagination.Result[*dao.Notification], error) {
+	return s.repo.ListByUser(userID, page)
 }
 
 // CountUnread 统计某用户的未读通知数量
@@ -107,23 +106,16 @@ func (s *notificationService) MarkAsRead(id uuid.UUID) error {
 
 // MarkAllAsRead 标记某用户所有通知为已读
 func (s *notificationService) MarkAllAsRead(userID uuid.UUID) error {
-	notes, err := s.repo.ListByUser(userID, 0, 0) // 0,0 表示不分页，取所有
-	if err != nil {
-		return err
-	}
-	for _, n := range notes {
-		if !n.IsRead {
-			n.IsRead = true
-			n.UpdatedAt = time.Now()
-			if err := s.repo.Update(n); err != nil {
-				return err
-			}
-		}
-	}
-	return nil
+	return s.repo.MarkAllRead(userID)
 }
 
 // DeleteNotification 删除（软删）某条通知
 func (s *notificationService) DeleteNotification(id uuid.UUID) error {
 	return s.repo.Delete(id)
 }
+
+// PurgeExpired 物理删除已过期的通知，由后台任务定期调用
+func (s *notificationService) PurgeExpired() (int, error) {
+	n, err := s.repo.PurgeExpired(time.Now())
+	return int(n), err
+}
@@ -0,0 +1,336 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"github.com/google/uuid"
+	"log"
+	"onepenny-server/internal/pagination"
+	"onepenny-server/internal/repository"
+	"onepenny-server/model/dao"
+	"strings"
+	"time"
+)
+
+var (
+	// ErrSavedSearchNotFound 对外暴露的“保存的搜索未找到”错误
+	ErrSavedSearchNotFound = repository.ErrSavedSearchNotFound
+	// ErrNotSavedSearchOwner 只能查看或管理自己保存的搜索
+	ErrNotSavedSearchOwner = errors.New("only the owner can manage this saved search")
+	// ErrSavedSearchNameRequired 名称为空
+	ErrSavedSearchNameRequired = errors.New("saved search name is required")
+	// ErrEmptySavedSearch 检索词与筛选条件都为空，会命中所有悬赏令
+	ErrEmptySavedSearch = errors.New("saved search needs a query or at least one filter")
+	// ErrInvalidDelivery 未定义的通知方式
+	ErrInvalidDelivery = errors.New("delivery must be instant or daily")
+	// ErrTooManySavedSearches 保存的搜索数已达上限
+	ErrTooManySavedSearches = errors.New("too many saved searches")
+)
+
+const (
+	// maxSavedSearches 每个用户最多保存的搜索数
+	maxSavedSearches = 50
+	// maxSavedSearchName 名称的最大长度（字符数）
+	maxSavedSearchName = 100
+	// digestInterval 按天汇总的通知间隔
+	digestInterval = 24 * time.Hour
+	// digestBatchSize 每次后台任务最多汇总的搜索数
+	digestBatchSize = 200
+)
+
+// CreateSavedSearchInput 保存搜索的参数
+type CreateSavedSearchInput struct {
+	UserID   uuid.UUID
+	Name     string
+	Query    string
+	Filter   dao.SavedSearchFilter
+	Delivery dao.SavedSearchDelivery // 为空时立即通知
+}
+
+// UpdateSavedSearchInput 修改保存的搜索，nil 表示不修改
+type UpdateSavedSearchInput struct {
+	Name     *string
+	Query    *string
+	Filter   *dao.SavedSearchFilter
+	Delivery *dao.SavedSearchDelivery
+}
+
+// SavedSearchService 定义保存的搜索相关业务接口。
+// 悬赏令发布时与所有未暂停的搜索比对，命中时立即通知或按天汇总通知
+type SavedSearchService interface {
+	CreateSavedSearch(input *CreateSavedSearchInput) (*dao.SavedSearch, error)
+	GetSavedSearch(id, userID uuid.UUID) (*dao.SavedSearch, error)
+	ListSavedSearches(userID uuid.UUID, page pagination.Page) (*pagination.Result[*dao.SavedSearch], error)
+	// UpdateSavedSearch 重命名或修改条件与通知方式，只影响之后发布的悬赏令
+	UpdateSavedSearch(id, userID uuid.UUID, input *UpdateSavedSearchInput) (*dao.SavedSearch, error)
+	// PauseSavedSearch 暂停后不再比对新发布的悬赏令，恢复后也不补发暂停期间的匹配
+	PauseSavedSearch(id, userID uuid.UUID) (*dao.SavedSearch, error)
+	ResumeSavedSearch(id, userID uuid.UUID) (*dao.SavedSearch, error)
+	DeleteSavedSearch(id, userID uuid.UUID) error
+	// ListMatches 按命中时间倒序列出最近命中的悬赏令，不含已不可见的
+	ListMatches(id, userID uuid.UUID, page pagination.Page) (*pagination.Result[*dao.SavedSearchMatch], error)
+
+	// MatchBounty 悬赏令发布后与保存的搜索比对并记录命中；失败只记录日志
+	MatchBounty(b *dao.Bounty)
+	// SendDigests 为按天汇总的搜索发送待通知命中的汇总，返回发送的通知数，供后台任务调用
+	SendDigests() (int, error)
+}
+
+type savedSearchService struct {
+	repo     repository.SavedSearchRepo
+	tagSvc   TagService
+	catSvc   CategoryService
+	notifSvc NotificationService
+}
+
+// NewSavedSearchService 构造函数
+func NewSavedSearchService(repo repository.SavedSearchRepo, tagSvc TagService, catSvc CategoryService, notifSvc NotificationService) SavedSearchService {
+	return &savedSearchService{repo: repo, tagSvc: tagSvc, catSvc: catSvc, notifSvc: notifSvc}
+}
+
+func (s *savedSearchService) CreateSavedSearch(input *CreateSavedSearchInput) (*dao.SavedSearch, error) {
+	n, err := s.repo.CountByUser(input.UserID)
+	if err != nil {
+		return nil, err
+	}
+	if n >= maxSavedSearches {
+		return nil, ErrTooManySavedSearches
+	}
+
+	ss := &dao.SavedSearch{UserID: input.UserID, Delivery: dao.SavedSearchDeliveryInstant}
+	if err := s.apply(ss, &UpdateSavedSearchInput{
+		Name:     &input.Name,
+		Query:    &input.Query,
+		Filter:   &input.Filter,
+		Delivery: &input.Delivery,
+	}); err != nil {
+		return nil, err
+	}
+	if err := s.repo.Create(ss); err != nil {
+		return nil, err
+	}
+	return ss, nil
+}
+
+func (s *savedSearchService) GetSavedSearch(id, userID uuid.UUID) (*dao.SavedSearch, error) {
+	ss, err := s.repo.GetByID(id)
+	if err != nil {
+		return nil, err
+	}
+	if ss.UserID != userID {
+		return nil, ErrNotSavedSearchOwner
+	}
+	return ss, nil
+}
+
+func (s *savedSearchService) ListSavedSearches(userID uuid.UUID, page pagination.Page) (*pagination.Result[*dao.SavedSearch], error) {
+	return s.repo.ListByUser(userID, page)
+}
+
+func (s *savedSearchService) UpdateSavedSearch(id, userID uuid.UUID, input *UpdateSavedSearchInput) (*dao.SavedSearch, error) {
+	ss, err := s.GetSavedSearch(id, userID)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.apply(ss, input); err != nil {
+		return nil, err
+	}
+	if err := s.repo.Update(ss); err != nil {
+		return nil, err
+	}
+	return ss, nil
+}
+
+// apply 校验并写入修改：检索词按全文搜索的规则生成 tsquery，分类须存在，标签归并为规范写法
+func (s *savedSearchService) apply(ss *dao.SavedSearch, input *UpdateSavedSearchInput) error {
+	if input.Name != nil {
+		name := strings.TrimSpace(*input.Name)
+		if name == "" {
+			return ErrSavedSearchNameRequired
+		}
+		ss.Name = truncateRunes(name, maxSavedSearchName)
+	}
+	if input.Query != nil {
+		q := strings.TrimSpace(*input.Query)
+		terms := searchTerms(q)
+		if q != "" && len(terms) == 0 {
+			return ErrEmptySearchQuery
+		}
+		ss.Query = truncateRunes(q, 255)
+		ss.TSQuery = buildTSQuery(terms)
+	}
+	if input.Filter != nil {
+		f := *input.Filter
+		for _, c := range f.Categories {
+			if err := s.catSvc.Validate(c); err != nil {
+				return err
+			}
+		}
+		tags, err := s.tagSvc.Canonical(f.Tags)
+		if err != nil {
+			return err
+		}
+		f.Tags = tags
+		f.Currency = strings.ToUpper(strings.TrimSpace(f.Currency))
+		f.Location = strings.TrimSpace(f.Location)
+		if (f.NearLat == nil) != (f.NearLng == nil) {
+			return ErrInvalidCoordinates
+		}
+		if _, err := toRepoFilter(toBountyFilter(&f), s.catSvc, s.tagSvc); err != nil {
+			return err
+		}
+		ss.Filter = f
+	}
+	if input.Delivery != nil {
+		switch *input.Delivery {
+		case "":
+		case dao.SavedSearchDeliveryInstant, dao.SavedSearchDeliveryDaily:
+			ss.Delivery = *input.Delivery
+		default:
+			return ErrInvalidDelivery
+		}
+	}
+	if ss.TSQuery == "" && isEmptyFilter(&ss.Filter) {
+		return ErrEmptySavedSearch
+	}
+	return nil
+}
+
+// toBountyFilter 将保存的筛选条件转为悬赏令列表的筛选条件
+func toBountyFilter(f *dao.SavedSearchFilter) *BountyFilter {
+	bf := &BountyFilter{
+		Location:   f.Location,
+		Categories: f.Categories,
+		Tags:       f.Tags,
+		AllTags:    f.AllTags,
+		Currency:   f.Currency,
+		Priorities: f.Priorities,
+		MinReward:  f.MinReward,
+		MaxReward:  f.MaxReward,
+	}
+	if f.NearLat != nil && f.NearLng != nil {
+		bf.Near = &GeoPoint{Lat: *f.NearLat, Lng: *f.NearLng}
+		bf.RadiusKm = f.RadiusKm
+	}
+	return bf
+}
+
+func isEmptyFilter(f *dao.SavedSearchFilter) bool {
+	return f.Location == "" && f.NearLat == nil && len(f.Categories) == 0 && len(f.Tags) == 0 &&
+		f.Currency == "" && len(f.Priorities) == 0 && f.MinReward == nil && f.MaxReward == nil
+}
+
+func (s *savedSearchService) PauseSavedSearch(id, userID uuid.UUID) (*dao.SavedSearch, error) {
+	return s.setPaused(id, userID, true)
+}
+
+func (s *savedSearchService) ResumeSavedSearch(id, userID uuid.UUID) (*dao.SavedSearch, error) {
+	return s.setPaused(id, userID, false)
+}
+
+func (s *savedSearchService) setPaused(id, userID uuid.UUID, paused bool) (*dao.SavedSearch, error) {
+	ss, err := s.GetSavedSearch(id, userID)
+	if err != nil {
+		return nil, err
+	}
+	if ss.Paused == paused {
+		return ss, nil
+	}
+	ss.Paused = paused
+	if err := s.repo.Update(ss); err != nil {
+		return nil, err
+	}
+	return ss, nil
+}
+
+func (s *savedSearchService) DeleteSavedSearch(id, userID uuid.UUID) error {
+	if _, err := s.GetSavedSearch(id, userID); err != nil {
+		return err
+	}
+	return s.repo.Delete(id)
+}
+
+func (s *savedSearchService) ListMatches(id, userID uuid.UUID, page pagination.Page) (*pagination.Result[*dao.SavedSearchMatch], error) {
+	if _, err := s.GetSavedSearch(id, userID); err != nil {
+		return nil, err
+	}
+	return s.repo.ListMatches(id, userID, page)
+}
+
+func (s *savedSearchService) MatchBounty(b *dao.Bounty) {
+	candidates, err := s.repo.ListCandidates(b.ID, b.UserID)
+	if err != nil {
+		log.Printf("bounty %s: list saved searches: %v", b.ID, err)
+		return
+	}
+	for _, ss := range candidates {
+		if err := s.match(b, ss); err != nil {
+			log.Printf("bounty %s: match saved search %s: %v", b.ID, ss.ID, err)
+		}
+	}
+}
+
+// match 比对一条保存的搜索；分类在比对时展开，使之后新增的子分类也能命中
+func (s *savedSearchService) match(b *dao.Bounty, ss *dao.SavedSearch) error {
+	f, err := toRepoFilter(toBountyFilter(&ss.Filter), s.catSvc, s.tagSvc)
+	if err != nil {
+		return err
+	}
+	ok, err := s.repo.Matches(b.ID, ss.UserID, f)
+	if err != nil || !ok {
+		return err
+	}
+
+	m := &dao.SavedSearchMatch{SavedSearchID: ss.ID, BountyID: b.ID}
+	instant := ss.Delivery != dao.SavedSearchDeliveryDaily
+	if instant {
+		now := time.Now()
+		m.NotifiedAt = &now
+	}
+	created, err := s.repo.RecordMatch(m)
+	if err != nil || !created || !instant {
+		return err
+	}
+	_, err = s.notifSvc.SendNotification(&SendNotificationInput{
+		UserID:      ss.UserID,
+		ActorID:     &b.UserID,
+		Type:        dao.NotificationTypeSavedSearch,
+		Title:       "保存的搜索有新匹配",
+		Description: "「" + ss.Name + "」：" + b.Title,
+		RelatedID:   &b.ID,
+		RelatedType: "bounty",
+		Metadata:    map[string]interface{}{"saved_search_id": ss.ID},
+	})
+	return err
+}
+
+func (s *savedSearchService) SendDigests() (int, error) {
+	now := time.Now()
+	due, err := s.repo.ListDigestDue(now.Add(-digestInterval), digestBatchSize)
+	if err != nil {
+		return 0, err
+	}
+	sent := 0
+	for _, ss := range due {
+		n, err := s.repo.MarkNotified(ss.ID, now)
+		if err != nil {
+			return sent, err
+		}
+		if n == 0 {
+			continue
+		}
+		if _, err := s.notifSvc.SendNotification(&SendNotificationInput{
+			UserID:      ss.UserID,
+			Type:        dao.NotificationTypeSavedSearch,
+			Title:       "保存的搜索有新匹配",
+			Description: fmt.Sprintf("「%s」有 %d 个新匹配的悬赏令", ss.Name, n),
+			RelatedID:   &ss.ID,
+			RelatedType: "saved_search",
+			Metadata:    map[string]interface{}{"count": n},
+		}); err != nil {
+			return sent, err
+		}
+		sent++
+	}
+	return sent, nil
+}
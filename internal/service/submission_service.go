@@ -0,0 +1,112 @@
+package service
+
+import (
+	"github.com/google/uuid"
+	"onepenny-server/internal/pagination"
+	"onepenny-server/internal/repository"
+	"onepenny-server/model/dao"
+)
+
+// SubmissionService 定义交付物提交与审核相关业务接口
+type SubmissionService interface {
+	Submit(input *SubmitInput) (*dao.Submission, error)
+	ListByBounty(bountyID, viewerID uuid.UUID, page pagination.Page) (*pagination.Result[*dao.Submission], error)
+	Accept(bountyID, submissionID, ownerID uuid.UUID) (*dao.Submission, error)
+	RequestChanges(bountyID, submissionID, ownerID uuid.UUID, comment string) (*dao.Submission, error)
+}
+
+type submissionService struct {
+	repo       repository.SubmissionRepo
+	bountyRepo repository.BountyRepo
+	watchSvc   WatchService
+	notifSvc   NotificationService
+}
+
+// NewSubmissionService 构造函数
+func NewSubmissionService(repo repository.SubmissionRepo, bountyRepo repository.BountyRepo, watchSvc WatchService, notifSvc NotificationService) SubmissionService {
+	return &submissionService{repo: repo, bountyRepo: bountyRepo, watchSvc: watchSvc, notifSvc: notifSvc}
+}
+
+// SubmitInput 提交交付物所需字段
+type SubmitInput struct {
+	BountyID       uuid.UUID
+	SubmitterID    uuid.UUID
+	Content        string
+	AttachmentURLs []string
+}
+
+// Submit 接收者提交交付物并发起结算，通知发布者审核
+func (s *submissionService) Submit(input *SubmitInput) (*dao.Submission, error) {
+	sub, err := s.repo.Create(&repository.CreateSubmissionInput{
+		BountyID:       input.BountyID,
+		SubmitterID:    input.SubmitterID,
+		Content:        input.Content,
+		AttachmentURLs: input.AttachmentURLs,
+	})
+	if err != nil {
+		return nil, err
+	}
+	if b, err := s.bountyRepo.GetByID(sub.BountyID); err == nil {
+		s.notify(sub, &input.SubmitterID, "收到新的交付物", "接收者提交了交付物，请审核："+b.Title, b.UserID)
+	}
+	return sub, nil
+}
+
+// ListByBounty 发布者与接收者查看悬赏令的全部交付历史
+func (s *submissionService) ListByBounty(bountyID, viewerID uuid.UUID, page pagination.Page) (*pagination.Result[*dao.Submission], error) {
+	b, err := s.bountyRepo.GetByID(bountyID)
+	if err != nil {
+		return nil, err
+	}
+	if b.UserID != viewerID && (b.ReceiverID == nil || *b.ReceiverID != viewerID) {
+		return nil, repository.ErrNotBountyParty
+	}
+	return s.repo.ListByBounty(bountyID, page)
+}
+
+// Accept 发布者通过交付物，悬赏令结算并通知接收者
+func (s *submissionService) Accept(bountyID, submissionID, ownerID uuid.UUID) (*dao.Submission, error) {
+	sub, err := s.repo.Accept(bountyID, submissionID, ownerID)
+	if err != nil {
+		return nil, err
+	}
+	s.notify(sub, &ownerID, "交付物已通过", "发布者已通过你的交付物，赏金已发放", sub.SubmitterID)
+	s.watchSvc.NotifyWatchers(&WatchEvent{
+		BountyID: sub.BountyID,
+		ActorID:  &ownerID,
+		Title:    "关注的悬赏令已结算",
+		Detail:   "发布者已通过交付物，赏金已发放给接收者",
+		Exclude:  []uuid.UUID{sub.SubmitterID},
+	})
+	return sub, nil
+}
+
+// RequestChanges 发布者要求修改，悬赏令退回进行中并通知接收者
+func (s *submissionService) RequestChanges(bountyID, submissionID, ownerID uuid.UUID, comment string) (*dao.Submission, error) {
+	sub, err := s.repo.RequestChanges(bountyID, submissionID, ownerID, comment)
+	if err != nil {
+		return nil, err
+	}
+	s.notify(sub, &ownerID, "交付物需要修改", comment, sub.SubmitterID)
+	return sub, nil
+}
+
+// notify 向相关用户发送交付物通知，通知失败不影响主流程
+func (s *submissionService) notify(sub *dao.Submission, actorID *uuid.UUID, title, desc string, userIDs ...uuid.UUID) {
+	for _, uid := range userIDs {
+		_, _ = s.notifSvc.SendNotification(&SendNotificationInput{
+			UserID:      uid,
+			ActorID:     actorID,
+			Type:        dao.NotificationTypeSubmission,
+			Title:       title,
+			Description: desc,
+			RelatedID:   &sub.BountyID,
+			RelatedType: "bounty",
+			Metadata: map[string]interface{}{
+				"submission_id": sub.ID,
+				"version":       sub.Version,
+				"status":        sub.Status,
+			},
+		})
+	}
+}
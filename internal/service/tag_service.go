@@ -0,0 +1,227 @@
+package service
+
+import (
+	"errors"
+	"github.com/google/uuid"
+	"log"
+	"onepenny-server/internal/pagination"
+	"onepenny-server/internal/repository"
+	"onepenny-server/model/dao"
+	"strings"
+	"unicode"
+)
+
+var (
+	// ErrTagNotFound 找不到对应的标签
+	ErrTagNotFound = repository.ErrTagNotFound
+	// ErrTagAliasTaken 别名已属于另一个标签
+	ErrTagAliasTaken = repository.ErrTagAliasTaken
+	// ErrInvalidTag 标签不含任何字母或数字
+	ErrInvalidTag = errors.New("tag must contain letters or digits")
+)
+
+// maxTagLength 标签 Slug 的最大字符数，超出部分截断
+const maxTagLength = 50
+
+// TagService 标签的归一化、自动补全与关注
+type TagService interface {
+	// Normalize 将用户输入的标签转换为规范 Slug 并去重：大小写、空格与连字符不同或互为别名的写法
+	// 归并为同一标签，未知标签自动创建
+	Normalize(raw []string) ([]string, error)
+	// Canonical 与 Normalize 相同但不创建标签，用于筛选条件
+	Canonical(raw []string) ([]string, error)
+	// Autocomplete 按前缀补全标签，使用次数多的在前；前缀为空时列出最热门的标签
+	Autocomplete(prefix string, page pagination.Page) (*pagination.Result[*dao.Tag], error)
+	// Recount 悬赏令的标签增删后重新统计使用次数
+	Recount(slugs []string) error
+	// AddAlias 管理员为标签添加别名；别名恰为另一个标签时将其合并
+	AddAlias(tag, alias string) (*dao.Tag, error)
+
+	Follow(userID uuid.UUID, tag string) (*dao.Tag, error)
+	Unfollow(userID uuid.UUID, tag string) error
+	ListFollowing(userID uuid.UUID, page pagination.Page) (*pagination.Result[*dao.Tag], error)
+	// NotifyFollowers 悬赏令发布后通知关注了其任一标签的用户，发布者除外；仅限公开的悬赏令
+	NotifyFollowers(b *dao.Bounty)
+}
+
+type tagService struct {
+	repo     repository.TagRepo
+	notifSvc NotificationService
+}
+
+// NewTagService 构造函数
+func NewTagService(repo repository.TagRepo, notifSvc NotificationService) TagService {
+	return &tagService{repo: repo, notifSvc: notifSvc}
+}
+
+// tagSlug 计算标签的规范写法：转为小写，字母、数字与 + # 之外的连续字符替换为单个连字符
+func tagSlug(raw string) string {
+	var b strings.Builder
+	n, sep := 0, false
+	for _, r := range strings.ToLower(raw) {
+		if !unicode.IsLetter(r) && !unicode.IsDigit(r) && r != '+' && r != '#' {
+			sep = true
+			continue
+		}
+		if n == maxTagLength {
+			break
+		}
+		if sep && n > 0 {
+			b.WriteByte('-')
+			n++
+		}
+		sep = false
+		b.WriteRune(r)
+		n++
+	}
+	return strings.TrimSuffix(b.String(), "-")
+}
+
+// tagKey 计算归并键：Slug 去掉连字符，使 "go-lang" 与 "golang" 视为同一标签
+func tagKey(slug string) string {
+	return strings.ReplaceAll(slug, "-", "")
+}
+
+func (s *tagService) Normalize(raw []string) ([]string, error) {
+	return s.normalize(raw, true)
+}
+
+func (s *tagService) Canonical(raw []string) ([]string, error) {
+	return s.normalize(raw, false)
+}
+
+func (s *tagService) normalize(raw []string, create bool) ([]string, error) {
+	type entry struct{ name, slug, key string }
+	var entries []entry
+	var keys []string
+	for _, r := range raw {
+		slug := tagSlug(r)
+		if slug == "" {
+			continue
+		}
+		key := tagKey(slug)
+		entries = append(entries, entry{name: strings.TrimSpace(r), slug: slug, key: key})
+		keys = append(keys, key)
+	}
+	found, err := s.repo.Resolve(keys)
+	if err != nil {
+		return nil, err
+	}
+
+	out := make([]string, 0, len(entries))
+	seen := map[string]bool{}
+	for _, e := range entries {
+		slug := e.slug
+		if t := found[e.key]; t != nil {
+			slug = t.Slug
+		} else if create {
+			t, err := s.repo.FirstOrCreate(&dao.Tag{Slug: e.slug, Key: e.key, Name: truncateRunes(e.name, 64)})
+			if err != nil {
+				return nil, err
+			}
+			found[e.key] = t
+			slug = t.Slug
+		}
+		if !seen[slug] {
+			seen[slug] = true
+			out = append(out, slug)
+		}
+	}
+	return out, nil
+}
+
+// resolve 按任意写法查找已有标签
+func (s *tagService) resolve(tag string) (*dao.Tag, error) {
+	key := tagKey(tagSlug(tag))
+	if key == "" {
+		return nil, ErrInvalidTag
+	}
+	found, err := s.repo.Resolve([]string{key})
+	if err != nil {
+		return nil, err
+	}
+	if found[key] == nil {
+		return nil, ErrTagNotFound
+	}
+	return found[key], nil
+}
+
+func (s *tagService) Autocomplete(prefix string, page pagination.Page) (*pagination.Result[*dao.Tag], error) {
+	return s.repo.Autocomplete(tagKey(tagSlug(prefix)), page)
+}
+
+func (s *tagService) Recount(slugs []string) error {
+	return s.repo.Recount(slugs)
+}
+
+func (s *tagService) AddAlias(tag, alias string) (*dao.Tag, error) {
+	t, err := s.resolve(tag)
+	if err != nil {
+		return nil, err
+	}
+	key := tagKey(tagSlug(alias))
+	if key == "" {
+		return nil, ErrInvalidTag
+	}
+	if err := s.repo.AddAlias(t.ID, key); err != nil {
+		return nil, err
+	}
+	return s.repo.GetBySlug(t.Slug)
+}
+
+func (s *tagService) Follow(userID uuid.UUID, tag string) (*dao.Tag, error) {
+	t, err := s.resolve(tag)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.repo.Follow(userID, t.ID); err != nil {
+		return nil, err
+	}
+	return t, nil
+}
+
+func (s *tagService) Unfollow(userID uuid.UUID, tag string) error {
+	t, err := s.resolve(tag)
+	if err != nil {
+		return err
+	}
+	return s.repo.Unfollow(userID, t.ID)
+}
+
+func (s *tagService) ListFollowing(userID uuid.UUID, page pagination.Page) (*pagination.Result[*dao.Tag], error) {
+	follows, err := s.repo.ListFollowing(userID, page)
+	if err != nil {
+		return nil, err
+	}
+	return pagination.Map(follows, func(f *dao.TagFollow) *dao.Tag { return &f.Tag }), nil
+}
+
+func (s *tagService) NotifyFollowers(b *dao.Bounty) {
+	if b.Visibility != dao.BountyVisibilityPublic || len(b.Tags) == 0 {
+		return
+	}
+	ids, err := s.repo.ListFollowerIDs(b.Tags, b.UserID)
+	if err != nil {
+		log.Printf("bounty %s: list tag followers: %v", b.ID, err)
+		return
+	}
+	for _, uid := range ids {
+		_, _ = s.notifSvc.SendNotification(&SendNotificationInput{
+			UserID:      uid,
+			ActorID:     &b.UserID,
+			Type:        dao.NotificationTypeTag,
+			Title:       "关注的标签有新悬赏令",
+			Description: "「" + b.Title + "」标签：" + strings.Join(b.Tags, "、"),
+			RelatedID:   &b.ID,
+			RelatedType: "bounty",
+		})
+	}
+}
+
+// truncateRunes 按字符数截断字符串
+func truncateRunes(s string, n int) string {
+	if r := []rune(s); len(r) > n {
+		return string(r[:n])
+	}
+	return s
+}
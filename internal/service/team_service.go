@@ -2,6 +2,7 @@ package service
 
 import (
 	"github.com/google/uuid"
+	"onepenny-server/internal/pagination"
 	"onepenny-server/internal/repository"
 	"onepenny-server/model/dao"
 )
@@ -13,13 +14,13 @@ var ErrTeamNotFound = repository.ErrTeamNotFound
 type TeamService interface {
 	CreateTeam(input *CreateTeamInput) (*dao.Team, error)
 	GetTeam(id uuid.UUID) (*dao.Team, error)
-	ListTeamsByOwner(ownerID uuid.UUID, page, size int) ([]*dao.Team, error)
+	ListTeamsByOwner(ownerID uuid.UUID, page pagination.Page) (*pagination.Result[*dao.Team], error)
 	UpdateTeam(id uuid.UUID, input *UpdateTeamInput) (*dao.Team, error)
 	DeleteTeam(id uuid.UUID) error
 
 	AddTeamMember(teamID, userID uuid.UUID) error
 	RemoveTeamMember(teamID, userID uuid.UUID) error
-	ListTeamMembers(teamID uuid.UUID, page, size int) ([]*dao.User, error)
+	ListTeamMembers(teamID uuid.UUID, page pagination.Page) (*pagination.Result[*dao.User], error)
 }
 
 type teamService struct {
@@ -70,12 +71,8 @@ func (s *teamService) GetTeam(id uuid.UUID) (*dao.Team, error) {
 }
 
 // ListTeamsByOwner 分页列出某用户创建的团队
-func (s *teamService) ListTeamsByOwner(ownerID uuid.UUID, page, size int) ([]*dao.Team, error) {
-	if page < 1 {
-		page = 1
-	}
-	offset := (page - 1) * size
-	return s.repo.ListByOwner(ownerID, offset, size)
+func (s *teamService) ListTeamsByOwner(ownerID uuid.UUID, page pagination.Page) (*pagination.Result[*dao.Team], error) {
+	return s.repo.ListByOwner(ownerID, page)
 }
 
 // UpdateTeam 更新团队名称或描述
@@ -112,10 +109,6 @@ func (s *teamService) RemoveTeamMember(teamID, userID uuid.UUID) error {
 }
 
 // ListTeamMembers 分页列出团队成员
-func (s *teamService) ListTeamMembers(teamID uuid.UUID, page, size int) ([]*dao.User, error) {
-	if page < 1 {
-		page = 1
-	}
-	offset := (page - 1) * size
-	return s.repo.ListMembers(teamID, offset, size)
+func (s *teamService) ListTeamMembers(teamID uuid.UUID, page pagination.Page) (*pagination.Result[*dao.User], error) {
+	return s.repo.ListMembers(teamID, page)
 }
@@ -0,0 +1,264 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/go-redis/redis/v8"
+	"github.com/google/uuid"
+	"log"
+	"math"
+	"onepenny-server/internal/pagination"
+	"onepenny-server/internal/repository"
+	"onepenny-server/model/dao"
+	"sort"
+	"strings"
+	"time"
+)
+
+// 参与热度计算的互动类型；取消点赞时扣回点赞的热度
+const (
+	EngagementView        = repository.EngagementView
+	EngagementLike        = repository.EngagementLike
+	EngagementUnlike      = "unlike"
+	EngagementComment     = repository.EngagementComment
+	EngagementApplication = repository.EngagementApplication
+)
+
+// 热度榜的时间窗口
+const (
+	TrendingWindowDay  = "24h"
+	TrendingWindowWeek = "7d"
+)
+
+// ErrInvalidTrendingWindow 未定义的热度榜时间窗口
+var ErrInvalidTrendingWindow = errors.New("window must be 24h or 7d")
+
+// engagementWeights 各互动类型对热度的贡献
+var engagementWeights = map[string]float64{
+	EngagementView:        1,
+	EngagementLike:        3,
+	EngagementUnlike:      -3,
+	EngagementComment:     4,
+	EngagementApplication: 5,
+}
+
+// trendingWindow 时间窗口的长度与衰减半衰期
+type trendingWindow struct {
+	span     time.Duration
+	halfLife time.Duration
+}
+
+var trendingWindows = map[string]trendingWindow{
+	TrendingWindowDay:  {span: 24 * time.Hour, halfLife: 6 * time.Hour},
+	TrendingWindowWeek: {span: 7 * 24 * time.Hour, halfLife: 48 * time.Hour},
+}
+
+const (
+	// trendingBucket 互动按小时分桶累加，读取时按桶的时间衰减后合并
+	trendingBucket = time.Hour
+	// trendingRankTTL 合并后的排行缓存时间
+	trendingRankTTL = time.Minute
+	// trendingMaxRanked 排行最多保留的悬赏令数
+	trendingMaxRanked = 1000
+)
+
+// TrendingBounty 热度榜中的悬赏令及其得分
+type TrendingBounty struct {
+	Bounty *dao.Bounty
+	Score  float64
+}
+
+// TrendingService 按时间衰减后的浏览、点赞、评论与申请数对悬赏令排序。
+// 互动按小时累加到 Redis 有序集合中，读取时按窗口内各小时桶的衰减权重合并
+type TrendingService interface {
+	// Trending 分页列出窗口内热度最高、查看者可见且仍在招募或进行中的悬赏令；
+	// category 非空时只含该分类及其子孙分类
+	Trending(viewerID uuid.UUID, window, category string, page pagination.Page) (*pagination.Result[*TrendingBounty], error)
+	// RecordEngagement 记录一次互动；失败只记录日志
+	RecordEngagement(bountyID uuid.UUID, kind string)
+}
+
+type trendingService struct {
+	repo   repository.TrendingRepo
+	catSvc CategoryService
+	rdb    *redis.Client
+}
+
+// NewTrendingService 构造函数；rdb 为 nil 时每次请求都按数据库计算
+func NewTrendingService(repo repository.TrendingRepo, catSvc CategoryService, rdb *redis.Client) TrendingService {
+	return &trendingService{repo: repo, catSvc: catSvc, rdb: rdb}
+}
+
+// trendingBucketKey 小时桶的键；category 非空时为该分类的桶
+func trendingBucketKey(bucket int64, category string) string {
+	if category == "" {
+		return fmt.Sprintf("onepenny:trending:%d", bucket)
+	}
+	return fmt.Sprintf("onepenny:trending:%d:c:%s", bucket, category)
+}
+
+func (s *trendingService) RecordEngagement(bountyID uuid.UUID, kind string) {
+	if s.rdb == nil {
+		return
+	}
+	if err := s.recordEngagement(context.Background(), bountyID, kind); err != nil {
+		log.Printf("trending: record %s on bounty %s: %v", kind, bountyID, err)
+	}
+}
+
+func (s *trendingService) recordEngagement(ctx context.Context, bountyID uuid.UUID, kind string) error {
+	category, err := s.repo.GetCategory(bountyID)
+	if err != nil {
+		return err
+	}
+	w := engagementWeights[kind]
+	bucket := time.Now().Unix() / int64(trendingBucket/time.Second)
+	// 桶至少保留到最长的窗口结束
+	ttl := trendingWindows[TrendingWindowWeek].span + trendingBucket
+	member := bountyID.String()
+	_, err = s.rdb.TxPipelined(ctx, func(p redis.Pipeliner) error {
+		keys := []string{trendingBucketKey(bucket, "")}
+		if category != "" {
+			keys = append(keys, trendingBucketKey(bucket, category))
+		}
+		for _, k := range keys {
+			p.ZIncrBy(ctx, k, w, member)
+			p.Expire(ctx, k, ttl)
+		}
+		return nil
+	})
+	return err
+}
+
+func (s *trendingService) Trending(viewerID uuid.UUID, window, category string, page pagination.Page) (*pagination.Result[*TrendingBounty], error) {
+	if window == "" {
+		window = TrendingWindowDay
+	}
+	tw, ok := trendingWindows[window]
+	if !ok {
+		return nil, ErrInvalidTrendingWindow
+	}
+	var categories []string
+	if category != "" {
+		var err error
+		if categories, err = s.catSvc.Expand([]string{category}); err != nil {
+			return nil, err
+		}
+	}
+
+	var ranked []repository.TrendingScore
+	var err error
+	if s.rdb == nil {
+		ranked, err = s.repo.Rank(&repository.TrendingRankQuery{
+			ViewerID:   viewerID,
+			Since:      time.Now().Add(-tw.span),
+			HalfLife:   tw.halfLife,
+			Weights:    engagementWeights,
+			Categories: categories,
+			Limit:      trendingMaxRanked,
+		})
+	} else {
+		ranked, err = s.rankCached(context.Background(), viewerID, window, tw, categories)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	skip, err := page.Skip()
+	if err != nil {
+		return nil, err
+	}
+	if skip > len(ranked) {
+		skip = len(ranked)
+	}
+	end := skip + page.Limit()
+	if end > len(ranked) {
+		end = len(ranked)
+	}
+	ids := make([]uuid.UUID, 0, end-skip)
+	for _, r := range ranked[skip:end] {
+		ids = append(ids, r.ID)
+	}
+	list, err := s.repo.GetBounties(ids)
+	if err != nil {
+		return nil, err
+	}
+	byID := make(map[uuid.UUID]*dao.Bounty, len(list))
+	for _, b := range list {
+		byID[b.ID] = b
+	}
+	items := make([]*TrendingBounty, 0, len(ids))
+	for _, r := range ranked[skip:end] {
+		if b := byID[r.ID]; b != nil {
+			items = append(items, &TrendingBounty{Bounty: b, Score: r.Score})
+		}
+	}
+	return pagination.Slice(items, page), nil
+}
+
+// rankCached 合并窗口内的小时桶得到全站排行（缓存一分钟），再按查看者的可见范围过滤
+func (s *trendingService) rankCached(ctx context.Context, viewerID uuid.UUID, window string, tw trendingWindow, categories []string) ([]repository.TrendingScore, error) {
+	sort.Strings(categories)
+	rankKey := "onepenny:trending:rank:" + window + ":" + strings.Join(categories, ",")
+
+	n, err := s.rdb.Exists(ctx, rankKey).Result()
+	if err != nil {
+		return nil, err
+	}
+	if n == 0 {
+		now := time.Now().Unix() / int64(trendingBucket/time.Second)
+		hours := int64(tw.span / trendingBucket)
+		store := &redis.ZStore{}
+		for age := int64(0); age < hours; age++ {
+			// 以桶的中点计算衰减，最新的桶平均只经过半小时
+			weight := math.Pow(0.5, (float64(age)+0.5)*float64(trendingBucket)/float64(tw.halfLife))
+			if len(categories) == 0 {
+				store.Keys = append(store.Keys, trendingBucketKey(now-age, ""))
+				store.Weights = append(store.Weights, weight)
+				continue
+			}
+			for _, c := range categories {
+				store.Keys = append(store.Keys, trendingBucketKey(now-age, c))
+				store.Weights = append(store.Weights, weight)
+			}
+		}
+		if _, err := s.rdb.TxPipelined(ctx, func(p redis.Pipeliner) error {
+			p.ZUnionStore(ctx, rankKey, store)
+			// 取消点赞可能使得分降为非正，不再上榜
+			p.ZRemRangeByScore(ctx, rankKey, "-inf", "0")
+			p.ZRemRangeByRank(ctx, rankKey, 0, -trendingMaxRanked-1)
+			p.Expire(ctx, rankKey, trendingRankTTL)
+			return nil
+		}); err != nil {
+			return nil, err
+		}
+	}
+
+	zs, err := s.rdb.ZRevRangeWithScores(ctx, rankKey, 0, -1).Result()
+	if err != nil {
+		return nil, err
+	}
+	ids := make([]uuid.UUID, 0, len(zs))
+	for _, z := range zs {
+		if id, err := uuid.Parse(z.Member.(string)); err == nil {
+			ids = append(ids, id)
+		}
+	}
+	visible, err := s.repo.FilterTrendable(viewerID, ids)
+	if err != nil {
+		return nil, err
+	}
+	ok := make(map[uuid.UUID]bool, len(visible))
+	for _, id := range visible {
+		ok[id] = true
+	}
+	ranked := make([]repository.TrendingScore, 0, len(visible))
+	for _, z := range zs {
+		id, _ := uuid.Parse(z.Member.(string))
+		if ok[id] {
+			ranked = append(ranked, repository.TrendingScore{ID: id, Score: z.Score})
+		}
+	}
+	return ranked, nil
+}
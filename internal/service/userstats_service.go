@@ -1,7 +1,9 @@
 package service
 
 import (
+	"onepenny-server/internal/pagination"
 	"onepenny-server/internal/repository"
+	"onepenny-server/model/dao"
 	"time"
 
 	"github.com/google/uuid"
@@ -33,11 +35,12 @@ type TotalEarnedResponse struct {
 
 // UserStatsService 提供用户数据统计相关的方法
 type UserStatsService interface {
-	ListMyBountiesByStatus(userID uuid.UUID, status string, page, size int) ([]BountySummary, error)
-	ListApplicationsForMyBounty(userID, bountyID uuid.UUID, page, size int) ([]ApplicationSummary, error)
+	ListMyBountiesByStatus(userID uuid.UUID, status string, page pagination.Page) (*pagination.Result[BountySummary], error)
+	ListApplicationsForMyBounty(userID, bountyID uuid.UUID, page pagination.Page) (*pagination.Result[ApplicationSummary], error)
 	GetTotalEarned(userID uuid.UUID) (float64, error)
-	ListLikedBounties(userID uuid.UUID, page, size int) ([]BountySummary, error)
-	ListViewedBounties(userID uuid.UUID, page, size int) ([]BountySummary, error)
+	GetTotalContributed(userID uuid.UUID) (map[string]float64, error)
+	ListLikedBounties(userID uuid.UUID, page pagination.Page) (*pagination.Result[BountySummary], error)
+	ListViewedBounties(userID uuid.UUID, page pagination.Page) (*pagination.Result[BountySummary], error)
 
 	CountApplications(userID uuid.UUID) (int64, error)
 	CountComments(userID uuid.UUID) (int64, error)
@@ -54,91 +57,67 @@ func NewUserStatsService(repo repository.UserStatsRepo) UserStatsService {
 	return &userStatsService{repo: repo}
 }
 
-func (s *userStatsService) ListMyBountiesByStatus(userID uuid.UUID, status string, page, size int) ([]BountySummary, error) {
-	offset := (page - 1) * size
-	bs, err := s.repo.ListBountiesByUserAndStatus(userID, status, offset, size)
-	if err != nil {
-		return nil, err
+func toBountySummary(b dao.Bounty) BountySummary {
+	return BountySummary{
+		ID:        b.ID,
+		Title:     b.Title,
+		Status:    string(b.Status),
+		Reward:    b.Reward,
+		Currency:  b.Currency,
+		CreatedAt: b.CreatedAt,
+		UpdatedAt: b.UpdatedAt,
 	}
-	res := make([]BountySummary, len(bs))
-	for i, b := range bs {
-		res[i] = BountySummary{
-			ID:        b.ID,
-			Title:     b.Title,
-			Status:    string(b.Status),
-			Reward:    b.Reward,
-			Currency:  b.Currency,
-			CreatedAt: b.CreatedAt,
-			UpdatedAt: b.UpdatedAt,
-		}
+}
+
+func toApplicationSummary(a dao.Application) ApplicationSummary {
+	return ApplicationSummary{
+		ID:        a.ID,
+		BountyID:  a.BountyID,
+		Proposal:  a.Proposal,
+		Status:    string(a.Status),
+		CreatedAt: a.CreatedAt,
+		UpdatedAt: a.UpdatedAt,
 	}
-	return res, nil
 }
 
-func (s *userStatsService) ListApplicationsForMyBounty(userID, bountyID uuid.UUID, page, size int) ([]ApplicationSummary, error) {
-	offset := (page - 1) * size
-	as, err := s.repo.ListApplicationsForBounty(userID, bountyID, offset, size)
+func (s *userStatsService) ListMyBountiesByStatus(userID uuid.UUID, status string, page pagination.Page) (*pagination.Result[BountySummary], error) {
+	res, err := s.repo.ListBountiesByUserAndStatus(userID, status, page)
 	if err != nil {
 		return nil, err
 	}
-	res := make([]ApplicationSummary, len(as))
-	for i, a := range as {
-		res[i] = ApplicationSummary{
-			ID:        a.ID,
-			BountyID:  a.BountyID,
-			Proposal:  a.Proposal,
-			Status:    string(a.Status),
-			CreatedAt: a.CreatedAt,
-			UpdatedAt: a.UpdatedAt,
-		}
+	return pagination.Map(res, toBountySummary), nil
+}
+
+func (s *userStatsService) ListApplicationsForMyBounty(userID, bountyID uuid.UUID, page pagination.Page) (*pagination.Result[ApplicationSummary], error) {
+	res, err := s.repo.ListApplicationsForBounty(userID, bountyID, page)
+	if err != nil {
+		return nil, err
 	}
-	return res, nil
+	return pagination.Map(res, toApplicationSummary), nil
 }
 
 func (s *userStatsService) GetTotalEarned(userID uuid.UUID) (float64, error) {
 	return s.repo.SumEarnedByUser(userID)
 }
 
-func (s *userStatsService) ListLikedBounties(userID uuid.UUID, page, size int) ([]BountySummary, error) {
-	offset := (page - 1) * size
-	bs, err := s.repo.ListLikedBounties(userID, offset, size)
+func (s *userStatsService) GetTotalContributed(userID uuid.UUID) (map[string]float64, error) {
+	return s.repo.SumContributedByUser(userID)
+}
+
+func (s *userStatsService) ListLikedBounties(userID uuid.UUID, page pagination.Page) (*pagination.Result[BountySummary], error) {
+	res, err := s.repo.ListLikedBounties(userID, page)
 	if err != nil {
 		return nil, err
 	}
-	res := make([]BountySummary, len(bs))
-	for i, b := range bs {
-		res[i] = BountySummary{
-			ID:        b.ID,
-			Title:     b.Title,
-			Status:    string(b.Status),
-			Reward:    b.Reward,
-			Currency:  b.Currency,
-			CreatedAt: b.CreatedAt,
-			UpdatedAt: b.UpdatedAt,
-		}
-	}
-	return res, nil
+	return pagination.Map(res, toBountySummary), nil
 }
 
-func (s *userStatsService) ListViewedBounties(userID uuid.UUID, page, size int) ([]BountySummary, error) {
-	offset := (page - 1) * size
-	bs, err := s.repo.ListViewedBounties(userID, offset, size)
+func (s *userStatsService) ListViewedBounties(userID uuid.UUID, page pagination.Page) (*pagination.Result[BountySummary], error) {
+	res, err := s.repo.ListViewedBounties(userID, page)
 	if err != nil {
 		return nil, err
 	}
-	res := make([]BountySummary, len(bs))
-	for i, b := range bs {
-		res[i] = BountySummary{
-			ID:        b.ID,
-			Title:     b.Title,
-			Status:    string(b.Status),
-			Reward:    b.Reward,
-			Currency:  b.Currency,
-			CreatedAt: b.CreatedAt,
-			UpdatedAt: b.UpdatedAt,
-		}
-	}
-	return res, nil
+	return pagination.Map(res, toBountySummary), nil
 }
 
 func (s *userStatsService) CountApplications(userID uuid.UUID) (int64, error) {
@@ -0,0 +1,257 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/go-redis/redis/v8"
+	"github.com/google/uuid"
+	"log"
+	"onepenny-server/internal/repository"
+	"onepenny-server/model/dao"
+	"strconv"
+	"strings"
+	"time"
+)
+
+var (
+	// ErrInvalidViewInterval 浏览统计的分桶粒度只能是 hour 或 day
+	ErrInvalidViewInterval = errors.New("interval must be hour or day")
+	// ErrTooManyViewBuckets 统计区间按所选粒度划分后的桶数过多
+	ErrTooManyViewBuckets = errors.New("time range too long for the interval")
+)
+
+const (
+	// viewBufferKey 待写入数据库的浏览记录，每项为 "悬赏令ID|用户ID|毫秒时间戳"
+	viewBufferKey = "onepenny:views:buffer"
+	// viewFlushBatch 每批从缓冲区写入数据库的浏览记录数
+	viewFlushBatch = 500
+	// viewMaxBuckets 单次浏览统计最多返回的桶数
+	viewMaxBuckets = 1000
+)
+
+func viewSeenKey(bountyID, userID uuid.UUID) string {
+	return "onepenny:views:seen:" + bountyID.String() + ":" + userID.String()
+}
+
+// viewIntervals 各分桶粒度对应的时长与未指定起始时间时的默认统计跨度
+var viewIntervals = map[string]struct{ step, span time.Duration }{
+	repository.ViewIntervalHour: {time.Hour, 48 * time.Hour},
+	repository.ViewIntervalDay:  {24 * time.Hour, 30 * 24 * time.Hour},
+}
+
+// ViewAnalytics 悬赏令在一段时间内的浏览统计，Buckets 覆盖整个区间，没有浏览的桶计为零
+type ViewAnalytics struct {
+	Interval      string
+	From          time.Time
+	To            time.Time
+	Views         int64
+	UniqueViewers int64
+	Buckets       []repository.ViewBucket
+}
+
+// ViewService 记录悬赏令浏览并为发布者提供浏览统计。
+// 同一用户在去重窗口内重复查看只记一次；浏览先缓冲在 Redis 中，由定时任务批量写入数据库并累加浏览数
+type ViewService interface {
+	// RecordView 记录用户查看了悬赏令，发布者本人不计；失败只记录日志
+	RecordView(b *dao.Bounty, viewerID uuid.UUID)
+	// FlushViews 将缓冲区中的浏览记录批量写入数据库，返回写入条数
+	FlushViews() (int, error)
+	// ViewAnalytics 按小时或天统计发布者自己悬赏令的浏览数与独立访客数，from、to 为空时取默认跨度
+	ViewAnalytics(bountyID, ownerID uuid.UUID, interval string, from, to *time.Time) (*ViewAnalytics, error)
+}
+
+type viewService struct {
+	repo       repository.ViewRepo
+	bountyRepo repository.BountyRepo
+	feedSvc    FeedService
+	trendSvc   TrendingService
+	rdb        *redis.Client
+	window     time.Duration
+}
+
+// NewViewService 构造函数；rdb 为 nil 时按数据库去重并直接写入
+func NewViewService(repo repository.ViewRepo, bountyRepo repository.BountyRepo, feedSvc FeedService, trendSvc TrendingService, rdb *redis.Client, window time.Duration) ViewService {
+	return &viewService{
+		repo:       repo,
+		bountyRepo: bountyRepo,
+		feedSvc:    feedSvc,
+		trendSvc:   trendSvc,
+		rdb:        rdb,
+		window:     window,
+	}
+}
+
+func (s *viewService) RecordView(b *dao.Bounty, viewerID uuid.UUID) {
+	if b.UserID == viewerID {
+		return
+	}
+	counted, err := s.recordView(b.ID, viewerID, time.Now())
+	if err != nil {
+		log.Printf("bounty %s: record view by %s: %v", b.ID, viewerID, err)
+		return
+	}
+	if !counted {
+		return
+	}
+	s.feedSvc.RecordSignal(viewerID, b.ID, FeedSignalView)
+	s.trendSvc.RecordEngagement(b.ID, EngagementView)
+}
+
+// recordView 去重后写入缓冲区，返回这次浏览是否计数
+func (s *viewService) recordView(bountyID, viewerID uuid.UUID, at time.Time) (bool, error) {
+	if s.rdb == nil {
+		seen, err := s.repo.HasViewedSince(bountyID, viewerID, at.Add(-s.window))
+		if err != nil || seen {
+			return false, err
+		}
+		return true, s.repo.Record([]*dao.BountyView{newBountyView(bountyID, viewerID, at)})
+	}
+
+	ctx := context.Background()
+	fresh, err := s.rdb.SetNX(ctx, viewSeenKey(bountyID, viewerID), 1, s.window).Result()
+	if err != nil || !fresh {
+		return false, err
+	}
+	entry := fmt.Sprintf("%s|%s|%d", bountyID, viewerID, at.UnixMilli())
+	return true, s.rdb.RPush(ctx, viewBufferKey, entry).Err()
+}
+
+func newBountyView(bountyID, userID uuid.UUID, at time.Time) *dao.BountyView {
+	v := &dao.BountyView{BountyID: bountyID, UserID: userID, ViewedAt: at}
+	// 热度与推荐按写入时间统计浏览，与实际浏览时间保持一致
+	v.CreatedAt = at
+	return v
+}
+
+// parseViewEntry 解析缓冲区中的一项
+func parseViewEntry(entry string) (*dao.BountyView, error) {
+	parts := strings.Split(entry, "|")
+	if len(parts) != 3 {
+		return nil, fmt.Errorf("malformed view entry %q", entry)
+	}
+	bountyID, err := uuid.Parse(parts[0])
+	if err != nil {
+		return nil, err
+	}
+	userID, err := uuid.Parse(parts[1])
+	if err != nil {
+		return nil, err
+	}
+	ms, err := strconv.ParseInt(parts[2], 10, 64)
+	if err != nil {
+		return nil, err
+	}
+	return newBountyView(bountyID, userID, time.UnixMilli(ms)), nil
+}
+
+func (s *viewService) FlushViews() (int, error) {
+	if s.rdb == nil {
+		return 0, nil
+	}
+	ctx := context.Background()
+	total := 0
+	for {
+		var batch *redis.StringSliceCmd
+		if _, err := s.rdb.TxPipelined(ctx, func(p redis.Pipeliner) error {
+			batch = p.LRange(ctx, viewBufferKey, 0, viewFlushBatch-1)
+			p.LTrim(ctx, viewBufferKey, viewFlushBatch, -1)
+			return nil
+		}); err != nil {
+			return total, err
+		}
+		entries := batch.Val()
+		if len(entries) == 0 {
+			return total, nil
+		}
+
+		views := make([]*dao.BountyView, 0, len(entries))
+		for _, e := range entries {
+			v, err := parseViewEntry(e)
+			if err != nil {
+				log.Printf("views: drop %v", err)
+				continue
+			}
+			views = append(views, v)
+		}
+		if err := s.repo.Record(views); err != nil {
+			// 放回缓冲区，下次再写
+			args := make([]interface{}, len(entries))
+			for i, e := range entries {
+				args[i] = e
+			}
+			if perr := s.rdb.RPush(ctx, viewBufferKey, args...).Err(); perr != nil {
+				log.Printf("views: requeue %d entries: %v", len(entries), perr)
+			}
+			return total, err
+		}
+		total += len(views)
+		if len(entries) < viewFlushBatch {
+			return total, nil
+		}
+	}
+}
+
+func (s *viewService) ViewAnalytics(bountyID, ownerID uuid.UUID, interval string, from, to *time.Time) (*ViewAnalytics, error) {
+	if interval == "" {
+		interval = repository.ViewIntervalDay
+	}
+	iv, ok := viewIntervals[interval]
+	if !ok {
+		return nil, ErrInvalidViewInterval
+	}
+	b, err := s.bountyRepo.GetByID(bountyID)
+	if err != nil {
+		return nil, err
+	}
+	if b.UserID != ownerID {
+		return nil, repository.ErrNotBountyOwner
+	}
+
+	end := time.Now().UTC()
+	if to != nil {
+		end = to.UTC()
+	}
+	start := end.Add(-iv.span)
+	if from != nil {
+		start = from.UTC()
+	}
+	if start.After(end) {
+		return nil, ErrInvalidRange
+	}
+	start = truncateView(start, iv.step)
+	if end.Sub(start)/iv.step >= viewMaxBuckets {
+		return nil, ErrTooManyViewBuckets
+	}
+
+	stats, err := s.repo.Stats(bountyID, start, end, interval)
+	if err != nil {
+		return nil, err
+	}
+	byStart := make(map[int64]repository.ViewBucket, len(stats.Buckets))
+	for _, bk := range stats.Buckets {
+		byStart[bk.Start.Unix()] = bk
+	}
+	res := &ViewAnalytics{
+		Interval:      interval,
+		From:          start,
+		To:            end,
+		Views:         stats.Views,
+		UniqueViewers: stats.UniqueViewers,
+	}
+	for t := start; t.Before(end); t = t.Add(iv.step) {
+		bk := byStart[t.Unix()]
+		bk.Start = t
+		res.Buckets = append(res.Buckets, bk)
+	}
+	return res, nil
+}
+
+// truncateView 按 UTC 向下取整到桶的起点，与数据库 date_trunc 的结果一致
+func truncateView(t time.Time, step time.Duration) time.Time {
+	t = t.UTC()
+	if step == 24*time.Hour {
+		return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+	}
+	return t.Truncate(step)
+}
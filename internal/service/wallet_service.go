@@ -0,0 +1,59 @@
+package service
+
+import (
+	"errors"
+	"github.com/google/uuid"
+	"onepenny-server/internal/pagination"
+	"onepenny-server/internal/repository"
+	"onepenny-server/model/dao"
+	"strings"
+)
+
+var (
+	// ErrInsufficientBalance 钱包余额不足
+	ErrInsufficientBalance = repository.ErrInsufficientBalance
+	// ErrInvalidAmount 金额必须为正数
+	ErrInvalidAmount = errors.New("amount must be positive")
+)
+
+// WalletService 定义钱包与账本相关业务接口
+type WalletService interface {
+	ListWallets(userID uuid.UUID) ([]*dao.Wallet, error)
+	ListEntries(userID uuid.UUID, page pagination.Page) (*pagination.Result[*dao.LedgerEntry], error)
+	Deposit(input *DepositInput) (*dao.Wallet, error)
+}
+
+type walletService struct {
+	repo repository.LedgerRepo
+}
+
+// NewWalletService 构造函数
+func NewWalletService(repo repository.LedgerRepo) WalletService {
+	return &walletService{repo: repo}
+}
+
+// DepositInput 充值所需字段
+type DepositInput struct {
+	UserID   uuid.UUID
+	Currency string
+	Amount   float64
+}
+
+// ListWallets 列出用户在各币种下的钱包余额
+func (s *walletService) ListWallets(userID uuid.UUID) ([]*dao.Wallet, error) {
+	return s.repo.ListWallets(userID)
+}
+
+// ListEntries 分页列出用户钱包的流水，按时间倒序
+func (s *walletService) ListEntries(userID uuid.UUID, page pagination.Page) (*pagination.Result[*dao.LedgerEntry], error) {
+	return s.repo.ListEntries(userID, page)
+}
+
+// Deposit 充值到用户钱包，从系统账户直接记账。
+// 只对管理员开放，用于人工入账；接入支付渠道后应改为由校验过的支付回调触发。
+func (s *walletService) Deposit(input *DepositInput) (*dao.Wallet, error) {
+	if input.Amount <= 0 {
+		return nil, ErrInvalidAmount
+	}
+	return s.repo.Deposit(input.UserID, strings.ToUpper(input.Currency), input.Amount)
+}
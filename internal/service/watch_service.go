@@ -0,0 +1,116 @@
+package service
+
+import (
+	"github.com/google/uuid"
+	"log"
+	"onepenny-server/internal/pagination"
+	"onepenny-server/internal/repository"
+	"onepenny-server/model/dao"
+)
+
+// WatchEvent 需要通知关注者的悬赏令动态
+type WatchEvent struct {
+	BountyID uuid.UUID
+	ActorID  *uuid.UUID // 操作者，不会收到通知；系统操作时为空
+	Title    string
+	Detail   string      // 通知正文，其后附悬赏令标题
+	Exclude  []uuid.UUID // 已单独收到通知的用户
+}
+
+// WatchService 定义悬赏令关注相关业务接口
+type WatchService interface {
+	// Watch 关注自己可见的悬赏令，重复关注不报错
+	Watch(bountyID, userID uuid.UUID) error
+	Unwatch(bountyID, userID uuid.UUID) error
+	IsWatching(bountyID, userID uuid.UUID) (bool, error)
+	ListWatching(userID uuid.UUID, page pagination.Page) (*pagination.Result[*dao.BountyWatch], error)
+	// AutoWatch 发布者与评论者自动关注；失败只记录日志
+	AutoWatch(bountyID, userID uuid.UUID)
+	// NotifyWatchers 通知仍可查看该悬赏令的关注者，操作者与已单独通知过的用户除外
+	NotifyWatchers(ev *WatchEvent)
+}
+
+type watchService struct {
+	repo       repository.WatchRepo
+	bountyRepo repository.BountyRepo
+	notifSvc   NotificationService
+}
+
+// NewWatchService 构造函数
+func NewWatchService(repo repository.WatchRepo, bountyRepo repository.BountyRepo, notifSvc NotificationService) WatchService {
+	return &watchService{repo: repo, bountyRepo: bountyRepo, notifSvc: notifSvc}
+}
+
+func (s *watchService) Watch(bountyID, userID uuid.UUID) error {
+	if err := ensureBountyVisible(s.bountyRepo, bountyID, userID); err != nil {
+		return err
+	}
+	_, err := s.repo.Watch(bountyID, userID)
+	return err
+}
+
+func (s *watchService) Unwatch(bountyID, userID uuid.UUID) error {
+	return s.repo.Unwatch(bountyID, userID)
+}
+
+func (s *watchService) IsWatching(bountyID, userID uuid.UUID) (bool, error) {
+	if err := ensureBountyVisible(s.bountyRepo, bountyID, userID); err != nil {
+		return false, err
+	}
+	return s.repo.IsWatching(bountyID, userID)
+}
+
+func (s *watchService) ListWatching(userID uuid.UUID, page pagination.Page) (*pagination.Result[*dao.BountyWatch], error) {
+	return s.repo.ListWatching(userID, page)
+}
+
+func (s *watchService) AutoWatch(bountyID, userID uuid.UUID) {
+	if _, err := s.repo.Watch(bountyID, userID); err != nil {
+		log.Printf("bounty %s: auto-watch for %s: %v", bountyID, userID, err)
+	}
+}
+
+func (s *watchService) NotifyWatchers(ev *WatchEvent) {
+	ids, err := s.repo.ListWatcherIDs(ev.BountyID)
+	if err != nil {
+		log.Printf("bounty %s: list watchers: %v", ev.BountyID, err)
+		return
+	}
+	skip := map[uuid.UUID]bool{}
+	if ev.ActorID != nil {
+		skip[*ev.ActorID] = true
+	}
+	for _, uid := range ev.Exclude {
+		skip[uid] = true
+	}
+	var recipients []uuid.UUID
+	for _, uid := range ids {
+		if !skip[uid] {
+			recipients = append(recipients, uid)
+		}
+	}
+	if len(recipients) == 0 {
+		return
+	}
+
+	b, err := s.bountyRepo.GetByID(ev.BountyID)
+	if err != nil {
+		log.Printf("bounty %s: notify watchers: %v", ev.BountyID, err)
+		return
+	}
+	for _, uid := range recipients {
+		// 可见范围可能在关注之后收窄
+		if ok, err := s.bountyRepo.CanView(b.ID, uid); err != nil || !ok {
+			continue
+		}
+		_, _ = s.notifSvc.SendNotification(&SendNotificationInput{
+			UserID:      uid,
+			ActorID:     ev.ActorID,
+			Type:        dao.NotificationTypeWatch,
+			Title:       ev.Title,
+			Description: ev.Detail + "：" + b.Title,
+			RelatedID:   &b.ID,
+			RelatedType: "bounty",
+		})
+	}
+}
@@ -6,6 +6,7 @@
 package main
 
 import (
+	"context"
 	"github.com/gin-gonic/gin"
 	"github.com/spf13/viper"
 	swaggerFiles "github.com/swaggo/files"
@@ -15,16 +16,28 @@ import (
 	applicationCtrl "onepenny-server/controller/application"
 	attachmentCtrl "onepenny-server/controller/attachment"
 	bountyCtrl "onepenny-server/controller/bounty"
+	bountySeriesCtrl "onepenny-server/controller/bountyseries"
+	bountyTemplateCtrl "onepenny-server/controller/bountytemplate"
+	categoryCtrl "onepenny-server/controller/category"
 	commentCtrl "onepenny-server/controller/comment"
+	contributionCtrl "onepenny-server/controller/contribution"
+	disputeCtrl "onepenny-server/controller/dispute"
 	invitationCtrl "onepenny-server/controller/invitation"
+	jobCtrl "onepenny-server/controller/job"
 	likeCtrl "onepenny-server/controller/like"
 	notificationCtrl "onepenny-server/controller/notification"
+	savedSearchCtrl "onepenny-server/controller/savedsearch"
+	submissionCtrl "onepenny-server/controller/submission"
+	tagCtrl "onepenny-server/controller/tag"
 	teamCtrl "onepenny-server/controller/team"
 	userCtrl "onepenny-server/controller/user"
+	walletCtrl "onepenny-server/controller/wallet"
 	"onepenny-server/database"
 	"onepenny-server/docs"
 	"onepenny-server/internal/repository"
+	"onepenny-server/internal/scheduler"
 	"onepenny-server/internal/service"
+	"time"
 )
 
 func main() {
@@ -66,6 +79,7 @@ func main() {
 	// 3. 构造 Repository
 	userRepo := repository.NewUserRepo(database.DB)
 	bountyRepo := repository.NewBountyRepo(database.DB)
+	bountyEventRepo := repository.NewBountyEventRepo(database.DB)
 	applicationRepo := repository.NewApplicationRepo(database.DB)
 	invitationRepo := repository.NewInvitationRepo(database.DB)
 	notificationRepo := repository.NewNotificationRepo(database.DB)
@@ -73,22 +87,101 @@ func main() {
 	likeRepo := repository.NewLikeRepo(database.DB)
 	teamRepo := repository.NewTeamRepo(database.DB)
 	statsRepo := repository.NewUserStatsRepo(database.DB)
+	ledgerRepo := repository.NewLedgerRepo(database.DB)
+	disputeRepo := repository.NewDisputeRepo(database.DB)
+	submissionRepo := repository.NewSubmissionRepo(database.DB)
+	jobRunRepo := repository.NewJobRunRepo(database.DB)
+	contributionRepo := repository.NewContributionRepo(database.DB)
+	templateRepo := repository.NewBountyTemplateRepo(database.DB)
+	seriesRepo := repository.NewBountySeriesRepo(database.DB)
+	tagRepo := repository.NewTagRepo(database.DB)
+	categoryRepo := repository.NewCategoryRepo(database.DB)
+	feedRepo := repository.NewFeedRepo(database.DB)
+	trendingRepo := repository.NewTrendingRepo(database.DB)
+	savedSearchRepo := repository.NewSavedSearchRepo(database.DB)
+	watchRepo := repository.NewWatchRepo(database.DB)
+	viewRepo := repository.NewViewRepo(database.DB)
+	duplicateRepo := repository.NewDuplicateRepo(database.DB)
 
 	// 4. 构造 Service
 	userSvc := service.NewUserService(userRepo)
-	bountySvc := service.NewBountyService(bountyRepo)
-	applicationSvc := service.NewApplicationService(applicationRepo)
-	invitationSvc := service.NewInvitationService(invitationRepo)
 	notificationSvc := service.NewNotificationService(notificationRepo)
-	commentSvc := service.NewCommentService(commentRepo)
-	likeSvc := service.NewLikeService(likeRepo)
+	tagSvc := service.NewTagService(tagRepo, notificationSvc)
+	categorySvc := service.NewCategoryService(categoryRepo)
+	feedSvc := service.NewFeedService(feedRepo, database.RedisClient)
+	trendingSvc := service.NewTrendingService(trendingRepo, categorySvc, database.RedisClient)
+	savedSearchSvc := service.NewSavedSearchService(savedSearchRepo, tagSvc, categorySvc, notificationSvc)
+	watchSvc := service.NewWatchService(watchRepo, bountyRepo, notificationSvc)
+	duplicateSvc := service.NewDuplicateService(duplicateRepo)
+	viewSvc := service.NewViewService(viewRepo, bountyRepo, feedSvc, trendingSvc, database.RedisClient, durationOr("views.dedupe_window", 30*time.Minute))
+	bountySvc := service.NewBountyService(bountyRepo, bountyEventRepo, teamRepo, tagSvc, categorySvc, savedSearchSvc, watchSvc, notificationSvc)
+	applicationSvc := service.NewApplicationService(applicationRepo, bountyRepo, feedSvc, trendingSvc, watchSvc)
+	invitationSvc := service.NewInvitationService(invitationRepo)
+	commentSvc := service.NewCommentService(commentRepo, bountyRepo, trendingSvc, watchSvc)
+	likeSvc := service.NewLikeService(likeRepo, bountyRepo, commentRepo, feedSvc, trendingSvc)
 	teamSvc := service.NewTeamService(teamRepo)
 	statsSvc := service.NewUserStatsService(statsRepo)
+	walletSvc := service.NewWalletService(ledgerRepo)
+	disputeSvc := service.NewDisputeService(disputeRepo, watchSvc, notificationSvc)
+	submissionSvc := service.NewSubmissionService(submissionRepo, bountyRepo, watchSvc, notificationSvc)
+	contributionSvc := service.NewContributionService(contributionRepo, bountyRepo, notificationSvc)
+	templateSvc := service.NewBountyTemplateService(templateRepo, teamRepo, tagSvc, categorySvc, bountySvc)
+	seriesSvc := service.NewBountySeriesService(seriesRepo, tagSvc, categorySvc, savedSearchSvc, watchSvc, notificationSvc)
+
+	// 后台定时任务：多实例部署时通过 Redis 选主，只有 leader 执行
+	sched := scheduler.New(database.RedisClient, jobRunRepo, durationOr("scheduler.lease_ttl", 30*time.Second))
+	autoConfirmAfter := durationOr("settlement.auto_confirm_after", 7*24*time.Hour)
+	sched.Register(scheduler.Job{
+		Name:     "expire_bounties",
+		Interval: durationOr("scheduler.jobs.expire_bounties", 5*time.Minute),
+		Run:      func(context.Context) (int, error) { return bountySvc.ExpireUnassigned() },
+	})
+	sched.Register(scheduler.Job{
+		Name:     "flag_overdue_bounties",
+		Interval: durationOr("scheduler.jobs.flag_overdue_bounties", 15*time.Minute),
+		Run:      func(context.Context) (int, error) { return bountySvc.FlagOverdue() },
+	})
+	sched.Register(scheduler.Job{
+		Name:     "auto_confirm_settlements",
+		Interval: durationOr("scheduler.jobs.auto_confirm_settlements", time.Hour),
+		Run:      func(context.Context) (int, error) { return bountySvc.AutoConfirmSettlements(autoConfirmAfter) },
+	})
+	sched.Register(scheduler.Job{
+		Name:     "expire_invitations",
+		Interval: durationOr("scheduler.jobs.expire_invitations", 5*time.Minute),
+		Run:      func(context.Context) (int, error) { return invitationSvc.RejectExpired() },
+	})
+	sched.Register(scheduler.Job{
+		Name:     "purge_notifications",
+		Interval: durationOr("scheduler.jobs.purge_notifications", time.Hour),
+		Run:      func(context.Context) (int, error) { return notificationSvc.PurgeExpired() },
+	})
+	sched.Register(scheduler.Job{
+		Name:     "publish_scheduled_bounties",
+		Interval: durationOr("scheduler.jobs.publish_scheduled_bounties", time.Minute),
+		Run:      func(context.Context) (int, error) { return bountySvc.PublishDue() },
+	})
+	sched.Register(scheduler.Job{
+		Name:     "spawn_recurring_bounties",
+		Interval: durationOr("scheduler.jobs.spawn_recurring_bounties", time.Minute),
+		Run:      func(context.Context) (int, error) { return seriesSvc.SpawnDue() },
+	})
+	sched.Register(scheduler.Job{
+		Name:     "saved_search_digests",
+		Interval: durationOr("scheduler.jobs.saved_search_digests", 15*time.Minute),
+		Run:      func(context.Context) (int, error) { return savedSearchSvc.SendDigests() },
+	})
+	sched.Register(scheduler.Job{
+		Name:     "flush_bounty_views",
+		Interval: durationOr("scheduler.jobs.flush_bounty_views", 30*time.Second),
+		Run:      func(context.Context) (int, error) { return viewSvc.FlushViews() },
+	})
+	jobSvc := service.NewJobService(jobRunRepo, sched)
 
 	// 5. 构造 Controller
 	authController := userCtrl.NewAuthController(userSvc)
 	profileController := userCtrl.NewProfileController(userSvc)
-	bountyController := bountyCtrl.NewBountyController(bountySvc)
+	bountyController := bountyCtrl.NewBountyController(bountySvc, feedSvc, trendingSvc, watchSvc, viewSvc, duplicateSvc)
 	applicationController := applicationCtrl.NewApplicationController(applicationSvc)
 	invitationController := invitationCtrl.NewInvitationController(invitationSvc)
 	notificationController := notificationCtrl.NewNotificationController(notificationSvc)
@@ -96,6 +189,16 @@ func main() {
 	likeController := likeCtrl.NewLikeController(likeSvc)
 	teamController := teamCtrl.NewTeamController(teamSvc)
 	statsController := userCtrl.NewUserStatsController(statsSvc)
+	walletController := walletCtrl.NewWalletController(walletSvc)
+	disputeController := disputeCtrl.NewDisputeController(disputeSvc)
+	submissionController := submissionCtrl.NewSubmissionController(submissionSvc)
+	jobController := jobCtrl.NewJobController(jobSvc)
+	contributionController := contributionCtrl.NewContributionController(contributionSvc)
+	templateController := bountyTemplateCtrl.NewBountyTemplateController(templateSvc)
+	seriesController := bountySeriesCtrl.NewBountySeriesController(seriesSvc)
+	tagController := tagCtrl.NewTagController(tagSvc)
+	categoryController := categoryCtrl.NewCategoryController(categorySvc)
+	savedSearchController := savedSearchCtrl.NewSavedSearchController(savedSearchSvc)
 
 	attachmentController := attachmentCtrl.NewAttachmentController()
 
@@ -113,8 +216,21 @@ func main() {
 		teamController,
 		attachmentController,
 		statsController,
+		walletController,
+		disputeController,
+		submissionController,
+		jobController,
+		contributionController,
+		templateController,
+		seriesController,
+		tagController,
+		categoryController,
+		savedSearchController,
 	)
 
+	// 启动后台任务
+	sched.Start(context.Background())
+
 	// → 在最外层挂载 swagger
 	r.GET("/swagger/*any", ginSwagger.WrapHandler(swaggerFiles.Handler))
 
@@ -129,3 +245,11 @@ func main() {
 		log.Fatalf("Failed to start server: %v", err)
 	}
 }
+
+// durationOr 读取时长配置，未配置或非法时使用默认值
+func durationOr(key string, def time.Duration) time.Duration {
+	if d := viper.GetDuration(key); d > 0 {
+		return d
+	}
+	return def
+}
@@ -0,0 +1,20 @@
+package migration
+
+import "gorm.io/gorm"
+
+// bountyDuplicateSQL 查重所需的 pg_trgm 扩展与标题三元组索引：
+// 发布时按标题与描述的三元组相似度比对近期未完成的悬赏令，管理员的重复报告按标题相似度（%）做自连接
+var bountyDuplicateSQL = []string{
+	`CREATE EXTENSION IF NOT EXISTS pg_trgm`,
+	`CREATE INDEX IF NOT EXISTS idx_bounty_title_trgm ON bounties USING gin (title gin_trgm_ops)`,
+}
+
+// setupBountyDuplicate 创建查重所需的扩展与索引，可重复执行
+func setupBountyDuplicate(db *gorm.DB) error {
+	for _, stmt := range bountyDuplicateSQL {
+		if err := db.Exec(stmt).Error; err != nil {
+			return err
+		}
+	}
+	return nil
+}
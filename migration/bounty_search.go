@@ -0,0 +1,68 @@
+package migration
+
+import "gorm.io/gorm"
+
+// bountySearchSQL 维护悬赏令全文检索向量的函数与触发器。
+//
+// PostgreSQL 自带的分词器不切分中文，连续的汉字会被当作一个词。
+// bounty_search_text 将每个汉字及相邻两字的组合拆成独立的词（一元 + 二元切分），
+// 再交给 simple 配置生成 tsvector；查询端按同样的规则切分，从而支持中文任意子串检索。
+// 标题、标签、分类、描述分别赋予 A–D 权重用于排序。
+var bountySearchSQL = []string{
+	`CREATE OR REPLACE FUNCTION bounty_search_text(src text) RETURNS text AS $$
+DECLARE
+	res text := '';
+	ch  text;
+	nxt text;
+	i   int;
+BEGIN
+	IF src IS NULL THEN
+		RETURN '';
+	END IF;
+	FOR i IN 1..char_length(src) LOOP
+		ch := substr(src, i, 1);
+		IF ch ~ '[\u3400-\u4dbf\u4e00-\u9fff\uf900-\ufaff]' THEN
+			res := res || ' ' || ch;
+			nxt := substr(src, i + 1, 1);
+			IF nxt ~ '[\u3400-\u4dbf\u4e00-\u9fff\uf900-\ufaff]' THEN
+				res := res || ' ' || ch || nxt;
+			END IF;
+			res := res || ' ';
+		ELSE
+			res := res || ch;
+		END IF;
+	END LOOP;
+	RETURN res;
+END
+$$ LANGUAGE plpgsql IMMUTABLE`,
+
+	`CREATE OR REPLACE FUNCTION bounties_search_vector_update() RETURNS trigger AS $$
+BEGIN
+	NEW.search_vector :=
+		setweight(to_tsvector('simple', bounty_search_text(NEW.title)), 'A') ||
+		setweight(to_tsvector('simple', bounty_search_text(array_to_string(NEW.tags, ' '))), 'B') ||
+		setweight(to_tsvector('simple', bounty_search_text(NEW.category)), 'C') ||
+		setweight(to_tsvector('simple', bounty_search_text(NEW.description)), 'D');
+	RETURN NEW;
+END
+$$ LANGUAGE plpgsql`,
+
+	`DROP TRIGGER IF EXISTS bounties_search_vector ON bounties`,
+
+	`CREATE TRIGGER bounties_search_vector
+	BEFORE INSERT OR UPDATE OF title, description, tags, category ON bounties
+	FOR EACH ROW EXECUTE FUNCTION bounties_search_vector_update()`,
+
+	// 历史数据：触发器创建前写入的悬赏令补齐检索向量
+	`UPDATE bounties SET title = title WHERE search_vector IS NULL`,
+}
+
+// setupBountySearch 创建或更新全文检索的函数与触发器，可重复执行
+func setupBountySearch(db *gorm.DB) error {
+	for _, stmt := range bountySearchSQL {
+		if err := db.Exec(stmt).Error; err != nil {
+			return err
+		}
+	}
+	return nil
+}
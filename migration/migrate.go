@@ -2,7 +2,9 @@ package migration
 
 import (
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 	"onepenny-server/model/dao"
+	"time"
 )
 
 // Migrate 自动迁移数据库模型
@@ -10,15 +12,37 @@ func Migrate(db *gorm.DB) error {
 
 	db.Exec("CREATE EXTENSION IF NOT EXISTS \"uuid-ossp\";")
 
-	return db.AutoMigrate(
+	if err := db.AutoMigrate(
 		// 基础用户数据库表
 		&dao.User{},
 
 		// 基础悬赏令表  为用户对象所拥有或申请
 		&dao.Bounty{},
 
+		// 悬赏令事件历史与分阶段里程碑
+		&dao.BountyEvent{},
+		&dao.Milestone{},
+		&dao.Submission{},
+		&dao.Contribution{},
+		&dao.BountyTemplate{},
+		&dao.BountySeries{},
+		&dao.BountyAllowedUser{},
+
+		// 分类树
+		&dao.Category{},
+
+		// 标签、别名与关注
+		&dao.Tag{},
+		&dao.TagAlias{},
+		&dao.TagFollow{},
+
+		// 保存的搜索与命中记录
+		&dao.SavedSearch{},
+		&dao.SavedSearchMatch{},
+
 		// 悬赏令统计模型
 		&dao.BountyView{},
+		&dao.BountyWatch{},
 
 		// 连接用户与悬赏令交互的申请与通知模型
 		&dao.Application{},
@@ -28,8 +52,84 @@ func Migrate(db *gorm.DB) error {
 		&dao.Comment{},
 		&dao.Like{},
 
+		// 结算争议
+		&dao.Dispute{},
+
+		// 钱包与复式记账
+		&dao.Wallet{},
+		&dao.LedgerTransaction{},
+		&dao.LedgerEntry{},
+
+		// 后台任务执行记录
+		&dao.JobRun{},
+
 		// 用户与用户之间的社交活动模型
 		&dao.Invitation{},
 		&dao.Team{},
-	)
+	); err != nil {
+		return err
+	}
+
+	if err := setupBountySearch(db); err != nil {
+		return err
+	}
+	if err := setupBountyDuplicate(db); err != nil {
+		return err
+	}
+
+	return migrateLegacyStatuses(db)
+}
+
+// schemaMigration 记录已执行过的一次性数据迁移
+type schemaMigration struct {
+	Name      string `gorm:"primaryKey"`
+	AppliedAt time.Time
+}
+
+func (schemaMigration) TableName() string {
+	return "schema_migrations"
+}
+
+// runOnce 在事务中执行名为 name 的一次性数据迁移：先写入标记行，已有标记时跳过；
+// 多个实例同时启动时，后到者会等待先到者的事务提交后再跳过
+func runOnce(db *gorm.DB, name string, fn func(tx *gorm.DB) error) error {
+	if err := db.AutoMigrate(&schemaMigration{}); err != nil {
+		return err
+	}
+	return db.Transaction(func(tx *gorm.DB) error {
+		res := tx.Clauses(clause.OnConflict{DoNothing: true}).
+			Create(&schemaMigration{Name: name, AppliedAt: time.Now()})
+		if res.Error != nil || res.RowsAffected == 0 {
+			return res.Error
+		}
+		return fn(tx)
+	})
+}
+
+// migrateLegacyStatuses 修正状态机上线前写入的悬赏令状态，只在上线后首次启动时执行一次：
+//   - 待结算状态曾写为 "PendingSettlement"；
+//   - 已结算状态的取值曾误写为 "cancelled"，且旧版本没有任何取消流程，因此这些记录实际都已结算；
+//     为稳妥起见仍跳过已有取消事件的记录；
+//   - 旧版本定义过但无流程写入的 "completed" 同样视为已结算。
+//
+// 已软删除的记录一并修正，以免恢复后状态错误
+func migrateLegacyStatuses(db *gorm.DB) error {
+	return runOnce(db, "legacy_bounty_statuses", func(tx *gorm.DB) error {
+		if err := tx.Unscoped().Model(&dao.Bounty{}).
+			Where("status = ?", "PendingSettlement").
+			Update("status", dao.BountyStatusPendingSettlement).Error; err != nil {
+			return err
+		}
+		cancelEvents := tx.Model(&dao.BountyEvent{}).
+			Select("1").
+			Where("bounty_events.bounty_id = bounties.id AND bounty_events.to_status = ?", dao.BountyStatusCancelled)
+		if err := tx.Unscoped().Model(&dao.Bounty{}).
+			Where("status = ? AND NOT EXISTS (?)", dao.BountyStatusCancelled, cancelEvents).
+			Update("status", dao.BountyStatusSettled).Error; err != nil {
+			return err
+		}
+		return tx.Unscoped().Model(&dao.Bounty{}).
+			Where("status = ?", "completed").
+			Update("status", dao.BountyStatusSettled).Error
+	})
 }
@@ -21,3 +21,8 @@ func (base *BaseModel) BeforeCreate(tx *gorm.DB) (err error) {
 	}
 	return
 }
+
+// PageKey 返回游标分页使用的排序键 (created_at, id)
+func (base BaseModel) PageKey() (time.Time, uuid.UUID) {
+	return base.CreatedAt, base.ID
+}
@@ -7,22 +7,6 @@ import (
 	"github.com/lib/pq"
 )
 
-// BountyStatus 定义赏金任务的状态
-type BountyStatus string
-
-const (
-	// BountyStatusCreated 任务刚创建，还未有人承接
-	BountyStatusCreated BountyStatus = "created"
-	// BountyStatusInProgress 任务进行中
-	BountyStatusInProgress BountyStatus = "in_progress"
-	// BountyStatusCompleted 任务已完成
-	BountyStatusCompleted BountyStatus = "completed"
-	// BountyStatusPendingSettlement 任务待结算
-	BountyStatusPendingSettlement BountyStatus = "PendingSettlement"
-	// BountyStatusSettled 任务完成
-	BountyStatusSettled BountyStatus = "cancelled"
-)
-
 // Bounty 是一个通用的「赏金任务」模型
 type Bounty struct {
 	BaseModel
@@ -43,18 +27,60 @@ type Bounty struct {
 	Status   BountyStatus `gorm:"type:varchar(20);index;default:'created'"`
 	Priority string       `gorm:"type:varchar(20);default:'normal'"` // low, normal, high
 
+	// 可见范围：发布者与接收者始终可见；团队可见时 TeamID 必填，仅邀请可见时以 AllowedUsers 为白名单
+	Visibility BountyVisibility `gorm:"type:varchar(20);index;default:'public'"`
+	TeamID     *uuid.UUID       `gorm:"type:uuid;index"`
+
 	// 时间与分类
 	Deadline *time.Time     `gorm:"index"`             // 可空
 	Category string         `gorm:"type:varchar(100)"` // 如 “设计”、“文案”等
 	Tags     pq.StringArray `gorm:"type:text[]"`       // 关键词
 
+	// 定时发布：草稿到达该时间后由后台任务自动发布；已发布的悬赏令记录实际发布时间
+	PublishAt *time.Time `gorm:"index"`
+
+	// 结算：接收者发起结算的时间，发布者超时未确认时据此自动结算
+	SettlementRequestedAt *time.Time `gorm:"index"`
+
+	// 逾期：进行中的悬赏令超过截止时间后由后台任务标记
+	OverdueAt *time.Time `gorm:"index"`
+
+	// 取消：进行中的悬赏令由发布者申请取消，需接收者同意
+	CancelRequestedAt *time.Time
+	CancelReason      string `gorm:"type:text"`
+
+	// 来源模板：通过模板一键发布时记录，可空
+	TemplateID *uuid.UUID `gorm:"type:uuid;index"`
+
+	// 周期悬赏：由哪个系列生成、第几期，可空
+	SeriesID   *uuid.UUID `gorm:"type:uuid;index"`
+	Occurrence int        `gorm:"not null;default:0"`
+
 	// 附件、位置与沟通
 	Attachments   pq.StringArray `gorm:"type:text[]"`       // 文档/图片等链接
 	Location      string         `gorm:"type:varchar(255)"` // "remote" 或线下地址
 	Communication string         `gorm:"type:varchar(50)"`  // e.g. "email", "wechat"
 
+	// 线下悬赏令的坐标（WGS84），用于按距离搜索；远程悬赏令为空
+	Latitude  *float64 `gorm:"type:double precision;index:idx_bounty_geo"`
+	Longitude *float64 `gorm:"type:double precision;index:idx_bounty_geo"`
+
+	// 关注人数与浏览数：分别由关注与浏览记录维护，编辑悬赏令时不覆盖
+	WatcherCount int64 `gorm:"not null;default:0"`
+	ViewCount    int64 `gorm:"not null;default:0"`
+
+	// 全文检索向量：由数据库触发器根据标题、描述、标签与分类维护，程序不读写
+	SearchVector string `gorm:"type:tsvector;->:false;<-:false;index:idx_bounty_search,type:gin"`
+
 	// —— 关联 ——
 	Comments     []Comment     `gorm:"foreignKey:BountyID;references:ID"`
 	Applications []Application `gorm:"foreignKey:BountyID;references:ID"`
+	Milestones   []Milestone   `gorm:"foreignKey:BountyID;references:ID"`
 	Likes        []Like        `gorm:"polymorphic:Likeable;"`
+
+	// 众筹追加：查询时仅预加载仍在托管或已发放的部分，用于计算实际赏金
+	Contributions []Contribution `gorm:"foreignKey:BountyID;references:ID"`
+
+	// 仅邀请可见时的白名单
+	AllowedUsers []BountyAllowedUser `gorm:"foreignKey:BountyID;references:ID"`
 }
@@ -0,0 +1,47 @@
+package dao
+
+import (
+	"github.com/google/uuid"
+)
+
+// BountyEventType 悬赏令事件类型
+const (
+	BountyEventCreated             = "created"              // 创建（草稿或直接发布）
+	BountyEventPublished           = "published"            // 草稿发布
+	BountyEventUpdated             = "updated"              // 编辑字段
+	BountyEventApplicationApproved = "application_approved" // 申请被批准，任务开始
+	BountyEventSettlementRequested = "settlement_requested" // 接收者发起结算
+	BountyEventSettlementConfirmed = "settlement_confirmed" // 发布者确认结算
+	BountyEventSettlementAutoDone  = "settlement_auto_done" // 发布者超时未确认，系统自动结算
+	BountyEventDisputeOpened       = "dispute_opened"       // 发起争议
+	BountyEventDisputeWithdrawn    = "dispute_withdrawn"    // 撤回争议
+	BountyEventDisputeResolved     = "dispute_resolved"     // 仲裁裁决
+	BountyEventMilestoneRequested  = "milestone_requested"  // 接收者发起里程碑结算
+	BountyEventMilestoneSettled    = "milestone_settled"    // 发布者确认里程碑结算
+	BountyEventSubmissionCreated   = "submission_created"   // 接收者提交交付物
+	BountyEventSubmissionAccepted  = "submission_accepted"  // 发布者通过交付物
+	BountyEventChangesRequested    = "changes_requested"    // 发布者要求修改交付物
+	BountyEventExpired             = "expired"              // 超过截止时间无人承接，自动过期
+	BountyEventOverdue             = "overdue"              // 进行中超过截止时间，标记逾期
+	BountyEventCancelRequested     = "cancel_requested"     // 发布者申请取消，等待接收者同意
+	BountyEventCancelDeclined      = "cancel_declined"      // 接收者拒绝取消
+	BountyEventCancelled           = "cancelled"            // 悬赏令被取消
+	BountyEventContributionAdded   = "contribution_added"   // 第三方追加赏金
+)
+
+// BountyEvent 悬赏令的历史事件，用于时间线展示与纠纷排查
+type BountyEvent struct {
+	BaseModel
+
+	BountyID uuid.UUID  `gorm:"type:uuid;not null;index"` // 所属悬赏令
+	ActorID  *uuid.UUID `gorm:"type:uuid;index"`          // 操作者（系统操作时为空）
+
+	Type       string       `gorm:"type:varchar(50);not null;index"`
+	FromStatus BountyStatus `gorm:"type:varchar(20)"` // 事件发生前的状态
+	ToStatus   BountyStatus `gorm:"type:varchar(20)"` // 事件发生后的状态
+
+	Payload map[string]interface{} `gorm:"type:jsonb;serializer:json"` // 变更字段等附加信息
+
+	// —— 关联预加载 ——
+	Actor *User `gorm:"foreignKey:ActorID;references:ID"`
+}
@@ -0,0 +1,49 @@
+package dao
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+)
+
+// BountySeriesStatus 周期悬赏的状态
+type BountySeriesStatus string
+
+const (
+	BountySeriesStatusActive BountySeriesStatus = "active" // 按计划生成
+	BountySeriesStatusPaused BountySeriesStatus = "paused" // 暂停，恢复后从当前时间重新计算
+	BountySeriesStatusEnded  BountySeriesStatus = "ended"  // 到达结束时间、次数上限或被手动结束
+)
+
+// BountySeries 周期悬赏：按 cron 表达式定期生成新的悬赏令，生成的悬赏令通过 SeriesID 关联。
+// Title 与 Description 中可使用 {{n}}（第几期）与 {{date}}（生成日期）占位符。
+type BountySeries struct {
+	BaseModel
+
+	UserID uuid.UUID `gorm:"type:uuid;not null;index"` // 发布者，每期赏金从其钱包托管
+
+	// 每期悬赏令的内容
+	Title       string         `gorm:"type:varchar(255);not null"`
+	Description string         `gorm:"type:text"`
+	Reward      float64        `gorm:"type:numeric;not null"`
+	Currency    string         `gorm:"type:varchar(10);default:'USD'"`
+	Category    string         `gorm:"type:varchar(100)"`
+	Tags        pq.StringArray `gorm:"type:text[]"`
+	Priority    string         `gorm:"type:varchar(20);default:'normal'"`
+
+	// 计划：五段式 cron 表达式，按 Timezone 解释
+	Cron     string `gorm:"type:varchar(100);not null"`
+	Timezone string `gorm:"type:varchar(64);default:'UTC'"`
+	// 每期截止时间 = 生成时间 + DeadlineAfterHours；为 0 时以下一期的生成时间为截止
+	DeadlineAfterHours int `gorm:"not null;default:0"`
+
+	// 结束条件：任一满足即结束，均为空/0 时无限期
+	EndAt          *time.Time
+	MaxOccurrences int `gorm:"not null;default:0"`
+
+	Status          BountySeriesStatus `gorm:"type:varchar(20);index;default:'active'"`
+	OccurrenceCount int                `gorm:"not null;default:0"` // 已生成的期数
+	NextRunAt       *time.Time         `gorm:"index"`              // 下一期生成时间，暂停或结束时为空
+	LastError       string             `gorm:"type:text"`          // 最近一次生成失败的原因，如余额不足
+}
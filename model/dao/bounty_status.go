@@ -0,0 +1,95 @@
+package dao
+
+import (
+	"errors"
+	"fmt"
+)
+
+// BountyStatus 定义赏金任务的状态
+type BountyStatus string
+
+const (
+	// BountyStatusDraft 草稿，仅发布者可见，发布时才托管赏金
+	BountyStatusDraft BountyStatus = "draft"
+	// BountyStatusCreated 任务刚创建，还未有人承接
+	BountyStatusCreated BountyStatus = "created"
+	// BountyStatusInProgress 任务进行中
+	BountyStatusInProgress BountyStatus = "in_progress"
+	// BountyStatusPendingSettlement 任务待结算
+	BountyStatusPendingSettlement BountyStatus = "pending_settlement"
+	// BountyStatusSettled 任务已结算
+	BountyStatusSettled BountyStatus = "settled"
+	// BountyStatusCancelled 任务已取消
+	BountyStatusCancelled BountyStatus = "cancelled"
+	// BountyStatusExpired 任务超过截止时间仍无人承接
+	BountyStatusExpired BountyStatus = "expired"
+	// BountyStatusDisputed 结算存在争议，等待仲裁
+	BountyStatusDisputed BountyStatus = "disputed"
+)
+
+// ErrIllegalBountyTransition 所有非法状态流转错误的哨兵值，可用 errors.Is 判断
+var ErrIllegalBountyTransition = errors.New("illegal bounty status transition")
+
+// BountyTransitionError 描述一次被状态机拒绝的状态流转
+type BountyTransitionError struct {
+	From BountyStatus
+	To   BountyStatus
+}
+
+func (e *BountyTransitionError) Error() string {
+	return fmt.Sprintf("悬赏令状态不能从 %s 变更为 %s", e.From, e.To)
+}
+
+func (e *BountyTransitionError) Unwrap() error {
+	return ErrIllegalBountyTransition
+}
+
+// bountyTransitions 悬赏令状态机：key 为当前状态，value 为允许进入的下一状态
+//
+//	draft → created（发布）
+//	created → in_progress → pending_settlement → settled
+//	created → cancelled / expired
+//	in_progress → cancelled
+//	pending_settlement → in_progress（发布者要求修改交付物）
+//	in_progress / pending_settlement ⇄ disputed → settled / cancelled
+var bountyTransitions = map[BountyStatus][]BountyStatus{
+	BountyStatusDraft:             {BountyStatusCreated},
+	BountyStatusCreated:           {BountyStatusInProgress, BountyStatusCancelled, BountyStatusExpired},
+	BountyStatusInProgress:        {BountyStatusPendingSettlement, BountyStatusCancelled, BountyStatusDisputed},
+	BountyStatusPendingSettlement: {BountyStatusSettled, BountyStatusDisputed, BountyStatusInProgress},
+	BountyStatusDisputed:          {BountyStatusSettled, BountyStatusCancelled, BountyStatusInProgress, BountyStatusPendingSettlement},
+}
+
+// IsValid 判断是否为已定义的状态
+func (s BountyStatus) IsValid() bool {
+	switch s {
+	case BountyStatusDraft, BountyStatusCreated, BountyStatusInProgress, BountyStatusPendingSettlement,
+		BountyStatusSettled, BountyStatusCancelled, BountyStatusExpired, BountyStatusDisputed:
+		return true
+	}
+	return false
+}
+
+// IsTerminal 终态不允许再发生任何流转
+func (s BountyStatus) IsTerminal() bool {
+	return len(bountyTransitions[s]) == 0
+}
+
+// CanTransitionTo 判断状态机是否允许从 s 流转到 to
+func (s BountyStatus) CanTransitionTo(to BountyStatus) bool {
+	for _, next := range bountyTransitions[s] {
+		if next == to {
+			return true
+		}
+	}
+	return false
+}
+
+// TransitionTo 校验并修改悬赏令状态，非法流转时返回 *BountyTransitionError
+func (b *Bounty) TransitionTo(to BountyStatus) error {
+	if !b.Status.CanTransitionTo(to) {
+		return &BountyTransitionError{From: b.Status, To: to}
+	}
+	b.Status = to
+	return nil
+}
@@ -0,0 +1,27 @@
+package dao
+
+import (
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+)
+
+// BountyTemplate 悬赏令模板，归属个人或团队，用于快速发布同类悬赏令。
+// Title 与 Description 中可使用 {{name}} 形式的占位符，实例化时替换。
+type BountyTemplate struct {
+	BaseModel
+
+	// 归属：TeamID 为空时为个人模板，仅创建者可见；否则团队成员均可使用
+	UserID uuid.UUID  `gorm:"type:uuid;not null;index"` // 创建者
+	TeamID *uuid.UUID `gorm:"type:uuid;index"`
+
+	Name string `gorm:"type:varchar(100);not null"` // 模板名称，如 “每周 Bug 分拣”
+
+	// 悬赏令默认内容
+	Title       string         `gorm:"type:varchar(255);not null"`
+	Description string         `gorm:"type:text"`
+	Reward      float64        `gorm:"type:numeric;not null;default:0"`
+	Currency    string         `gorm:"type:varchar(10);default:'USD'"`
+	Category    string         `gorm:"type:varchar(100)"`
+	Tags        pq.StringArray `gorm:"type:text[]"`
+	Priority    string         `gorm:"type:varchar(20);default:'normal'"`
+}
@@ -6,10 +6,11 @@ import (
 	"github.com/google/uuid"
 )
 
-// BountyView 记录用户查看过哪些悬赏
+// BountyView 记录用户查看过哪些悬赏。同一用户在去重窗口内重复查看只记一次，
+// 浏览先缓冲在 Redis 中再批量写入，ViewedAt 为实际浏览时间
 type BountyView struct {
 	BaseModel
-	UserID   uuid.UUID `gorm:"type:uuid;index"` // 谁看过
-	BountyID uuid.UUID `gorm:"type:uuid;index"` // 看的是哪个悬赏
-	ViewedAt time.Time `gorm:"autoCreateTime"`  // 第一次写入的时间
+	UserID   uuid.UUID `gorm:"type:uuid;index"`                                       // 谁看过
+	BountyID uuid.UUID `gorm:"type:uuid;index;index:idx_bounty_view_time,priority:1"` // 看的是哪个悬赏
+	ViewedAt time.Time `gorm:"autoCreateTime;index:idx_bounty_view_time,priority:2"`  // 浏览时间
 }
@@ -0,0 +1,34 @@
+package dao
+
+import "github.com/google/uuid"
+
+// BountyVisibility 悬赏令的可见范围
+type BountyVisibility string
+
+const (
+	// BountyVisibilityPublic 所有登录用户可见，并出现在列表中
+	BountyVisibilityPublic BountyVisibility = "public"
+	// BountyVisibilityUnlisted 持有链接（ID）者可见，但不出现在列表中
+	BountyVisibilityUnlisted BountyVisibility = "unlisted"
+	// BountyVisibilityTeam 仅 TeamID 对应团队的成员可见
+	BountyVisibilityTeam BountyVisibility = "team"
+	// BountyVisibilityInviteOnly 仅白名单中的用户可见
+	BountyVisibilityInviteOnly BountyVisibility = "invite_only"
+)
+
+// IsValid 判断是否为已定义的可见范围
+func (v BountyVisibility) IsValid() bool {
+	switch v {
+	case BountyVisibilityPublic, BountyVisibilityUnlisted, BountyVisibilityTeam, BountyVisibilityInviteOnly:
+		return true
+	}
+	return false
+}
+
+// BountyAllowedUser 仅邀请可见的悬赏令的白名单
+type BountyAllowedUser struct {
+	BaseModel
+
+	BountyID uuid.UUID `gorm:"type:uuid;not null;uniqueIndex:idx_bounty_allowed_user"`
+	UserID   uuid.UUID `gorm:"type:uuid;not null;uniqueIndex:idx_bounty_allowed_user;index"`
+}
@@ -0,0 +1,14 @@
+package dao
+
+import "github.com/google/uuid"
+
+// BountyWatch 用户关注的悬赏令，状态变化、新评论、截止时间修改与结算时收到通知。
+// 发布者与评论者自动关注
+type BountyWatch struct {
+	BaseModel
+
+	BountyID uuid.UUID `gorm:"type:uuid;not null;uniqueIndex:idx_bounty_watch;index"`
+	UserID   uuid.UUID `gorm:"type:uuid;not null;uniqueIndex:idx_bounty_watch;index"`
+
+	Bounty Bounty `gorm:"foreignKey:BountyID;references:ID"`
+}
@@ -0,0 +1,33 @@
+package dao
+
+import "github.com/google/uuid"
+
+// DefaultCategoryLocale 分类名称缺少请求语言时回退使用的语言
+const DefaultCategoryLocale = "en"
+
+// Category 由管理员维护的悬赏令分类树。悬赏令、模板与周期悬赏的 Category 字段保存其 Slug
+type Category struct {
+	BaseModel
+
+	Slug     string     `gorm:"type:varchar(64);not null;uniqueIndex"` // 如 "ui-design"
+	ParentID *uuid.UUID `gorm:"type:uuid;index"`                       // 为空表示顶级分类
+
+	// Path 由根到自身的 Slug 路径，如 "/design/ui-design/"，用于按前缀查找全部子孙分类
+	Path string `gorm:"type:varchar(1024);not null;index"`
+
+	// 各语言的显示名称，如 {"en": "UI Design", "zh": "界面设计"}
+	Names     map[string]string `gorm:"type:jsonb;serializer:json"`
+	Icon      string            `gorm:"type:varchar(255)"`  // 图标 URL 或图标名
+	SortOrder int               `gorm:"not null;default:0"` // 同级分类升序排列
+}
+
+// DisplayName 按语言取显示名称，缺少时依次回退到默认语言与 Slug
+func (c *Category) DisplayName(locale string) string {
+	if n := c.Names[locale]; n != "" {
+		return n
+	}
+	if n := c.Names[DefaultCategoryLocale]; n != "" {
+		return n
+	}
+	return c.Slug
+}
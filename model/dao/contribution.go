@@ -0,0 +1,29 @@
+package dao
+
+import "github.com/google/uuid"
+
+// ContributionStatus 众筹追加的资金状态
+type ContributionStatus string
+
+const (
+	ContributionStatusActive   ContributionStatus = "active"   // 托管中
+	ContributionStatusReleased ContributionStatus = "released" // 已随结算发放给接收者
+	ContributionStatusRefunded ContributionStatus = "refunded" // 悬赏令取消或过期，已退回出资人
+)
+
+// Contribution 第三方用户对悬赏令追加的赏金，按币种单独托管
+type Contribution struct {
+	BaseModel
+
+	BountyID uuid.UUID `gorm:"type:uuid;not null;index"`
+	UserID   uuid.UUID `gorm:"type:uuid;not null;index"` // 出资人
+
+	Currency string  `gorm:"type:varchar(10);not null"`
+	Amount   float64 `gorm:"type:numeric;not null"`
+	Message  string  `gorm:"type:text"` // 出资留言
+
+	Status ContributionStatus `gorm:"type:varchar(20);not null;default:'active';index"`
+
+	// —— 关联预加载 ——
+	User User `gorm:"foreignKey:UserID;references:ID"`
+}
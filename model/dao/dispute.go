@@ -0,0 +1,54 @@
+package dao
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+)
+
+// DisputeStatus 争议状态
+type DisputeStatus string
+
+const (
+	DisputeStatusOpen      DisputeStatus = "open"      // 等待仲裁
+	DisputeStatusResolved  DisputeStatus = "resolved"  // 已裁决
+	DisputeStatusWithdrawn DisputeStatus = "withdrawn" // 发起方撤回
+)
+
+// DisputeRuling 仲裁结果
+type DisputeRuling string
+
+const (
+	DisputeRulingFullPayout    DisputeRuling = "full_payout"    // 全额发放给接收者
+	DisputeRulingPartialPayout DisputeRuling = "partial_payout" // 部分发放，剩余退回发布者
+	DisputeRulingRefund        DisputeRuling = "refund"         // 全额退回发布者
+)
+
+// Dispute 悬赏令结算争议，由发布者或接收者发起，仲裁员裁决
+type Dispute struct {
+	BaseModel
+
+	// —— 当事人 ——
+	BountyID     uuid.UUID `gorm:"type:uuid;not null;index"` // 争议的悬赏令
+	InitiatorID  uuid.UUID `gorm:"type:uuid;not null;index"` // 发起方
+	RespondentID uuid.UUID `gorm:"type:uuid;not null;index"` // 另一方
+
+	// —— 争议内容 ——
+	Reason       string         `gorm:"type:text;not null"`
+	EvidenceURLs pq.StringArray `gorm:"type:text[]"` // 双方提交的证据附件
+
+	// —— 状态 ——
+	Status     DisputeStatus `gorm:"type:varchar(20);not null;default:'open';index"`
+	PrevStatus BountyStatus  `gorm:"type:varchar(20)"` // 发起争议前悬赏令的状态，撤回时恢复
+
+	// —— 裁决 ——
+	ArbitratorID *uuid.UUID    `gorm:"type:uuid;index"`
+	Ruling       DisputeRuling `gorm:"type:varchar(20)"`
+	PayoutAmount float64       `gorm:"type:numeric;default:0"` // 判给接收者的金额
+	RulingNote   string        `gorm:"type:text"`
+	ResolvedAt   *time.Time
+
+	// —— 关联预加载 ——
+	Bounty Bounty `gorm:"foreignKey:BountyID;references:ID"`
+}
@@ -0,0 +1,25 @@
+package dao
+
+import "time"
+
+// JobRunStatus 后台任务单次执行结果
+type JobRunStatus string
+
+const (
+	JobRunStatusSucceeded JobRunStatus = "succeeded"
+	JobRunStatusFailed    JobRunStatus = "failed"
+)
+
+// JobRun 后台定时任务的一次执行记录，仅由当选 leader 的实例写入
+type JobRun struct {
+	BaseModel
+
+	Job      string `gorm:"type:varchar(100);not null;index"` // 任务名
+	Instance string `gorm:"type:varchar(255);not null"`       // 执行该任务的实例标识
+
+	StartedAt  time.Time    `gorm:"not null;index"`
+	FinishedAt time.Time    `gorm:"not null"`
+	Status     JobRunStatus `gorm:"type:varchar(20);not null"`
+	Affected   int          `gorm:"not null;default:0"` // 本次处理的记录数
+	Error      string       `gorm:"type:text"`
+}
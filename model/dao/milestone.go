@@ -0,0 +1,33 @@
+package dao
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// MilestoneStatus 里程碑状态
+type MilestoneStatus string
+
+const (
+	MilestoneStatusPending           MilestoneStatus = "pending"            // 未完成
+	MilestoneStatusPendingSettlement MilestoneStatus = "pending_settlement" // 接收者已发起结算
+	MilestoneStatusSettled           MilestoneStatus = "settled"            // 发布者已确认，该部分赏金已发放
+)
+
+// Milestone 悬赏令的阶段性里程碑，各里程碑金额之和等于悬赏令赏金
+type Milestone struct {
+	BaseModel
+
+	BountyID uuid.UUID `gorm:"type:uuid;not null;index"`
+	Seq      int       `gorm:"not null"` // 顺序，从 1 开始，必须按顺序结算
+
+	Title       string     `gorm:"type:varchar(255);not null"`
+	Deliverable string     `gorm:"type:text"`             // 交付物说明
+	Amount      float64    `gorm:"type:numeric;not null"` // 该阶段的赏金份额
+	DueDate     *time.Time `gorm:"index"`                 // 可空
+
+	Status                MilestoneStatus `gorm:"type:varchar(20);not null;default:'pending'"`
+	SettlementRequestedAt *time.Time
+	SettledAt             *time.Time
+}
@@ -7,9 +7,14 @@ import (
 
 // NotificationType 常量
 const (
-	NotificationTypeComment = "comment"
-	NotificationTypeInvite  = "invite"
-	NotificationTypeSystem  = "system"
+	NotificationTypeComment     = "comment"
+	NotificationTypeInvite      = "invite"
+	NotificationTypeSystem      = "system"
+	NotificationTypeDispute     = "dispute"
+	NotificationTypeSubmission  = "submission"
+	NotificationTypeTag         = "tag"          // 关注的标签有新悬赏令
+	NotificationTypeSavedSearch = "saved_search" // 保存的搜索有新匹配
+	NotificationTypeWatch       = "watch"        // 关注的悬赏令有新动态
 )
 
 // ChannelType 常量
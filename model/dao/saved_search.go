@@ -0,0 +1,61 @@
+package dao
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// SavedSearchDelivery 保存的搜索有新匹配时的通知方式
+type SavedSearchDelivery string
+
+const (
+	SavedSearchDeliveryInstant SavedSearchDelivery = "instant" // 每个新匹配立即通知
+	SavedSearchDeliveryDaily   SavedSearchDelivery = "daily"   // 每天最多汇总通知一次
+)
+
+// SavedSearchFilter 保存的筛选条件，含义与悬赏令列表的同名参数一致；零值表示不限
+type SavedSearchFilter struct {
+	Location   string   `json:"location,omitempty"`
+	NearLat    *float64 `json:"near_lat,omitempty"`
+	NearLng    *float64 `json:"near_lng,omitempty"`
+	RadiusKm   float64  `json:"radius_km,omitempty"`
+	Categories []string `json:"categories,omitempty"`
+	Tags       []string `json:"tags,omitempty"`
+	AllTags    bool     `json:"all_tags,omitempty"`
+	Currency   string   `json:"currency,omitempty"`
+	Priorities []string `json:"priorities,omitempty"`
+	MinReward  *float64 `json:"min_reward,omitempty"`
+	MaxReward  *float64 `json:"max_reward,omitempty"`
+}
+
+// SavedSearch 用户保存的悬赏令搜索：新发布的悬赏令命中检索词与筛选条件时通知用户
+type SavedSearch struct {
+	BaseModel
+
+	UserID uuid.UUID `gorm:"type:uuid;not null;index"`
+	Name   string    `gorm:"type:varchar(100);not null"`
+
+	// 全文检索词，可空；TSQuery 由检索词生成，用于发布时先行过滤
+	Query   string `gorm:"type:varchar(255)"`
+	TSQuery string `gorm:"column:ts_query;type:text;not null;default:''"`
+
+	Filter SavedSearchFilter `gorm:"type:jsonb;serializer:json"`
+
+	Delivery SavedSearchDelivery `gorm:"type:varchar(20);not null;default:'instant'"`
+	Paused   bool                `gorm:"not null;default:false;index"`
+
+	// 最近一次发送通知的时间，按天汇总时据此决定下一次汇总
+	LastNotifiedAt *time.Time
+}
+
+// SavedSearchMatch 新发布的悬赏令命中保存的搜索的记录；NotifiedAt 为空表示尚待汇总通知
+type SavedSearchMatch struct {
+	BaseModel
+
+	SavedSearchID uuid.UUID  `gorm:"type:uuid;not null;uniqueIndex:idx_saved_search_match;index"`
+	BountyID      uuid.UUID  `gorm:"type:uuid;not null;uniqueIndex:idx_saved_search_match"`
+	NotifiedAt    *time.Time `gorm:"index"`
+
+	Bounty Bounty `gorm:"foreignKey:BountyID;references:ID"`
+}
@@ -0,0 +1,38 @@
+package dao
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+)
+
+// SubmissionStatus 交付物审核状态
+type SubmissionStatus string
+
+const (
+	SubmissionStatusPending          SubmissionStatus = "pending"           // 等待发布者审核
+	SubmissionStatusAccepted         SubmissionStatus = "accepted"          // 已通过，悬赏令随之结算
+	SubmissionStatusChangesRequested SubmissionStatus = "changes_requested" // 发布者要求修改
+)
+
+// Submission 接收者提交的交付物，每次提交生成一个新版本，历史版本保留供双方查看
+type Submission struct {
+	BaseModel
+
+	BountyID    uuid.UUID `gorm:"type:uuid;not null;uniqueIndex:idx_submission_version"`
+	SubmitterID uuid.UUID `gorm:"type:uuid;not null;index"`
+	Version     int       `gorm:"not null;uniqueIndex:idx_submission_version"` // 同一悬赏令下从 1 递增
+
+	// —— 交付内容 ——
+	Content        string         `gorm:"type:text;not null"`
+	AttachmentURLs pq.StringArray `gorm:"type:text[]"`
+
+	// —— 审核 ——
+	Status        SubmissionStatus `gorm:"type:varchar(20);not null;default:'pending'"`
+	ReviewComment string           `gorm:"type:text"` // 要求修改时的说明
+	ReviewedAt    *time.Time
+
+	// —— 关联预加载 ——
+	Submitter User `gorm:"foreignKey:SubmitterID;references:ID"`
+}
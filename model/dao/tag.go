@@ -0,0 +1,36 @@
+package dao
+
+import "github.com/google/uuid"
+
+// Tag 受管理的标签。悬赏令、模板与周期悬赏的 Tags 字段只保存规范 Slug；
+// 大小写、空格与连字符不同的写法通过 Key 归并，其他同义写法通过别名归并
+type Tag struct {
+	BaseModel
+
+	Slug string `gorm:"type:varchar(64);not null;uniqueIndex"` // 规范写法，如 "go-lang"
+	Key  string `gorm:"type:varchar(64);not null;uniqueIndex"` // 归并键：Slug 去掉连字符，如 "golang"
+	Name string `gorm:"type:varchar(64);not null"`             // 首次使用时的原始写法，用于展示
+
+	// 使用该标签的悬赏令数（不含已删除），用于自动补全排序
+	UsageCount int64 `gorm:"not null;default:0;index"`
+
+	Aliases []TagAlias `gorm:"foreignKey:TagID;references:ID"`
+}
+
+// TagAlias 标签别名，如 "go" → golang；Alias 为归并键
+type TagAlias struct {
+	BaseModel
+
+	TagID uuid.UUID `gorm:"type:uuid;not null;index"`
+	Alias string    `gorm:"type:varchar(64);not null;uniqueIndex"`
+}
+
+// TagFollow 用户关注的标签，有匹配的新悬赏令发布时收到通知
+type TagFollow struct {
+	BaseModel
+
+	UserID uuid.UUID `gorm:"type:uuid;not null;uniqueIndex:idx_tag_follow;index"`
+	TagID  uuid.UUID `gorm:"type:uuid;not null;uniqueIndex:idx_tag_follow;index"`
+
+	Tag Tag `gorm:"foreignKey:TagID;references:ID"`
+}
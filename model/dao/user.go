@@ -4,6 +4,13 @@ import (
 	"time"
 )
 
+// UserRole 用户角色，目前由运维直接在数据库中指定
+const (
+	RoleUser       = "user"       // 普通用户
+	RoleArbitrator = "arbitrator" // 仲裁员，处理结算争议
+	RoleAdmin      = "admin"      // 管理员，拥有所有角色的权限
+)
+
 // User 只包含登录认证及基本偏好所需字段
 type User struct {
 	// —— 元信息 ——
@@ -17,6 +24,7 @@ type User struct {
 	LastPasswordChange *time.Time // 上次修改密码时间
 	Verified           bool       `gorm:"default:false"`                     // 邮箱是否验证
 	AccountStatus      string     `gorm:"type:varchar(20);default:'active'"` // active, suspended, deleted
+	Role               string     `gorm:"type:varchar(20);default:'user'"`   // user, arbitrator, admin
 
 	// —— 安全设置 ——
 	TwoFactorEnabled bool       `gorm:"default:false"` // 是否启用二次验证
@@ -38,3 +46,16 @@ type User struct {
 	// 点过的赞
 	Likes []Like `gorm:"polymorphic:Likeable;"`
 }
+
+// HasRole 判断用户是否具备任一给定角色，管理员视为具备所有角色
+func (u *User) HasRole(roles ...string) bool {
+	if u.Role == RoleAdmin {
+		return true
+	}
+	for _, r := range roles {
+		if u.Role == r {
+			return true
+		}
+	}
+	return false
+}
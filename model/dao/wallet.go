@@ -0,0 +1,56 @@
+package dao
+
+import (
+	"github.com/google/uuid"
+)
+
+// WalletOwnerType 钱包（记账账户）归属类型
+const (
+	WalletOwnerUser   = "user"   // 用户可用余额，OwnerID 为用户 ID
+	WalletOwnerEscrow = "escrow" // 悬赏令托管账户，OwnerID 为悬赏令 ID
+	WalletOwnerSystem = "system" // 平台外部资金的对手方（充值/提现），OwnerID 为 uuid.Nil
+)
+
+// LedgerTransactionType 记账凭证类型
+const (
+	LedgerTxDeposit       = "deposit"        // 充值
+	LedgerTxEscrowLock    = "escrow_lock"    // 发布悬赏时锁定赏金
+	LedgerTxEscrowRelease = "escrow_release" // 结算时将托管赏金发放给接收者
+	LedgerTxEscrowRefund  = "escrow_refund"  // 取消/过期时将托管赏金退回发布者
+	LedgerTxContribution  = "contribution"   // 第三方追加赏金：出资人 → 托管
+)
+
+// Wallet 记账账户，每个归属对象在每种货币下各有一个
+type Wallet struct {
+	BaseModel
+
+	OwnerType string    `gorm:"type:varchar(20);not null;uniqueIndex:idx_wallet_owner"`
+	OwnerID   uuid.UUID `gorm:"type:uuid;not null;uniqueIndex:idx_wallet_owner"`
+	Currency  string    `gorm:"type:varchar(10);not null;uniqueIndex:idx_wallet_owner"`
+	Balance   float64   `gorm:"type:numeric;not null;default:0"` // 冗余余额，等于该账户全部分录之和
+}
+
+// LedgerTransaction 一张记账凭证，其下所有分录金额之和必须为 0
+type LedgerTransaction struct {
+	BaseModel
+
+	Type     string     `gorm:"type:varchar(30);not null;index"`
+	BountyID *uuid.UUID `gorm:"type:uuid;index"` // 关联悬赏令（充值等为空）
+	Memo     string     `gorm:"type:text"`
+
+	Entries []LedgerEntry `gorm:"foreignKey:TransactionID;references:ID"`
+}
+
+// LedgerEntry 记账分录：正数为入账，负数为出账
+type LedgerEntry struct {
+	BaseModel
+
+	TransactionID uuid.UUID `gorm:"type:uuid;not null;index"`
+	WalletID      uuid.UUID `gorm:"type:uuid;not null;index"`
+	Amount        float64   `gorm:"type:numeric;not null"`
+	BalanceAfter  float64   `gorm:"type:numeric;not null"` // 记账后该账户余额
+
+	// —— 关联预加载 ——
+	Transaction *LedgerTransaction `gorm:"foreignKey:TransactionID;references:ID"`
+	Wallet      *Wallet            `gorm:"foreignKey:WalletID;references:ID"`
+}
@@ -0,0 +1,21 @@
+package util
+
+import "math"
+
+// EarthRadiusKm 地球平均半径（千米）
+const EarthRadiusKm = 6371.0
+
+// DistanceKm 按 haversine 公式计算两个经纬度坐标之间的球面距离（千米）
+func DistanceKm(lat1, lng1, lat2, lng2 float64) float64 {
+	rad := math.Pi / 180
+	dLat := (lat2 - lat1) * rad
+	dLng := (lng2 - lng1) * rad
+	a := math.Pow(math.Sin(dLat/2), 2) +
+		math.Cos(lat1*rad)*math.Cos(lat2*rad)*math.Pow(math.Sin(dLng/2), 2)
+	return 2 * EarthRadiusKm * math.Asin(math.Min(1, math.Sqrt(a)))
+}
+
+// ValidCoordinates 纬度在 [-90, 90]、经度在 [-180, 180] 范围内
+func ValidCoordinates(lat, lng float64) bool {
+	return lat >= -90 && lat <= 90 && lng >= -180 && lng <= 180
+}